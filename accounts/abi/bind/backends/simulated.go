@@ -66,7 +66,7 @@ type SimulatedBackend struct {
 // for testing purposes.
 func NewSimulatedBackend(alloc core.GenesisAlloc, gasLimit uint64) *SimulatedBackend {
 	database := ethdb.NewMemDatabase()
-	simConfig := &params.ChainConfig{big.NewInt(1337), nil, ""}
+	simConfig := &params.ChainConfig{big.NewInt(1337), nil, "", false}
 	genesis := core.Genesis{Config: simConfig, GasLimit: gasLimit, Alloc: alloc}
 	genesis.MustCommit(database)
 	blockchain, _, _ := core.NewBlockChain(database, nil, nil, genesis.Config, nil, vm.Config{}, nil)
@@ -425,6 +425,10 @@ func (m callmsg) SetData(b []byte)        {}
 func (m callmsg) SetTxType(src uint64)    {}
 func (m callmsg) SetNonce(n uint64)       {}
 
+func (m callmsg) ValidityWindow() *types.ValidityWindow { return nil }
+func (m callmsg) AccessList() types.AccessList          { return nil }
+func (m callmsg) FeePayer() *common.Address             { return nil }
+
 // filterBackend implements filters.Backend to support filtering for logs without
 // taking bloom-bits acceleration structures into account.
 type filterBackend struct {
@@ -6,6 +6,7 @@
 
 #include "fixedhash.hpp"
 #include "txencode.hpp"
+#include "datastream.h"
 
 #ifdef __cplusplus
 extern "C" {
@@ -16,12 +17,102 @@ extern "C" {
     int64_t bcwasmDelegateCallInt64(const uint8_t *address, const uint8_t *args, uint32_t len);
     void bcwasmCall(const uint8_t *address, const uint8_t *args, uint32_t len);
     void bcwasmDelegateCall(const uint8_t *address, const uint8_t *args, uint32_t len);
+    int32_t bcwasmCallContract(const uint8_t *address, const uint8_t *args, size_t len, uint64_t gas, const uint8_t value[32]);
+    int32_t bcwasmDelegateCallContract(const uint8_t *address, const uint8_t *args, size_t len, uint64_t gas);
+    size_t bcwasmCallOutputLength();
+    void bcwasmGetCallOutput(uint8_t *dst);
+    void bcwasmReturn(const uint8_t *data, size_t len);
+    void bcwasmRevert(const uint8_t *reason, size_t len);
 #ifdef __cplusplus
 }
 #endif
 
 
 namespace bcwasm {
+    /**
+     * @brief Stop the execution and revert all state changes, the gas left is
+     * returned to the caller together with the reason
+     * 
+     * @param reason Revert reason
+     */
+    inline void revert(const std::string &reason) {
+        ::bcwasmRevert((const uint8_t*)reason.data(), reason.size());
+    }
+
+    /**
+     * @brief Set the raw return data of the contract call, overriding the
+     * return value of the called function. Use pack() to return structs.
+     * 
+     * @param data Return data
+     */
+    inline void setReturnData(const bytes &data) {
+        ::bcwasmReturn(data.data(), data.size());
+    }
+
+    /**
+     * @brief Status of a cross-contract call
+     * 
+     */
+    enum CallStatus {
+        kCallSuccess = 0,
+        kCallReverted = 1, // the callee reverted, data holds its reason
+        kCallFailed = 2    // any other failure, data holds the error message
+    };
+
+    /**
+     * @brief Result of a cross-contract call
+     * 
+     */
+    struct CallResult {
+        int32_t status;
+        bytes data;
+
+        bool ok() const { return status == kCallSuccess; }
+        bool reverted() const { return status == kCallReverted; }
+
+        /**
+         * @brief Revert reason or error message of a failed call
+         * 
+         * @return std::string 
+         */
+        std::string reason() const {
+            return ok() ? std::string() : std::string(data.begin(), data.end());
+        }
+
+        /**
+         * @brief Return value of a function returning an integer
+         * 
+         * @return int64_t 
+         */
+        int64_t toInt64() const {
+            int64_t ret = 0;
+            for (size_t i = 0; i < data.size() && i < 8; i++) {
+                ret = (ret << 8) | data[i];
+            }
+            return ret;
+        }
+
+        /**
+         * @brief Return value of a function returning a string
+         * 
+         * @return std::string 
+         */
+        std::string toString() const {
+            return std::string(data.begin(), data.end());
+        }
+
+        /**
+         * @brief Return value set with setReturnData(pack(value))
+         * 
+         * @tparam T Returned type, serializable with BCWASM_SERIALIZE
+         * @return T 
+         */
+        template<typename T>
+        T as() const {
+            return unpack<T>((const char*)data.data(), data.size());
+        }
+    };
+
     /**
      * @brief Cross-contract call contract
      * 
@@ -144,8 +235,101 @@ namespace bcwasm {
             ::bcwasmDelegateCall(address_.data(), rlpData.data(), rlpData.size());
         }
 
+        /**
+         * @brief Limit the gas of the calls made with the returned contract,
+         * by default all but one 64th of the gas left is forwarded
+         * 
+         * @param gas Gas limit
+         * @return DeployedContract 
+         */
+        DeployedContract withGas(uint64_t gas) const {
+            DeployedContract c(*this);
+            c.gas_ = gas;
+            return c;
+        }
+
+        /**
+         * @brief Transfer value with the calls made with the returned contract
+         * 
+         * @param value Amount transferred from this contract
+         * @return DeployedContract 
+         */
+        DeployedContract withValue(u256 value) const {
+            DeployedContract c(*this);
+            c.value_ = value;
+            return c;
+        }
+
+        /**
+         * @brief Call contract specification function without aborting on
+         * failure, the state changes of a failed callee are reverted
+         * 
+         * @tparam Args Parameter template
+         * @param funcName Function name
+         * @param args Function parameters
+         * @return CallResult Status and return data of the call
+         */
+        template<typename... Args>
+        inline CallResult tryCall(const std::string &funcName, Args&&... args) const {
+            RLPStream stream(sizeof...(args) + 2);
+            txEncode(stream, kTxType, funcName, args...);
+            const bytes& rlpData = stream.out();
+            bytes value(32);
+            toBigEndian(value_, value);
+            int32_t status = ::bcwasmCallContract(address_.data(), rlpData.data(), rlpData.size(), gas_, value.data());
+            return result(status);
+        }
+
+        /**
+         * @brief Delegate call contract specification function without
+         * aborting on failure
+         * 
+         * @tparam Args Parameter template
+         * @param funcName Function name
+         * @param args Function parameters
+         * @return CallResult Status and return data of the call
+         */
+        template<typename... Args>
+        inline CallResult tryDelegateCall(const std::string &funcName, Args&&... args) const {
+            RLPStream stream(sizeof...(args) + 2);
+            txEncode(stream, kTxType, funcName, args...);
+            const bytes& rlpData = stream.out();
+            int32_t status = ::bcwasmDelegateCallContract(address_.data(), rlpData.data(), rlpData.size(), gas_);
+            return result(status);
+        }
+
+        /**
+         * @brief Call contract specification function, a failure of the
+         * callee reverts this contract with the same reason
+         * 
+         * @tparam Args Parameter template
+         * @param funcName Function name
+         * @param args Function parameters
+         * @return CallResult Status and return data of the call
+         */
+        template<typename... Args>
+        inline CallResult callOrRevert(const std::string &funcName, Args&&... args) const {
+            CallResult res = tryCall(funcName, args...);
+            if (!res.ok()) {
+                revert(res.reason());
+            }
+            return res;
+        }
+
     private:
+        CallResult result(int32_t status) const {
+            CallResult res;
+            res.status = status;
+            res.data.resize(::bcwasmCallOutputLength());
+            if (!res.data.empty()) {
+                ::bcwasmGetCallOutput(res.data.data());
+            }
+            return res;
+        }
+
         const int64_t kTxType = 9;
         Address address_;
+        uint64_t gas_ = 0;
+        u256 value_ = 0;
     };
 }
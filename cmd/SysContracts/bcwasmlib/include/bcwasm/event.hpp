@@ -3,6 +3,8 @@
 #include "print.hpp"
 #include "common.h"
 #include "RLP.h"
+#include "state.hpp"
+#include "assert.h"
 
 #define ARG_COUNT_P1_(\
   _1, _2, _3, _4, _5, _6, _7, _8, _9, _10, N, ...) \
@@ -70,6 +72,7 @@
 extern "C" {
 #endif
     void emitEvent(const char *topic, size_t topicLen, const uint8_t *data, size_t dataLen);
+    void emitEventIndexed(const char *name, size_t nameLen, const uint8_t *topics, size_t topicsCount, const uint8_t *data, size_t dataLen);
 #ifdef __cplusplus
 }
 #endif
@@ -235,4 +238,87 @@ namespace bcwasm {
         const bytes& rlpData = stream.out();
         ::emitEvent(topic.data(), topic.length(),rlpData.data(), rlpData.size());
     }
+
+    /**
+     * @brief Maximum number of indexed event parameters
+     * 
+     */
+    const size_t kMaxIndexedParams = 3;
+
+    /**
+     * @brief Topic of an indexed unsigned integer, a 32 bytes big endian word
+     * 
+     * @param num Value
+     * @return h256 
+     */
+    h256 eventTopic(uint64_t num) {
+        h256 topic;
+        for (size_t i = 0; i < 8; i++) {
+            topic.data()[31 - i] = (byte)(num >> (8 * i));
+        }
+        return topic;
+    }
+
+    /**
+     * @brief Topic of an indexed signed integer, sign extended to 32 bytes
+     * 
+     * @param num Value
+     * @return h256 
+     */
+    h256 eventTopic(int64_t num) {
+        h256 topic = eventTopic((uint64_t)num);
+        if (num < 0) {
+            memset(topic.data(), 0xff, 24);
+        }
+        return topic;
+    }
+
+    h256 eventTopic(uint32_t num) { return eventTopic((uint64_t)num); }
+    h256 eventTopic(int32_t num) { return eventTopic((int64_t)num); }
+    h256 eventTopic(bool b) { return eventTopic((uint64_t)(b ? 1 : 0)); }
+
+    /**
+     * @brief Topic of an indexed address, left padded to 32 bytes
+     * 
+     * @param addr Address
+     * @return h256 
+     */
+    h256 eventTopic(const Address &addr) {
+        h256 topic;
+        memcpy(topic.data() + 12, addr.data(), 20);
+        return topic;
+    }
+
+    /**
+     * @brief Topic of an indexed string, the Keccak-256 hash of its content
+     * 
+     * @param s String
+     * @return h256 
+     */
+    h256 eventTopic(const std::string &s) {
+        return sha3(s);
+    }
+
+    h256 eventTopic(const char *s) { return sha3(std::string(s)); }
+
+    /**
+     * @brief Emit an event with indexed parameters
+     * 
+     * @tparam Args Event parameter type
+     * @param name Event name, hashed into the first topic
+     * @param topics Topics of the indexed parameters, see eventTopic
+     * @param args Non indexed event parameters
+     */
+    template<typename... Args>
+    void emitEventIndexed(const std::string &name, const std::vector<h256> &topics, Args &&... args) {
+        BCWasmAssert(topics.size() <= kMaxIndexedParams, "too many indexed event parameters");
+        bytes packed;
+        for (const h256 &topic : topics) {
+            packed.insert(packed.end(), topic.data(), topic.data() + 32);
+        }
+        RLPStream stream(sizeof...(args));
+        event(stream, args...);
+        const bytes& rlpData = stream.out();
+        ::emitEventIndexed(name.data(), name.length(), packed.data(), topics.size(), rlpData.data(), rlpData.size());
+    }
 }
@@ -31,12 +31,12 @@ import (
 	"github.com/PlatONEnetwork/PlatONE-Go/core"
 	"github.com/PlatONEnetwork/PlatONE-Go/core/state"
 	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/eth"
 	"github.com/PlatONEnetwork/PlatONE-Go/eth/downloader"
 	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
 	"github.com/PlatONEnetwork/PlatONE-Go/event"
 	"github.com/PlatONEnetwork/PlatONE-Go/log"
 	"github.com/PlatONEnetwork/PlatONE-Go/trie"
-	"github.com/syndtr/goleveldb/leveldb/util"
 	"gopkg.in/urfave/cli.v1"
 )
 
@@ -76,7 +76,10 @@ The import command imports blocks from an RLP-encoded form. The form can be one
 with several RLP-encoded blocks, or several files can be used.
 
 If only one file is used, import error will result in failure. If several files are used,
-processing will proceed even if an individual RLP-file import failure occurs.`,
+processing will proceed even if an individual RLP-file import failure occurs.
+
+A directory argument is read as the ancient block store of another node, whose
+blocks are streamed from instead.`,
 	}
 	exportCommand = cli.Command{
 		Action:    utils.MigrateFlags(exportChain),
@@ -123,6 +126,48 @@ be gzipped.`,
 		Category: "BLOCKCHAIN COMMANDS",
 		Description: `
 The export-preimages command export hash preimages to an RLP encoded stream`,
+	}
+	exportCheckpointCommand = cli.Command{
+		Action:    utils.MigrateFlags(exportCheckpoint),
+		Name:      "export-checkpoint",
+		Usage:     "Export the state of a block into a checkpoint file",
+		ArgsUsage: "<filename> <blockNum>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.CacheFlag,
+			utils.SyncModeFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The export-checkpoint command writes the canonical block of the given number,
+its whole state and the header of the next block, whose commit seals finalize
+it, to a file. The state of the block must be stored, which an archive node
+ensures for any block. If the file ends with .gz, the output will be gzipped.`,
+	}
+	importCheckpointCommand = cli.Command{
+		Action:    utils.MigrateFlags(importCheckpoint),
+		Name:      "import-checkpoint",
+		Usage:     "Bootstrap a new node from a checkpoint file",
+		ArgsUsage: "<filename>",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.CacheFlag,
+			utils.SyncModeFlag,
+			utils.GCModeFlag,
+			utils.CheckpointHashFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The import-checkpoint command makes the block of a checkpoint file the head of
+a node initialized with the genesis block of the chain, for the node to sync
+the blocks after it instead of the whole chain.
+
+The checkpoint block must be the one of the hash given with --checkpoint.hash,
+obtained from an existing member of the chain. The state is checked against the
+state root of the block, and the commit seals of the next block against the
+validators of the node registry in the state, before the block is made the
+head. The blocks before it are not downloaded, and the ancient block store is
+disabled on such a node.`,
 	}
 	copydbCommand = cli.Command{
 		Action:    utils.MigrateFlags(copyDb),
@@ -150,6 +195,23 @@ The first argument must be the directory containing the blockchain to download f
 		Category: "BLOCKCHAIN COMMANDS",
 		Description: `
 Remove blockchain and state databases`,
+	}
+	migratedbCommand = cli.Command{
+		Action:    utils.MigrateFlags(migrateDB),
+		Name:      "migratedb",
+		Usage:     "Migrate the databases to another storage engine",
+		ArgsUsage: " ",
+		Flags: []cli.Flag{
+			utils.DataDirFlag,
+			utils.DatabaseEngineFlag,
+			utils.CacheFlag,
+		},
+		Category: "BLOCKCHAIN COMMANDS",
+		Description: `
+The migratedb command copies the chain databases of the data directory to the
+storage engine selected by --db.engine. The original databases are kept next
+to the migrated ones, suffixed by their engine, and can be removed once the
+node runs fine.`,
 	}
 	dumpCommand = cli.Command{
 		Action:    utils.MigrateFlags(dump),
@@ -244,19 +306,7 @@ func importChain(ctx *cli.Context) error {
 	fmt.Printf("Import done in %v.\n\n", time.Since(start))
 
 	// Output pre-compaction stats mostly to see the import trashing
-	db := chainDb.(*ethdb.LDBDatabase)
-
-	stats, err := db.LDB().GetProperty("leveldb.stats")
-	if err != nil {
-		utils.Fatalf("Failed to read database stats: %v", err)
-	}
-	fmt.Println(stats)
-
-	ioStats, err := db.LDB().GetProperty("leveldb.iostats")
-	if err != nil {
-		utils.Fatalf("Failed to read database iostats: %v", err)
-	}
-	fmt.Println(ioStats)
+	printDatabaseStats(chainDb)
 
 	fmt.Printf("Trie cache misses:  %d\n", trie.CacheMisses())
 	fmt.Printf("Trie cache unloads: %d\n\n", trie.CacheUnloads())
@@ -275,26 +325,44 @@ func importChain(ctx *cli.Context) error {
 	}
 
 	// Compact the entire database to more accurately measure disk io and print the stats
-	start = time.Now()
-	fmt.Println("Compacting entire database...")
-	if err = db.LDB().CompactRange(util.Range{}); err != nil {
-		utils.Fatalf("Compaction failed: %v", err)
-	}
-	fmt.Printf("Compaction done in %v.\n\n", time.Since(start))
+	compactDatabase(chainDb)
+	printDatabaseStats(chainDb)
 
-	stats, err = db.LDB().GetProperty("leveldb.stats")
+	return nil
+}
+
+// printDatabaseStats prints the internal statistics of the LevelDB engine,
+// the other engines have none to report.
+func printDatabaseStats(db ethdb.Database) {
+	ldb, ok := db.(*ethdb.LDBDatabase)
+	if !ok {
+		return
+	}
+	stats, err := ldb.LDB().GetProperty("leveldb.stats")
 	if err != nil {
 		utils.Fatalf("Failed to read database stats: %v", err)
 	}
 	fmt.Println(stats)
 
-	ioStats, err = db.LDB().GetProperty("leveldb.iostats")
+	ioStats, err := ldb.LDB().GetProperty("leveldb.iostats")
 	if err != nil {
 		utils.Fatalf("Failed to read database iostats: %v", err)
 	}
 	fmt.Println(ioStats)
+}
 
-	return nil
+// compactDatabase compacts the entire database if its engine supports it.
+func compactDatabase(db ethdb.Database) {
+	compacter, ok := db.(ethdb.Compacter)
+	if !ok {
+		return
+	}
+	start := time.Now()
+	fmt.Println("Compacting entire database...")
+	if err := compacter.Compact(nil, nil); err != nil {
+		utils.Fatalf("Compaction failed: %v", err)
+	}
+	fmt.Printf("Compaction done in %v.\n\n", time.Since(start))
 }
 
 func exportChain(ctx *cli.Context) error {
@@ -329,13 +397,70 @@ func exportChain(ctx *cli.Context) error {
 	return nil
 }
 
+// exportCheckpoint exports the state of a block into the specified file.
+func exportCheckpoint(ctx *cli.Context) error {
+	if len(ctx.Args()) < 2 {
+		utils.Fatalf("This command requires two arguments.")
+	}
+	number, err := strconv.ParseUint(ctx.Args().Get(1), 10, 64)
+	if err != nil {
+		utils.Fatalf("Export error in parsing parameters: block number not an integer\n")
+	}
+	stack := makeFullNode(ctx)
+	chain, db := utils.MakeChain(ctx, stack)
+	defer db.Close()
+	defer chain.Stop()
+
+	start := time.Now()
+	if err := utils.ExportCheckpoint(chain, ctx.Args().First(), number); err != nil {
+		utils.Fatalf("Export error: %v\n", err)
+	}
+	fmt.Printf("Export done in %v\n", time.Since(start))
+	return nil
+}
+
+// importCheckpoint bootstraps the chain of a new node from the specified
+// checkpoint file.
+func importCheckpoint(ctx *cli.Context) error {
+	if len(ctx.Args()) < 1 {
+		utils.Fatalf("This command requires an argument.")
+	}
+	trusted := ctx.GlobalString(utils.CheckpointHashFlag.Name)
+	if len(common.FromHex(trusted)) != common.HashLength {
+		utils.Fatalf("Import error: --%s must be the hash of the checkpoint block", utils.CheckpointHashFlag.Name)
+	}
+	stack := makeFullNode(ctx)
+	chain, db := utils.MakeChain(ctx, stack)
+	if head := chain.CurrentBlock(); head.NumberU64() != 0 {
+		utils.Fatalf("Import error: chain at block %d, checkpoints are imported in new databases", head.NumberU64())
+	}
+	chain.Stop()
+
+	start := time.Now()
+	cp, err := utils.ImportCheckpoint(db, ctx.Args().First(), common.HexToHash(trusted))
+	db.Close()
+	if err != nil {
+		utils.Fatalf("Import error: %v", err)
+	}
+	// Reopen the chain with the checkpoint block for the engine to verify it
+	chain, db = utils.MakeChain(ctx, stack)
+	defer db.Close()
+	defer chain.Stop()
+
+	if err := chain.VerifyCheckpoint(cp); err != nil {
+		utils.Fatalf("Checkpoint verification failed: %v", err)
+	}
+	fmt.Printf("Imported checkpoint at block %d (%x) in %v\n", cp.Block.NumberU64(), cp.Block.Hash(), time.Since(start))
+	return nil
+}
+
 // importPreimages imports preimage data from the specified file.
 func importPreimages(ctx *cli.Context) error {
 	if len(ctx.Args()) < 1 {
 		utils.Fatalf("This command requires an argument.")
 	}
 	stack := makeFullNode(ctx)
-	diskdb := utils.MakeChainDatabase(ctx, stack).(*ethdb.LDBDatabase)
+	diskdb := utils.MakeChainDatabase(ctx, stack)
 
 	start := time.Now()
 	if err := utils.ImportPreimages(diskdb, ctx.Args().First()); err != nil {
@@ -351,7 +476,7 @@ func exportPreimages(ctx *cli.Context) error {
 		utils.Fatalf("This command requires an argument.")
 	}
 	stack := makeFullNode(ctx)
-	diskdb := utils.MakeChainDatabase(ctx, stack).(*ethdb.LDBDatabase)
+	diskdb := utils.MakeChainDatabase(ctx, stack).(ethdb.Iteratee)
 
 	start := time.Now()
 	if err := utils.ExportPreimages(diskdb, ctx.Args().First()); err != nil {
@@ -374,7 +499,7 @@ func copyDb(ctx *cli.Context) error {
 	dl := downloader.New(syncmode, chainDb, new(event.TypeMux), chain, nil, nil)
 
 	// Create a source peer to satisfy downloader requests from
-	db, err := ethdb.NewLDBDatabase(ctx.Args().First(), ctx.GlobalInt(utils.CacheFlag.Name), 256)
+	db, err := ethdb.Open("", ctx.Args().First(), ctx.GlobalInt(utils.CacheFlag.Name), 256)
 	if err != nil {
 		return err
 	}
@@ -399,12 +524,7 @@ func copyDb(ctx *cli.Context) error {
 	fmt.Printf("Database copy done in %v\n", time.Since(start))
 
 	// Compact the entire database to remove any sync overhead
-	start = time.Now()
-	fmt.Println("Compacting entire database...")
-	if err = chainDb.(*ethdb.LDBDatabase).LDB().CompactRange(util.Range{}); err != nil {
-		utils.Fatalf("Compaction failed: %v", err)
-	}
-	fmt.Printf("Compaction done in %v.\n\n", time.Since(start))
+	compactDatabase(chainDb)
 
 	return nil
 }
@@ -412,7 +532,7 @@ func copyDb(ctx *cli.Context) error {
 func removeDB(ctx *cli.Context) error {
 	stack, _ := makeConfigNode(ctx)
 
-	for _, name := range []string{"chaindata", "lightchaindata"} {
+	for _, name := range []string{"chaindata", eth.AncientDir, "lightchaindata"} {
 		// Ensure the database exists in the first place
 		logger := log.New("database", name)
 
@@ -438,6 +558,81 @@ func removeDB(ctx *cli.Context) error {
 	return nil
 }
 
+func migrateDB(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+
+	engine := ctx.GlobalString(utils.DatabaseEngineFlag.Name)
+	if !ethdb.IsEngine(engine) {
+		utils.Fatalf("Unknown database engine %q", engine)
+	}
+	for _, name := range []string{"chaindata", "lightchaindata", "extdb"} {
+		logger := log.New("database", name)
+
+		dbdir := stack.ResolvePath(name)
+		current := ethdb.DetectEngine(dbdir)
+		switch current {
+		case "":
+			logger.Info("Database doesn't exist, skipping", "path", dbdir)
+			continue
+		case engine:
+			logger.Info("Database already migrated, skipping", "engine", engine)
+			continue
+		}
+		if err := migrateDatabase(dbdir, current, engine, ctx.GlobalInt(utils.CacheFlag.Name)); err != nil {
+			utils.Fatalf("Failed to migrate %s: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// migrateDatabase copies the database in dbdir stored with engine from to a
+// new one stored with engine to, which then replaces it. The original one is
+// moved aside, suffixed by its engine.
+func migrateDatabase(dbdir string, from, to string, cache int) error {
+	var (
+		tmpdir = dbdir + ".migrating"
+		olddir = dbdir + "." + from
+		logger = log.New("database", dbdir)
+	)
+	if common.FileExist(olddir) {
+		return fmt.Errorf("backup directory %s already exists", olddir)
+	}
+	// Drop any leftover of an interrupted migration
+	if err := os.RemoveAll(tmpdir); err != nil {
+		return err
+	}
+	src, err := ethdb.Open(from, dbdir, cache/2, 256)
+	if err != nil {
+		return err
+	}
+	dst, err := ethdb.Open(to, tmpdir, cache/2, 256)
+	if err != nil {
+		src.Close()
+		return err
+	}
+	logger.Info("Migrating database", "from", from, "to", to)
+	start, logged := time.Now(), time.Now()
+	entries, err := ethdb.Copy(dst, src.(ethdb.Iteratee), func(entries int) {
+		if time.Since(logged) > 8*time.Second {
+			logger.Info("Migrating database", "entries", entries, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	})
+	src.Close()
+	dst.Close()
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(dbdir, olddir); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpdir, dbdir); err != nil {
+		return err
+	}
+	logger.Info("Migrated database", "entries", entries, "backup", olddir, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
 func dump(ctx *cli.Context) error {
 	stack := makeFullNode(ctx)
 	chain, chainDb := utils.MakeChain(ctx, stack)
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/cmd/utils"
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/rawdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/eth"
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/log"
+	"github.com/PlatONEnetwork/PlatONE-Go/node"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	dbCommand = cli.Command{
+		Name:     "db",
+		Usage:    "Low level database operations",
+		Category: "BLOCKCHAIN COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Name:   "inspect",
+				Usage:  "Show the space used by each kind of data",
+				Action: utils.MigrateFlags(inspectDB),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.CacheFlag,
+				},
+				Description: `
+platone db inspect
+
+Counts the entries of the databases of a stopped node by kind of data and sums
+their sizes, keys included: the chain database, the ancient block store and the
+monitoring entries of the extended database.`,
+			},
+			{
+				Name:   "verify",
+				Usage:  "Check the integrity of the canonical chain",
+				Action: utils.MigrateFlags(verifyDB),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.CacheFlag,
+				},
+				Description: `
+platone db verify
+
+Walks the canonical chain of a stopped node from its genesis block, or from the
+checkpoint it was bootstrapped from, to its head block. Checks that the headers
+link up, that the bodies and the receipts not pruned match the roots of their
+headers and that the state of the head block is present. The state of older
+blocks is only counted, being pruned by design.`,
+			},
+			{
+				Name:   "compact",
+				Usage:  "Compact the chain and extended databases",
+				Action: utils.MigrateFlags(compactDB),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.CacheFlag,
+				},
+				Description: `
+platone db compact
+
+Compacts the entire chain and extended databases of a stopped node, reclaiming
+the space of the deleted and overwritten entries.`,
+			},
+		},
+	}
+)
+
+// monitorKeyPrefix is the prefix of the keys of the monitoring entries of the
+// extended database, a transaction hash followed by the monitor type.
+var monitorKeyPrefix = []byte("0x")
+
+func inspectDB(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	chainDb := utils.MakeChainDatabase(ctx, stack)
+	defer chainDb.Close()
+
+	iteratee, ok := chainDb.(ethdb.Iteratee)
+	if !ok {
+		utils.Fatalf("Chain database unable to iterate")
+	}
+	start := time.Now()
+	stats, err := rawdb.InspectDatabase(iteratee)
+	if err != nil {
+		utils.Fatalf("Failed to inspect chain database: %v", err)
+	}
+	var total common.StorageSize
+	fmt.Println("Chain database:")
+	for _, stat := range stats {
+		fmt.Printf("  %-28s %12d %12v\n", stat.Name, stat.Count, stat.Size)
+		total += stat.Size
+	}
+	fmt.Printf("  %-28s %12s %12v\n\n", "Total", "", total)
+
+	fmt.Printf("Ancient block store: %v\n", dirSize(stack.ResolvePath(eth.AncientDir)))
+
+	if extDb := openExtDB(stack); extDb != nil {
+		iteratee, ok := extDb.(ethdb.Iteratee)
+		if !ok {
+			utils.Fatalf("Extended database unable to iterate")
+		}
+		var monitor, other entryCounter
+		it := iteratee.NewIteratorWithPrefix(nil)
+		for it.Next() {
+			if key := it.Key(); bytes.HasPrefix(key, monitorKeyPrefix) && len(key) > 2+2*common.HashLength {
+				monitor.add(key, it.Value())
+			} else {
+				other.add(key, it.Value())
+			}
+		}
+		err := it.Error()
+		it.Release()
+		extDb.Close()
+		if err != nil {
+			utils.Fatalf("Failed to inspect extended database: %v", err)
+		}
+		fmt.Println("Extended database:")
+		fmt.Printf("  %-28s %12d %12v\n", "Monitoring entries", monitor.count, monitor.size)
+		fmt.Printf("  %-28s %12d %12v\n", "Other", other.count, other.size)
+	}
+
+	log.Info("Inspected databases", "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// entryCounter counts entries and sums their sizes.
+type entryCounter struct {
+	count uint64
+	size  common.StorageSize
+}
+
+func (c *entryCounter) add(key, value []byte) {
+	c.count++
+	c.size += common.StorageSize(len(key) + len(value))
+}
+
+// dirSize returns the total size of the files under dir, zero if missing.
+func dirSize(dir string) common.StorageSize {
+	var size int64
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return common.StorageSize(size)
+}
+
+// openExtDB opens the extended database of the node, nil if it doesn't exist.
+func openExtDB(stack *node.Node) ethdb.Database {
+	if ethdb.DetectEngine(stack.ResolvePath("extdb")) == "" {
+		return nil
+	}
+	db, err := stack.OpenDatabase("extdb", 16, 16)
+	if err != nil {
+		utils.Fatalf("Could not open extended database: %v", err)
+	}
+	return db
+}
+
+func verifyDB(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	chainDb := utils.MakeChainDatabase(ctx, stack)
+	defer chainDb.Close()
+
+	head := rawdb.ReadHeaderNumber(chainDb, rawdb.ReadHeadBlockHash(chainDb))
+	if head == nil {
+		utils.Fatalf("No head block in the database")
+	}
+	// The blocks before the checkpoint a chain was bootstrapped from are missing
+	var first uint64
+	if hash := rawdb.ReadCheckpointHash(chainDb); hash != (common.Hash{}) {
+		if number := rawdb.ReadHeaderNumber(chainDb, hash); number != nil {
+			first = *number
+		}
+	}
+	var (
+		tail     = rawdb.ReadReceiptsTail(chainDb)
+		start    = time.Now()
+		logged   = time.Now()
+		problems int
+		states   int
+		parent   common.Hash
+		root     common.Hash
+	)
+	report := func(number uint64, msg string, ctx ...interface{}) {
+		problems++
+		log.Error(msg, append([]interface{}{"number", number}, ctx...)...)
+	}
+	log.Info("Verifying chain", "first", first, "head", *head, "receipts", tail)
+	for number := first; number <= *head; number++ {
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Verifying chain", "number", number, "head", *head, "problems", problems, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+		hash := rawdb.ReadCanonicalHash(chainDb, number)
+		if hash == (common.Hash{}) {
+			report(number, "Canonical hash missing")
+			parent = common.Hash{}
+			continue
+		}
+		header := rawdb.ReadHeader(chainDb, hash, number)
+		switch {
+		case header == nil:
+			report(number, "Header missing", "hash", hash)
+			parent = common.Hash{}
+			continue
+		case header.Hash() != hash:
+			report(number, "Header hash mismatch", "hash", hash, "have", header.Hash())
+		case header.Number.Uint64() != number:
+			report(number, "Header number mismatch", "have", header.Number)
+		case number > first && parent != (common.Hash{}) && header.ParentHash != parent:
+			report(number, "Header not linked to its parent", "parent", header.ParentHash, "want", parent)
+		}
+		if stored := rawdb.ReadHeaderNumber(chainDb, hash); stored == nil || *stored != number {
+			report(number, "Header number entry missing or mismatched", "hash", hash)
+		}
+		parent, root = hash, header.Root
+
+		body := rawdb.ReadBody(chainDb, hash, number)
+		if body == nil {
+			report(number, "Body missing", "hash", hash)
+			continue
+		}
+		txs := types.Transactions(body.Transactions)
+		if header.TxHash != types.DeriveShaWith(txs, true) && header.TxHash != types.DeriveShaWith(txs, false) {
+			report(number, "Transactions mismatch the header", "hash", hash)
+		}
+		if number >= tail {
+			receipts := rawdb.ReadReceipts(chainDb, hash, number)
+			if receipts == nil && len(txs) > 0 {
+				report(number, "Receipts missing", "hash", hash)
+			} else if header.ReceiptHash != types.DeriveShaWith(receipts, true) && header.ReceiptHash != types.DeriveShaWith(receipts, false) {
+				report(number, "Receipts mismatch the header", "hash", hash)
+			}
+		}
+		if ok, _ := chainDb.Has(header.Root.Bytes()); ok || header.Root == types.EmptyRootHash {
+			states++
+		}
+	}
+	if ok, _ := chainDb.Has(root.Bytes()); !ok && root != types.EmptyRootHash {
+		report(*head, "State of the head block missing", "root", root)
+	}
+	log.Info("Verified chain", "blocks", *head-first+1, "states", states, "problems", problems, "elapsed", common.PrettyDuration(time.Since(start)))
+	if problems > 0 {
+		utils.Fatalf("Found %d problems in the database", problems)
+	}
+	return nil
+}
+
+func compactDB(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+
+	chainDb := utils.MakeChainDatabase(ctx, stack)
+	compactDatabase(chainDb)
+	printDatabaseStats(chainDb)
+	chainDb.Close()
+
+	if extDb := openExtDB(stack); extDb != nil {
+		compactDatabase(extDb)
+		printDatabaseStats(extDb)
+		extDb.Close()
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"github.com/PlatONEnetwork/PlatONE-Go/cmd/utils"
+	istanbulBackend "github.com/PlatONEnetwork/PlatONE-Go/consensus/istanbul/backend"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/rawdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/state/pruner"
+	"github.com/PlatONEnetwork/PlatONE-Go/log"
+	"gopkg.in/urfave/cli.v1"
+)
+
+var (
+	snapshotCommand = cli.Command{
+		Name:     "snapshot",
+		Usage:    "A set of commands based on the block states",
+		Category: "BLOCKCHAIN COMMANDS",
+		Subcommands: []cli.Command{
+			{
+				Name:   "prune-state",
+				Usage:  "Delete the state data unreachable from the recent block states and the checkpoints",
+				Action: utils.MigrateFlags(pruneState),
+				Flags: []cli.Flag{
+					utils.DataDirFlag,
+					utils.CacheFlag,
+					utils.CacheDatabaseFlag,
+					utils.StateHistoryFlag,
+					utils.StateCheckpointFlag,
+				},
+				Description: `
+platone snapshot prune-state
+
+Deletes from the database of a stopped node the trie nodes, contract codes and
+ABIs unreachable from the states of the last --state.history blocks and of the
+blocks at multiples of --state.checkpoint. Only the states written to disk are
+kept: run the node with the same --state.checkpoint for the checkpoint states
+to be there.
+
+The command refuses to prune the states after the latest finalized Istanbul
+checkpoint, whose vote snapshot is stored every 1024 blocks: the history must
+reach back to it.`,
+			},
+		},
+	}
+)
+
+// pruneState deletes the state data of the chain database not needed by the
+// kept block states.
+func pruneState(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	chainDb := utils.MakeChainDatabase(ctx, stack)
+	defer chainDb.Close()
+
+	head := rawdb.ReadHeaderNumber(chainDb, rawdb.ReadHeadBlockHash(chainDb))
+	if head == nil {
+		utils.Fatalf("No head block in the database")
+	}
+	config := pruner.Config{
+		History:    ctx.GlobalUint64(utils.StateHistoryFlag.Name),
+		Checkpoint: ctx.GlobalUint64(utils.StateCheckpointFlag.Name),
+		Finalized:  istanbulBackend.LatestCheckpoint(chainDb, *head),
+	}
+	log.Info("Pruning state", "head", *head, "history", config.History, "checkpoint", config.Checkpoint, "finalized", config.Finalized)
+	if err := pruner.New(chainDb, config).Prune(); err != nil {
+		utils.Fatalf("Failed to prune state: %v", err)
+	}
+	return nil
+}
@@ -69,11 +69,16 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			configFileFlag,
 			utils.DataDirFlag,
+			utils.DatabaseEngineFlag,
 			utils.KeyStoreDirFlag,
 			utils.NoUSBFlag,
 			utils.NetworkIdFlag,
 			utils.SyncModeFlag,
 			utils.GCModeFlag,
+			utils.StateCheckpointFlag,
+			utils.AncientThresholdFlag,
+			utils.ReceiptRetentionFlag,
+			utils.ReceiptArchiveFlag,
 			utils.EthStatsURLFlag,
 			utils.IdentityFlag,
 			utils.LightServFlag,
@@ -98,6 +103,8 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.TxPoolNoLocalsFlag,
 			utils.TxPoolJournalFlag,
 			utils.TxPoolRejournalFlag,
+			utils.TxPoolPersistFlag,
+			utils.TxPoolLifecycleFlag,
 			utils.TxPoolPriceLimitFlag,
 			utils.TxPoolPriceBumpFlag,
 			utils.TxPoolAccountSlotsFlag,
@@ -105,6 +112,8 @@ var AppHelpFlagGroups = []flagGroup{
 			utils.TxPoolAccountQueueFlag,
 			utils.TxPoolGlobalQueueFlag,
 			utils.TxPoolGlobalTxCountFlag,
+			utils.TxPoolAdminsFlag,
+			utils.TxPoolAccountTxCountFlag,
 			utils.TxPoolLifetimeFlag,
 		},
 	},
@@ -89,10 +89,13 @@ should be stored in the ./abi with the name of its address`,
 		Usage:     "Get the transaction receipt by transaction hash",
 		ArgsUsage: "<tx hash>",
 		Action:    contractReceipt,
+		Flags:     contractReceiptCmdFlags,
 		Description: `
 		platonecli contract receipt <tx hash>
 
-Get the full information of the transaction receipt by transaction hash`,
+Get the full information of the transaction receipt by transaction hash
+The events emitted by a wasm contract are decoded with the --abi file, or
+the abi file stored in ./abi with the name of the contract address`,
 	}
 )
 
@@ -117,6 +120,36 @@ func contractReceipt(c *cli.Context) {
 		resultBytes, _ := json.MarshalIndent(result, "", "\t")
 		fmt.Printf("result:\n%s\n", resultBytes)
 	}
+
+	if result != nil {
+		printReceiptEvents(c, result)
+	}
+}
+
+// printReceiptEvents prints the events of the receipt declared in the abi of the contract
+func printReceiptEvents(c *cli.Context, receipt *packet.Receipt) {
+	contract := receipt.To
+	if receipt.ContractAddress != "" {
+		contract = receipt.ContractAddress
+	}
+
+	abiPath := c.String(ContractAbiFilePathFlag.Name)
+	if abiPath == "" && contract != "" {
+		abiPath = getAbiFile(contract)
+	}
+	if abiPath == "" {
+		return
+	}
+
+	abiBytes := cmd_common.ParamParse(abiPath, "abi").([]byte)
+	events, err := receipt.Logs.DecodeWasmEvents(contract, abiBytes)
+	if err != nil {
+		utils.Fatalf("decode events failed: %s\n", err.Error())
+	}
+	if len(events) != 0 {
+		eventsBytes, _ := json.MarshalIndent(events, "", "\t")
+		fmt.Printf("events:\n%s\n", eventsBytes)
+	}
 }
 
 // deploy a contract
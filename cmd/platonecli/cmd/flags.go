@@ -361,7 +361,8 @@ var (
 		ContractVmFlags,
 		TransferValueFlag,
 		ShowContractMethodsFlag)
-	contractMethodsCmd = append([]cli.Flag{}, ContractAbiFilePathFlag)
+	contractMethodsCmd      = append([]cli.Flag{}, ContractAbiFilePathFlag)
+	contractReceiptCmdFlags = append([]cli.Flag{}, UrlFlags, ContractAbiFilePathFlag)
 
 	// cns
 	cnsResolveCmdFlags = append(globalCmdFlags, CnsVersionFlags)
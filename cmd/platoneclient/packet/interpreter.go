@@ -188,6 +188,7 @@ func (i EvmContractInterpreter) ReceiptParsing(receipt *Receipt, abiBytes []byte
 	}
 
 	recpParsing.Status = receiptStatusReturn(receipt.Status)
+	recpParsing.RevertReason = receipt.RevertReason
 	recpParsing.BlockNumber, _ = hexutil.DecodeUint64(receipt.BlockNumber)
 
 	return recpParsing
@@ -305,6 +306,7 @@ func (i WasmContractInterpreter) ReceiptParsing(receipt *Receipt, abiBytes []byt
 	}
 
 	recpParsing.Status = receiptStatusReturn(receipt.Status)
+	recpParsing.RevertReason = receipt.RevertReason
 	recpParsing.BlockNumber, _ = hexutil.DecodeUint64(receipt.BlockNumber)
 
 	return recpParsing
@@ -373,6 +375,7 @@ func (i EvmDeployInterpreter) ReceiptParsing(receipt *Receipt, abiBytes []byte)
 	}
 
 	recpParsing.Status = receiptStatusReturn(receipt.Status)
+	recpParsing.RevertReason = receipt.RevertReason
 
 	return recpParsing
 }
@@ -427,6 +430,7 @@ func (i WasmDeployInterpreter) ReceiptParsing(receipt *Receipt, abiBytes []byte)
 	}
 
 	recpParsing.Status = receiptStatusReturn(receipt.Status)
+	recpParsing.RevertReason = receipt.RevertReason
 
 	return recpParsing
 }
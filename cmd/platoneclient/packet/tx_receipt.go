@@ -17,6 +17,7 @@ import (
 	"github.com/PlatONEnetwork/PlatONE-Go/common/byteutil"
 	"github.com/PlatONEnetwork/PlatONE-Go/common/hexutil"
 	"github.com/PlatONEnetwork/PlatONE-Go/crypto"
+	lifeutils "github.com/PlatONEnetwork/PlatONE-Go/life/utils"
 	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
 )
 
@@ -39,6 +40,7 @@ type ReceiptParsingReturn struct {
 	From            string
 	To              string
 	TxHash          string
+	RevertReason    string `json:"revertReason,omitempty"`
 	Err             string `json:"err,omitempty"`
 }
 
@@ -65,7 +67,8 @@ type Receipt struct {
 	TransactionHash   string    `json:"transactionHash"`  // the hash of the transaction
 	TransactionIndex  string    `json:"transactionIndex"` // the index of the transaction
 	Logs              RecptLogs `json:"logs"`
-	Status            string    `json:"status"` // the execution status of the transaction, "0x1" for success
+	Status            string    `json:"status"`                 // the execution status of the transaction, "0x1" for success
+	RevertReason      string    `json:"revertReason,omitempty"` // why the transaction or a system contract function it called failed
 }
 
 type Log struct {
@@ -76,6 +79,39 @@ type Log struct {
 
 type RecptLogs []*Log
 
+// DecodeWasmEvents decodes the logs emitted by the wasm contract with the
+// abi file abiBytes, the logs of other contracts and undeclared events are skipped.
+func (logs RecptLogs) DecodeWasmEvents(contract string, abiBytes []byte) ([]*lifeutils.DecodedEvent, error) {
+	var wasmAbi lifeutils.WasmAbi
+	if err := wasmAbi.FromJson(abiBytes); err != nil {
+		return nil, err
+	}
+
+	var events []*lifeutils.DecodedEvent
+	for _, log := range logs {
+		if !strings.EqualFold(log.Address, contract) || len(log.Topics) == 0 {
+			continue
+		}
+		topics := make([]common.Hash, len(log.Topics))
+		for i, topic := range log.Topics {
+			topics[i] = common.HexToHash(topic)
+		}
+		if _, ok := wasmAbi.EventByTopic(topics[0]); !ok {
+			continue
+		}
+		data, err := hexutil.Decode(log.Data)
+		if err != nil {
+			return nil, err
+		}
+		event, err := wasmAbi.DecodeEvent(topics, data)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
 // ParseSysContractResult parsed the rpc response to Receipt object
 func ParseTxReceipt(response interface{}) (*Receipt, error) {
 	var receipt = &Receipt{}
@@ -102,6 +138,7 @@ func (receipt *Receipt) Parsing() *ReceiptParsingReturn {
 	var recpParsing = new(ReceiptParsingReturn)
 
 	recpParsing.Status = receiptStatusReturn(receipt.Status)
+	recpParsing.RevertReason = receipt.RevertReason
 	recpParsing.BlockNumber, _ = hexutil.DecodeUint64(receipt.BlockNumber)
 	recpParsing.ContractAddress = receipt.ContractAddress
 	recpParsing.From = receipt.From
@@ -109,6 +109,10 @@ func ImportChain(chain *core.BlockChain, fn string) error {
 		}
 	}
 
+	// An ancient block store is streamed from instead of an RLP file
+	if info, err := os.Stat(fn); err == nil && info.IsDir() {
+		return importAncients(chain, fn, checkInterrupt)
+	}
 	log.Info("Importing blockchain", "file", fn)
 
 	// Open the file handle and potentially unwrap the gzip stream
@@ -169,6 +173,42 @@ func ImportChain(chain *core.BlockChain, fn string) error {
 	return nil
 }
 
+// importAncients imports the blocks of the ancient block store in dir, except
+// the genesis block.
+func importAncients(chain *core.BlockChain, dir string, checkInterrupt func() bool) error {
+	log.Info("Importing blockchain", "ancients", dir)
+
+	freezer, err := rawdb.OpenFreezer(dir)
+	if err != nil {
+		return err
+	}
+	defer freezer.Close()
+
+	blocks := make(types.Blocks, 0, importBatchSize)
+	for first := uint64(1); first < freezer.Ancients(); first += importBatchSize {
+		if checkInterrupt() {
+			return fmt.Errorf("interrupted")
+		}
+		blocks = blocks[:0]
+		for n := first; n < first+importBatchSize && n < freezer.Ancients(); n++ {
+			block, err := rawdb.ReadAncientBlock(freezer, n)
+			if err != nil {
+				return err
+			}
+			blocks = append(blocks, block)
+		}
+		missing := missingBlocks(chain, blocks)
+		if len(missing) == 0 {
+			log.Info("Skipping batch as all blocks present", "first", blocks[0].Hash(), "last", blocks[len(blocks)-1].Hash())
+			continue
+		}
+		if _, err := chain.InsertChain(missing); err != nil {
+			return fmt.Errorf("invalid block %d: %v", missing[0].NumberU64(), err)
+		}
+	}
+	return nil
+}
+
 func missingBlocks(chain *core.BlockChain, blocks []*types.Block) []*types.Block {
 	head := chain.CurrentBlock()
 	for i, block := range blocks {
@@ -239,7 +279,7 @@ func ExportAppendChain(blockchain *core.BlockChain, fn string, first uint64, las
 }
 
 // ImportPreimages imports a batch of exported hash preimages into the database.
-func ImportPreimages(db *ethdb.LDBDatabase, fn string) error {
+func ImportPreimages(db ethdb.Database, fn string) error {
 	log.Info("Importing preimages", "file", fn)
 
 	// Open the file handle and potentially unwrap the gzip stream
@@ -286,7 +326,7 @@ func ImportPreimages(db *ethdb.LDBDatabase, fn string) error {
 
 // ExportPreimages exports all known hash preimages into the specified file,
 // truncating any data already present in the file.
-func ExportPreimages(db *ethdb.LDBDatabase, fn string) error {
+func ExportPreimages(db ethdb.Iteratee, fn string) error {
 	log.Info("Exporting preimages", "file", fn)
 
 	// Open the file handle and potentially wrap with a gzip stream
@@ -303,6 +343,7 @@ func ExportPreimages(db *ethdb.LDBDatabase, fn string) error {
 	}
 	// Iterate over the preimages and export them
 	it := db.NewIteratorWithPrefix([]byte("secure-key-"))
+	defer it.Release()
 	for it.Next() {
 		if err := rlp.Encode(writer, it.Value()); err != nil {
 			return err
@@ -311,3 +352,48 @@ func ExportPreimages(db *ethdb.LDBDatabase, fn string) error {
 	log.Info("Exported preimages", "file", fn)
 	return nil
 }
+
+// ExportCheckpoint exports the canonical block number, its state and the header
+// finalizing it into the specified file, gzipped if it ends with .gz.
+func ExportCheckpoint(blockchain *core.BlockChain, fn string, number uint64) error {
+	log.Info("Exporting checkpoint", "file", fn)
+
+	// Open the file handle and potentially wrap with a gzip stream
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var writer io.Writer = fh
+	if strings.HasSuffix(fn, ".gz") {
+		writer = gzip.NewWriter(writer)
+		defer writer.(*gzip.Writer).Close()
+	}
+	if err := core.ExportCheckpoint(blockchain, number, writer); err != nil {
+		return err
+	}
+	log.Info("Exported checkpoint", "file", fn)
+	return nil
+}
+
+// ImportCheckpoint imports a checkpoint of the trusted block from the specified
+// file into a database at its genesis block.
+func ImportCheckpoint(db ethdb.Database, fn string, trusted common.Hash) (*core.Checkpoint, error) {
+	log.Info("Importing checkpoint", "file", fn)
+
+	// Open the file handle and potentially unwrap the gzip stream
+	fh, err := os.Open(fn)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+
+	var reader io.Reader = fh
+	if strings.HasSuffix(fn, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return nil, err
+		}
+	}
+	return core.ImportCheckpoint(db, reader, trusted)
+}
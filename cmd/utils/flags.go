@@ -34,6 +34,7 @@ import (
 	istanbulBackend "github.com/PlatONEnetwork/PlatONE-Go/consensus/istanbul/backend"
 
 	"github.com/PlatONEnetwork/PlatONE-Go/core"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/rawdb"
 	"github.com/PlatONEnetwork/PlatONE-Go/core/state"
 	"github.com/PlatONEnetwork/PlatONE-Go/core/vm"
 	"github.com/PlatONEnetwork/PlatONE-Go/crypto"
@@ -116,6 +117,11 @@ var (
 		Usage: "Data directory for the databases and keystore",
 		Value: DirectoryString{node.DefaultDataDir()},
 	}
+	DatabaseEngineFlag = cli.StringFlag{
+		Name:  "db.engine",
+		Usage: "Storage engine of the new databases (leveldb, pebble), existing ones keep their own",
+		Value: ethdb.DefaultEngine,
+	}
 	KeyStoreDirFlag = DirectoryFlag{
 		Name:  "keystore",
 		Usage: "Directory for the keystore (default = inside the datadir)",
@@ -150,6 +156,31 @@ var (
 		Usage: `Blockchain garbage collection mode ("full", "archive")`,
 		Value: "full",
 	}
+	CheckpointHashFlag = cli.StringFlag{
+		Name:  "checkpoint.hash",
+		Usage: "Hash of the block of the imported checkpoint, as known by an existing member of the chain",
+	}
+	StateHistoryFlag = cli.Uint64Flag{
+		Name:  "state.history",
+		Usage: "Number of recent block states kept by state pruning",
+		Value: 1024,
+	}
+	StateCheckpointFlag = cli.Uint64Flag{
+		Name:  "state.checkpoint",
+		Usage: "Interval of the block states written to disk and kept by state pruning whatever their age (0 = none)",
+	}
+	AncientThresholdFlag = cli.Uint64Flag{
+		Name:  "ancient.threshold",
+		Usage: "Age in blocks of the blocks moved from the chain database to the ancient store (0 = keep them)",
+	}
+	ReceiptRetentionFlag = cli.Uint64Flag{
+		Name:  "receipts.retention",
+		Usage: "Number of recent blocks whose receipts and logs are kept (0 = all)",
+	}
+	ReceiptArchiveFlag = cli.StringFlag{
+		Name:  "receipts.archive",
+		Usage: "File the pruned receipts are appended to before deletion (relative to the data directory)",
+	}
 	LightServFlag = cli.IntFlag{
 		Name:  "lightserv",
 		Usage: "Maximum percentage of time allowed for serving LES requests (0-90)",
@@ -203,6 +234,15 @@ var (
 		Usage: "Time interval to regenerate the local transaction journal",
 		Value: core.DefaultTxPoolConfig.Rejournal,
 	}
+	TxPoolPersistFlag = cli.BoolFlag{
+		Name:  "txpool.persist",
+		Usage: "Persist all pending transactions in the node database to survive node restarts",
+	}
+	TxPoolLifecycleFlag = cli.DurationFlag{
+		Name:  "txpool.lifecycle",
+		Usage: "Time to keep the lifecycle of transactions after their last step (0 = disabled)",
+		Value: core.DefaultTxPoolConfig.Lifecycle,
+	}
 	TxPoolPriceLimitFlag = cli.Uint64Flag{
 		Name:  "txpool.pricelimit",
 		Usage: "Minimum gas price limit to enforce for acceptance into the pool",
@@ -238,6 +278,15 @@ var (
 		Usage: "Maximum number of transactions for package",
 		Value: eth.DefaultConfig.TxPool.GlobalTxCount,
 	}
+	TxPoolAdminsFlag = cli.StringFlag{
+		Name:  "txpool.admins",
+		Usage: "Comma separated accounts whose transactions are packaged in the system lane, ahead of all others",
+	}
+	TxPoolAccountTxCountFlag = cli.Uint64Flag{
+		Name:  "txpool.accounttxcount",
+		Usage: "Maximum number of transactions of one account for package outside the system lane (0 = no limit)",
+		Value: eth.DefaultConfig.TxPool.AccountTxCount,
+	}
 	TxPoolLifetimeFlag = cli.DurationFlag{
 		Name:  "txpool.lifetime",
 		Usage: "Maximum amount of time non-executable transaction are queued",
@@ -897,6 +946,13 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	case ctx.GlobalIsSet(DataDirFlag.Name):
 		cfg.DataDir = ctx.GlobalString(DataDirFlag.Name)
 	}
+	if ctx.GlobalIsSet(DatabaseEngineFlag.Name) {
+		engine := ctx.GlobalString(DatabaseEngineFlag.Name)
+		if !ethdb.IsEngine(engine) {
+			Fatalf("Unknown database engine %q", engine)
+		}
+		cfg.DatabaseEngine = engine
+	}
 
 	if ctx.GlobalIsSet(KeyStoreDirFlag.Name) {
 		cfg.KeyStoreDir = ctx.GlobalString(KeyStoreDirFlag.Name)
@@ -938,6 +994,12 @@ func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolRejournalFlag.Name) {
 		cfg.Rejournal = ctx.GlobalDuration(TxPoolRejournalFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolPersistFlag.Name) {
+		cfg.Persist = ctx.GlobalBool(TxPoolPersistFlag.Name)
+	}
+	if ctx.GlobalIsSet(TxPoolLifecycleFlag.Name) {
+		cfg.Lifecycle = ctx.GlobalDuration(TxPoolLifecycleFlag.Name)
+	}
 	if ctx.GlobalIsSet(TxPoolPriceLimitFlag.Name) {
 		cfg.PriceLimit = ctx.GlobalUint64(TxPoolPriceLimitFlag.Name)
 	}
@@ -959,6 +1021,19 @@ func setTxPool(ctx *cli.Context, cfg *core.TxPoolConfig) {
 	if ctx.GlobalIsSet(TxPoolGlobalTxCountFlag.Name) {
 		cfg.GlobalTxCount = ctx.GlobalUint64(TxPoolGlobalTxCountFlag.Name)
 	}
+	if ctx.GlobalIsSet(TxPoolAdminsFlag.Name) {
+		admins := strings.Split(ctx.GlobalString(TxPoolAdminsFlag.Name), ",")
+		for _, account := range admins {
+			if trimmed := strings.TrimSpace(account); !common.IsHexAddress(trimmed) {
+				Fatalf("Invalid account in --txpool.admins: %s", trimmed)
+			} else {
+				cfg.Admins = append(cfg.Admins, common.HexToAddress(trimmed))
+			}
+		}
+	}
+	if ctx.GlobalIsSet(TxPoolAccountTxCountFlag.Name) {
+		cfg.AccountTxCount = ctx.GlobalUint64(TxPoolAccountTxCountFlag.Name)
+	}
 	if ctx.GlobalIsSet(TxPoolLifetimeFlag.Name) {
 		cfg.Lifetime = ctx.GlobalDuration(TxPoolLifetimeFlag.Name)
 	}
@@ -1034,6 +1109,18 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *eth.Config) {
 		Fatalf("--%s must be either 'full' or 'archive'", GCModeFlag.Name)
 	}
 	cfg.NoPruning = ctx.GlobalString(GCModeFlag.Name) == "archive"
+	if ctx.GlobalIsSet(StateCheckpointFlag.Name) {
+		cfg.StateCheckpoint = ctx.GlobalUint64(StateCheckpointFlag.Name)
+	}
+	if ctx.GlobalIsSet(AncientThresholdFlag.Name) {
+		cfg.AncientThreshold = ctx.GlobalUint64(AncientThresholdFlag.Name)
+	}
+	if ctx.GlobalIsSet(ReceiptRetentionFlag.Name) {
+		cfg.ReceiptRetention = ctx.GlobalUint64(ReceiptRetentionFlag.Name)
+	}
+	if ctx.GlobalIsSet(ReceiptArchiveFlag.Name) {
+		cfg.ReceiptArchive = ctx.GlobalString(ReceiptArchiveFlag.Name)
+	}
 
 	if ctx.GlobalIsSet(CacheFlag.Name) || ctx.GlobalIsSet(CacheGCFlag.Name) {
 		cfg.TrieCache = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheGCFlag.Name) / 100
@@ -1165,7 +1252,8 @@ func SetupMetrics(ctx *cli.Context) {
 	}
 }
 
-// MakeChainDatabase open an LevelDB using the flags passed to the client and will hard crash if it fails.
+// MakeChainDatabase opens the chain database using the flags passed to the client, along
+// with the ancient block store of full nodes, and will hard crash if it fails.
 func MakeChainDatabase(ctx *cli.Context, stack *node.Node) ethdb.Database {
 	var (
 		cache   = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheDatabaseFlag.Name) / 100
@@ -1179,6 +1267,12 @@ func MakeChainDatabase(ctx *cli.Context, stack *node.Node) ethdb.Database {
 	if err != nil {
 		Fatalf("Could not open database: %v", err)
 	}
+	if dir := stack.ResolvePath(eth.AncientDir); dir != "" && name == "chaindata" {
+		// Offline commands only read the frozen blocks, the node moves them
+		if chainDb, err = rawdb.NewDatabaseWithFreezer(chainDb, dir, 0); err != nil {
+			Fatalf("Could not open ancient block store: %v", err)
+		}
+	}
 	return chainDb
 }
 
@@ -1200,9 +1294,10 @@ func MakeChain(ctx *cli.Context, stack *node.Node) (chain *core.BlockChain, chai
 		Fatalf("--%s must be either 'full' or 'archive'", GCModeFlag.Name)
 	}
 	cache := &core.CacheConfig{
-		Disabled:      ctx.GlobalString(GCModeFlag.Name) == "archive",
-		TrieNodeLimit: eth.DefaultConfig.TrieCache,
-		TrieTimeLimit: eth.DefaultConfig.TrieTimeout,
+		Disabled:        ctx.GlobalString(GCModeFlag.Name) == "archive",
+		TrieNodeLimit:   eth.DefaultConfig.TrieCache,
+		TrieTimeLimit:   eth.DefaultConfig.TrieTimeout,
+		StateCheckpoint: ctx.GlobalUint64(StateCheckpointFlag.Name),
 	}
 	if ctx.GlobalIsSet(CacheFlag.Name) || ctx.GlobalIsSet(CacheGCFlag.Name) {
 		cache.TrieNodeLimit = ctx.GlobalInt(CacheFlag.Name) * ctx.GlobalInt(CacheGCFlag.Name) / 100
@@ -100,7 +100,7 @@ func benchmark(wasmFile string, outDir string, loop int) error {
 		return err
 	}
 
-	if err := lru.SetWasmDB(outDir); err != nil {
+	if err := lru.SetWasmDB(outDir, ""); err != nil {
 		return err
 	}
 
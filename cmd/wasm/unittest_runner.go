@@ -532,6 +532,12 @@ func (s *stateDB) Call(addr, params []byte) ([]byte, error) {
 func (s *stateDB) DelegateCall(addr, params []byte) ([]byte, error) {
 	return nil, nil
 }
+func (s *stateDB) CallContract(addr common.Address, params []byte, gas uint64, value *big.Int) ([]byte, uint64, error) {
+	return nil, 0, nil
+}
+func (s *stateDB) DelegateCallContract(addr common.Address, params []byte, gas uint64) ([]byte, uint64, error) {
+	return nil, 0, nil
+}
 
 
 //func (s *stateDB) CreateAccount(common.Address){}
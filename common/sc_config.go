@@ -56,24 +56,31 @@ type SystemConfig struct {
 	ContractAddress map[string]Address
 }
 
-var SysCfg = &SystemConfig{
-	SystemConfigMu: &sync.RWMutex{},
-	Nodes:          make([]NodeInfo, 0),
-	nodeMap:        make(map[string]*NodeInfo),
-	ConsensusNodes: make([]*NodeInfo, 0),
-	DeleteNodes:    make([]*NodeInfo, 0),
-	HighsetNumber:  new(big.Int).SetInt64(0),
-	SysParam: &SystemParameter{
-		BlockGasLimit: 0xffffffffffff,
-		TxGasLimit:    100000000000000,
-		VRF: VRFParams{
-			ElectionEpoch:     0,
-			NextElectionBlock: 0,
-			ValidatorCount:    0,
+// SysCfg is the system configuration in force after the head of the chain.
+var SysCfg = NewSystemConfig()
+
+// NewSystemConfig creates a system configuration holding the default
+// parameters and no nodes.
+func NewSystemConfig() *SystemConfig {
+	return &SystemConfig{
+		SystemConfigMu: &sync.RWMutex{},
+		Nodes:          make([]NodeInfo, 0),
+		nodeMap:        make(map[string]*NodeInfo),
+		ConsensusNodes: make([]*NodeInfo, 0),
+		DeleteNodes:    make([]*NodeInfo, 0),
+		HighsetNumber:  new(big.Int).SetInt64(0),
+		SysParam: &SystemParameter{
+			BlockGasLimit: 0xffffffffffff,
+			TxGasLimit:    100000000000000,
+			VRF: VRFParams{
+				ElectionEpoch:     0,
+				NextElectionBlock: 0,
+				ValidatorCount:    0,
+			},
+			IsBlockUseTrieHash: true,
 		},
-		IsBlockUseTrieHash: true,
-	},
-	ContractAddress: make(map[string]Address),
+		ContractAddress: make(map[string]Address),
+	}
 }
 
 func (sc *SystemConfig) IsProduceEmptyBlock() bool {
@@ -14,7 +14,9 @@ var (
 	FirewallManagementAddress    = common.HexToAddress("0x1000000000000000000000000000000000000005") // The PlatONE Precompiled contract addr for fire wall management
 	GroupManagementAddress       = common.HexToAddress("0x1000000000000000000000000000000000000006") // The PlatONE Precompiled contract addr for group management
 	ContractDataProcessorAddress = common.HexToAddress("0x1000000000000000000000000000000000000007") // The PlatONE Precompiled contract addr for group management
+	FeeSponsorManagementAddress  = common.HexToAddress("0x1000000000000000000000000000000000000008") // The PlatONE Precompiled contract addr for fee sponsor management
 	CnsInvokeAddress             = common.HexToAddress("0x0000000000000000000000000000000000000000") // The PlatONE Precompiled contract addr for group management
+	BatchInvokeAddress           = common.HexToAddress("0x1000000000000000000000000000000000000010") // The recipient of batch transactions, which carry their calls in the payload
 
 )
 
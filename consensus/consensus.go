@@ -110,6 +110,15 @@ type Handler interface {
 	SetBroadcaster(Broadcaster)
 }
 
+// Checkpointer is implemented by the consensus engines able to verify the
+// blocks of a chain bootstrapped from a state checkpoint, which lacks the
+// blocks before the checkpoint.
+type Checkpointer interface {
+	// StoreCheckpoint stores what the engine needs to verify the blocks after
+	// the checkpoint block of header, whose state is in chain.
+	StoreCheckpoint(chain ChainReader, header *types.Header) error
+}
+
 // Istanbul is a consensus engine to avoid byzantine failure
 type Istanbul interface {
 	Engine
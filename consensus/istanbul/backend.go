@@ -69,4 +69,7 @@ type Backend interface {
 
 	// ParentValidators returns the validator set of the given proposal's parent block
 	ParentValidators(proposal Proposal) ValidatorSet
+
+	// SystemConfig returns the system configuration in force for the given proposal
+	SystemConfig(proposal Proposal) *common.SystemConfig
 }
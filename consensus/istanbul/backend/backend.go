@@ -257,7 +257,7 @@ func (sb *backend) Commit(proposal istanbul.Proposal, seals [][]byte) error {
 	// update block's header
 	block = block.WithSeal(h)
 	isEmpty := block.Transactions().Len() == 0
-	isProduceEmptyBlock := common.SysCfg.IsProduceEmptyBlock()
+	isProduceEmptyBlock := systemConfigAt(sb.chain, h).IsProduceEmptyBlock()
 
 	if !isEmpty || isProduceEmptyBlock {
 		sb.logger.Info("Committed", "address", sb.Address(), "hash", proposal.Hash(), "number", proposal.Number().Uint64())
@@ -379,8 +379,17 @@ func (sb *backend) excuteBlock(proposal istanbul.Proposal) error {
 		return err
 	} else {
 		// Iterate over and process the individual transactios
-		txsMap := make(map[common.Hash]struct{})
+		var (
+			txsMap = make(map[common.Hash]struct{})
+			budget = sb.config.GasBudget()
+			used   uint64
+		)
 		for _, tx := range block.Transactions() {
+			// The gas used, unlike the time taken, is the same on every validator
+			if budget > 0 && used >= budget {
+				sb.logger.Warn("Proposal exceeds the gas budget of a round", "number", block.Number(), "hash", block.Hash(), "used", used, "budget", budget)
+				return errGasBudgetExceeded
+			}
 			sb.current.state.Prepare(tx.Hash(), common.Hash{}, sb.current.tcount)
 			snap := sb.current.state.Snapshot()
 			if r := chain.GetReceiptsByHash(tx.Hash()); r != nil {
@@ -400,6 +409,7 @@ func (sb *backend) excuteBlock(proposal istanbul.Proposal) error {
 			sb.current.txs = append(sb.current.txs, tx)
 			sb.current.receipts = append(sb.current.receipts, receipt)
 			sb.current.tcount++
+			used += receipt.GasUsed
 
 			sb.current.state.Finalise(true)
 		}
@@ -431,7 +441,8 @@ func (sb *backend) Verify(proposal istanbul.Proposal, isProposer bool) (time.Dur
 	}
 
 	// check block body
-	txnHash := types.DeriveSha(block.Transactions())
+	sysConfig := systemConfigAt(sb.chain, block.Header())
+	txnHash := types.DeriveShaWith(block.Transactions(), sysConfig.IsBlockUseTrieHash())
 	//uncleHash := types.CalcUncleHash(block.Uncles())
 	if txnHash != block.Header().TxHash {
 		return 0, errMismatchTxhashes
@@ -442,6 +453,12 @@ func (sb *backend) Verify(proposal istanbul.Proposal, isProposer bool) (time.Dur
 
 	// If this node is proposer and the proposal is mined by this node, need not to execute the block
 	if (block.Coinbase() != sb.address) || !isProposer {
+		// refuse the blocks allowed more gas than the system configuration
+		// grants, executing them checks the gas budget of a round
+		if limit := uint64(sysConfig.GetBlockGasLimit()); block.GasLimit() > limit || block.GasUsed() > block.GasLimit() {
+			sb.logger.Warn("Proposal exceeds the block gas limit", "number", block.Number(), "hash", block.Hash(), "gasLimit", block.GasLimit(), "gasUsed", block.GasUsed(), "limit", limit)
+			return 0, errBlockGasExceeded
+		}
 		//excute txs in block
 		if err := sb.excuteBlock(proposal); err != nil {
 			return 0, err
@@ -494,6 +511,14 @@ func (sb *backend) GetProposer(number uint64) common.Address {
 	return common.Address{}
 }
 
+// SystemConfig implements istanbul.Backend.SystemConfig
+func (sb *backend) SystemConfig(proposal istanbul.Proposal) *common.SystemConfig {
+	if block, ok := proposal.(*types.Block); ok {
+		return systemConfigAt(sb.chain, block.Header())
+	}
+	return common.SysCfg
+}
+
 // ParentValidators implements istanbul.Backend.GetParentValidators
 func (sb *backend) ParentValidators(proposal istanbul.Proposal) istanbul.ValidatorSet {
 	if block, ok := proposal.(*types.Block); ok {
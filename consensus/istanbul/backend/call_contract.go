@@ -59,7 +59,7 @@ func getConsensusNodesList(chain consensus.ChainReader, sb *backend, number uint
 func getVRFParamsAtNumber(chain consensus.ChainReader, sb *backend, number uint64) *common.VRFParams {
 	isOldBlock := number < chain.CurrentHeader().Number.Uint64()
 	if !isOldBlock {
-		return &common.SysCfg.SysParam.VRF
+		return &systemConfigAfter(chain, number).SysParam.VRF
 	}
 
 	resVRF := CallSystemContractAtBlockNumber(chain, sb, number, "__sys_ParamManager", "getVRFParams", []interface{}{})
@@ -90,7 +90,29 @@ func getCandidateNodesAtNumber(chain consensus.ChainReader, sb *backend, number
 		}
 	}
 
-	return common.SysCfg.GetConsensusNodesFilterDelay(number, nodes, isOldBlock)
+	return systemConfigAfter(chain, number).GetConsensusNodesFilterDelay(number, nodes, isOldBlock)
+}
+
+// systemConfigAt returns the system configuration in force for the block of
+// header, the one after the head if chain does not resolve them.
+func systemConfigAt(chain consensus.ChainReader, header *types.Header) *common.SystemConfig {
+	if reader, ok := chain.(core.SystemConfigReader); ok {
+		if config := reader.SystemConfigAt(header); config != nil {
+			return config
+		}
+	}
+	return common.SysCfg
+}
+
+// systemConfigAfter returns the system configuration set by the canonical
+// block number, the one after the head if chain does not resolve them.
+func systemConfigAfter(chain consensus.ChainReader, number uint64) *common.SystemConfig {
+	if bc, ok := chain.(*core.BlockChain); ok {
+		if config := bc.SystemConfigByNumber(number + 1); config != nil {
+			return config
+		}
+	}
+	return common.SysCfg
 }
 
 func ParseResultToExtractType(res []byte, v interface{}) interface{} {
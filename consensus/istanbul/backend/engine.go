@@ -86,6 +86,15 @@ var (
 	errEmptyCommittedSeals = errors.New("zero committed seals")
 	// errMismatchTxhashes is returned if the TxHash in header is mismatch.
 	errMismatchTxhashes = errors.New("mismatch transcations hashes")
+	// errBlockGasExceeded is returned if a proposal is allowed or uses more gas
+	// than the block gas limit of the system configuration.
+	errBlockGasExceeded = errors.New("proposal exceeds the block gas limit")
+	// errGasBudgetExceeded is returned if a transaction of a proposal starts after
+	// the ones before it used the gas budget of a round.
+	errGasBudgetExceeded = errors.New("proposal exceeds the gas budget of a round")
+	// errNoConsensusNodes is returned if the node registry in the state of a
+	// checkpoint holds no consensus node.
+	errNoConsensusNodes = errors.New("no consensus nodes in the node registry")
 )
 var (
 	//nilUncleHash      = types.CalcUncleHash(nil) // Always Keccak256(RLP([])) as uncles are meaningless outside of PoW.
@@ -174,7 +183,7 @@ func (sb *backend) verifyCascadingFields(chain consensus.ChainReader, header *ty
 	}
 
 	//// Verify VRF Nonce
-	if common.SysCfg.SysParam.VRF.ElectionEpoch != 0 {
+	if systemConfigAt(chain, header).SysParam.VRF.ElectionEpoch != 0 {
 		if err := sb.verifyVRF(chain, header); err != nil {
 			return err
 		}
@@ -376,8 +385,9 @@ func (sb *backend) Finalize(chain consensus.ChainReader, header *types.Header, s
 	}
 	header.Root = state.IntermediateRoot(true)
 	log.Debug(fmt.Errorf("root after:%x", header.Root).Error())
-	// Assemble and return the final block for sealing
-	return types.NewBlock(header, txs, receipts), nil
+	// Assemble and return the final block for sealing, hashing its lists the
+	// way the configuration in force at its height does
+	return types.NewBlockWith(header, txs, receipts, systemConfigAt(chain, header).IsBlockUseTrieHash()), nil
 }
 
 // Seal generates a new block for the given input block with the local miner's
@@ -599,6 +609,14 @@ func (sb *backend) snapshot(chain consensus.ChainReader, number uint64, hash com
 			if header == nil {
 				return nil, consensus.ErrUnknownAncestor
 			}
+			// A chain bootstrapped from a state checkpoint starts with the checkpoint
+			// block, whose snapshot is on disk whatever its number
+			if number > 0 && chain.GetHeader(header.ParentHash, number-1) == nil {
+				if s, err := loadSnapshot(sb.db, hash); err == nil {
+					snap = s
+					break
+				}
+			}
 		}
 		headers = append(headers, header)
 		number, hash = number-1, header.ParentHash
@@ -625,6 +643,34 @@ func (sb *backend) snapshot(chain consensus.ChainReader, number uint64, hash com
 	return snap, err
 }
 
+// StoreCheckpoint stores the voting snapshot of the checkpoint block of header
+// a chain was bootstrapped from, with the validators the node registry holds in
+// its state, for the blocks after it to be verified without the ones before.
+func (sb *backend) StoreCheckpoint(chain consensus.ChainReader, header *types.Header) error {
+	nodes, err := getConsensusNodesList(chain, sb, header.Number.Uint64())
+	if err != nil {
+		return err
+	}
+	if len(nodes) == 0 {
+		return errNoConsensusNodes
+	}
+	addrs := make([]common.Address, len(nodes))
+	for i, node := range nodes {
+		pub, err := node.Pubkey()
+		if err != nil {
+			return err
+		}
+		addrs[i] = crypto.PubkeyToAddress(*pub)
+	}
+	snap := newSnapshot(header.Number.Uint64(), header.Hash(), validator.NewSet(addrs, sb.config.ProposerPolicy))
+	if err := snap.store(sb.db); err != nil {
+		return err
+	}
+	sb.recents.Add(snap.Hash, snap)
+	log.Info("Stored checkpoint voting snapshot", "number", snap.Number, "hash", snap.Hash, "validators", len(addrs))
+	return nil
+}
+
 // FIXME: Need to update this for Istanbul
 // sigHash returns the hash which is used as input for the Istanbul
 // signing. It is the hash of the entire header apart from the 65 byte signature
@@ -26,6 +26,7 @@ import (
 	"github.com/PlatONEnetwork/PlatONE-Go/consensus"
 	"github.com/PlatONEnetwork/PlatONE-Go/consensus/istanbul"
 	"github.com/PlatONEnetwork/PlatONE-Go/consensus/istanbul/validator"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/rawdb"
 	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
 	"github.com/PlatONEnetwork/PlatONE-Go/crypto"
 	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
@@ -91,6 +92,19 @@ func loadSnapshot(db ethdb.Database, hash common.Hash) (*Snapshot, error) {
 	return snap, nil
 }
 
+// LatestCheckpoint returns the number of the latest canonical block up to head
+// whose vote snapshot is stored in db, 0 if there is none. The validator sets
+// are restored from there and the blocks up to it are final.
+func LatestCheckpoint(db ethdb.Database, head uint64) uint64 {
+	for number := head - head%checkpointInterval; number > 0; number -= checkpointInterval {
+		hash := rawdb.ReadCanonicalHash(db, number)
+		if ok, _ := db.Has(append([]byte(dbKeySnapshotPrefix), hash[:]...)); ok {
+			return number
+		}
+	}
+	return 0
+}
+
 // store inserts the snapshot into the database.
 func (s *Snapshot) store(db ethdb.Database) error {
 	blob, err := json.Marshal(s)
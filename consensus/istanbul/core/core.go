@@ -233,7 +233,7 @@ func (c *core) commit() {
 
 		if err := c.backend.Commit(proposal, committedSeals); err != nil {
 
-			if err == ErrFirstCommitAtWrongTime || err == ErrEmpty && !common.SysCfg.IsProduceEmptyBlock() {
+			if err == ErrFirstCommitAtWrongTime || err == ErrEmpty && !c.backend.SystemConfig(proposal).IsProduceEmptyBlock() {
 				c.current.UnlockHash() //Unlock block when insertion fails
 				cur := c.currentView().Round
 				//time.Sleep(time.Second)
@@ -254,7 +254,7 @@ func (c *core) singleCommit(proposal istanbul.Proposal) {
 	committedSeals := make([][]byte, 1)
 	committedSeals[0], _ = c.backend.Sign(seal)
 	if err := c.backend.Commit(proposal, committedSeals); err != nil {
-		if err == ErrFirstCommitAtWrongTime || err == ErrEmpty && !common.SysCfg.IsProduceEmptyBlock() {
+		if err == ErrFirstCommitAtWrongTime || err == ErrEmpty && !c.backend.SystemConfig(proposal).IsProduceEmptyBlock() {
 			c.current.UnlockHash() //Unlock block when insertion fails
 			cur := c.currentView().Round
 			//time.Sleep(time.Second)
@@ -156,6 +156,10 @@ func (self *testSystemBackend) ParentValidators(proposal istanbul.Proposal) ista
 	return self.peers
 }
 
+func (self *testSystemBackend) SystemConfig(proposal istanbul.Proposal) *common.SystemConfig {
+	return common.SysCfg
+}
+
 // ==============================================
 //
 // define the struct that need to be provided for integration tests.
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/common/hexutil"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/state"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/vm"
+	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
+)
+
+// revertCode reverts every call.
+var revertCode = hexutil.MustDecode("0x60006000fd")
+
+// Tests that the calls of a batch transaction are kept together or undone
+// together, with their outcomes in the receipt.
+func TestBatchTransaction(t *testing.T) {
+	defer common.SetCurrentInterpreterType(common.GetCurrentInterpreterType())
+	common.SetCurrentInterpreterType("evm")
+
+	env := newDeterminismEnv(t, 1, 2, 0)
+	reverter := common.Address{0xde, 0xad}
+	statedb, _ := state.New(env.root, env.db)
+	statedb.SetCode(reverter, revertCode)
+	root, _ := statedb.Commit(true)
+	env.db.TrieDB().Commit(root, false)
+
+	apply := func(calls []types.BatchCall) (*state.StateDB, *types.Receipt) {
+		tx, err := types.NewBatchTransaction(0, calls, 1000000, big.NewInt(0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx, _ = types.SignTx(tx, types.MakeSigner(env.config), env.keys[0])
+		statedb, _ := state.New(root, env.db)
+		statedb.Prepare(tx.Hash(), common.Hash{}, 0)
+		header := &types.Header{Number: big.NewInt(1), Time: big.NewInt(1), GasLimit: uint64(common.SysCfg.GetBlockGasLimit())}
+		receipt, _, err := ApplyTransaction(env.config, nil, &common.Address{}, new(GasPool).AddGas(header.GasLimit), statedb, header, tx, new(uint64), vm.Config{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return statedb, receipt
+	}
+	counter := func(statedb *state.StateDB, addr common.Address) byte {
+		value := statedb.GetState(addr, make([]byte, 32))
+		if len(value) == 0 {
+			return 0
+		}
+		return value[len(value)-1]
+	}
+	first, second := env.contracts[0], env.contracts[1]
+
+	statedb, receipt := apply([]types.BatchCall{{To: &first, Data: []byte{0x01}}, {To: &second, Data: []byte{0x01}}, {To: &first, Data: []byte{0x01}}})
+	if receipt.Status != types.ReceiptStatusSuccessful || len(receipt.Calls) != 3 || len(receipt.Logs) != 3 {
+		t.Fatalf("receipt status %d, %d calls, %d logs", receipt.Status, len(receipt.Calls), len(receipt.Logs))
+	}
+	if counter(statedb, first) != 2 || counter(statedb, second) != 1 {
+		t.Fatalf("counters %d and %d, want 2 and 1", counter(statedb, first), counter(statedb, second))
+	}
+	if logs := receipt.CallLogs(1); len(logs) != 1 || logs[0].Address != second {
+		t.Fatalf("logs of the second call: %v", logs)
+	}
+
+	statedb, receipt = apply([]types.BatchCall{{To: &first, Data: []byte{0x01}}, {To: &reverter}, {To: &second, Data: []byte{0x01}}})
+	if receipt.Status != types.ReceiptStatusFailed || len(receipt.Logs) != 0 || receipt.RevertReason == "" {
+		t.Fatalf("receipt status %d, %d logs, reason %q", receipt.Status, len(receipt.Logs), receipt.RevertReason)
+	}
+	if len(receipt.Calls) != 2 || receipt.Calls[0].Status != types.ReceiptStatusSuccessful || receipt.Calls[1].Status != types.ReceiptStatusFailed {
+		t.Fatalf("call results %+v", receipt.Calls)
+	}
+	if counter(statedb, first) != 0 || counter(statedb, second) != 0 {
+		t.Fatalf("counters %d and %d after a failed batch", counter(statedb, first), counter(statedb, second))
+	}
+
+	// the call results are stored with the receipt
+	enc, err := rlp.EncodeToBytes((*types.ReceiptForStorage)(receipt))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dec types.ReceiptForStorage
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if dec.RevertReason != receipt.RevertReason || len(dec.Calls) != 2 || dec.Calls[1] != receipt.Calls[1] {
+		t.Fatalf("decoded receipt %+v, want %+v", dec, receipt)
+	}
+}
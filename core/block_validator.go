@@ -62,7 +62,8 @@ func (v *BlockValidator) ValidateBody(block *types.Block) error {
 	}
 	// Header validity is known at this point, check the transactions
 	header := block.Header()
-	if hash := types.DeriveSha(block.Transactions()); hash != header.TxHash {
+	useTrieHash := systemConfigAt(v.bc, header).IsBlockUseTrieHash()
+	if hash := types.DeriveShaWith(block.Transactions(), useTrieHash); hash != header.TxHash {
 		return fmt.Errorf("transaction root hash mismatch: have %x, want %x", hash, header.TxHash)
 	}
 	return nil
@@ -84,7 +85,7 @@ func (v *BlockValidator) ValidateState(block, parent *types.Block, statedb *stat
 		return fmt.Errorf("invalid bloom (remote: %x  local: %x)", header.Bloom, rbloom)
 	}
 	// Tre receipt Trie's root (R = (Tr [[H1, R1], ... [Hn, R1]]))
-	receiptSha := types.DeriveSha(receipts)
+	receiptSha := types.DeriveShaWith(receipts, systemConfigAt(v.bc, header).IsBlockUseTrieHash())
 	if receiptSha != header.ReceiptHash {
 		return fmt.Errorf("invalid receipt root hash (remote: %x local: %x)", header.ReceiptHash, receiptSha)
 	}
@@ -96,14 +97,15 @@ func (v *BlockValidator) ValidateState(block, parent *types.Block, statedb *stat
 	return nil
 }
 
-// CalcGasLimit computes the gas limit of the next block after parent. It aims
+// CalcGasLimit computes the gas limit of the next block after parent, the one
+// set by sysConfig, the system configuration in force for that block. It aims
 // to keep the baseline gas above the provided floor, and increase it towards the
 // ceil if the blocks are full. If the ceil is exceeded, it will always decrease
 // the gas allowance.
-func CalcGasLimit(parent *types.Block, gasFloor, gasCeil uint64) uint64 {
+func CalcGasLimit(parent *types.Block, sysConfig *common.SystemConfig, gasFloor, gasCeil uint64) uint64 {
 
-	if common.SysCfg != nil {
-		return uint64(common.SysCfg.GetBlockGasLimit())
+	if sysConfig != nil {
+		return uint64(sysConfig.GetBlockGasLimit())
 	} else {
 		return parent.GasLimit()
 	}
@@ -33,6 +33,7 @@ import (
 	"github.com/PlatONEnetwork/PlatONE-Go/consensus"
 	"github.com/PlatONEnetwork/PlatONE-Go/core/rawdb"
 	"github.com/PlatONEnetwork/PlatONE-Go/core/state"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/state/snapshot"
 	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
 	"github.com/PlatONEnetwork/PlatONE-Go/core/vm"
 	"github.com/PlatONEnetwork/PlatONE-Go/crypto"
@@ -70,6 +71,8 @@ type CacheConfig struct {
 	Disabled      bool          // Whether to disable trie write caching (archive node)
 	TrieNodeLimit int           // Memory limit (MB) at which to flush the current in-memory trie to disk
 	TrieTimeLimit time.Duration // Time limit after which to flush the current in-memory trie to disk
+
+	StateCheckpoint uint64 // Interval of the block states flushed to disk to be kept by state pruning, 0 for none
 }
 
 type ReceiptsTask struct {
@@ -117,10 +120,12 @@ type BlockChain struct {
 	currentFastBlock atomic.Value // Current head of the fast-sync chain (may be above the block chain!)
 
 	stateCache   state.Database // State database to reuse between imports (contains state cache)
+	snaps        *snapshot.Tree // Flat snapshots of the recent states, nil if unsupported
 	bodyCache    *lru.Cache     // Cache for the most recent block bodies
 	bodyRLPCache *lru.Cache     // Cache for the most recent block bodies in RLP encoded format
 	blockCache   *lru.Cache     // Cache for the most recent entire blocks
 	futureBlocks *lru.Cache     // future blocks are blocks added for later processing
+	sysConfigs   *lru.Cache     // System configurations by parent block and by system contract storage
 
 	quit     chan struct{} // blockchain quit channel
 	updateCh chan *ReceiptsTask
@@ -153,6 +158,7 @@ func NewBlockChain(db ethdb.Database, extdb ethdb.Database, cacheConfig *CacheCo
 	blockCache, _ := lru.New(blockCacheLimit)
 	futureBlocks, _ := lru.New(maxFutureBlocks)
 	badBlocks, _ := lru.New(badBlockLimit)
+	sysConfigs, _ := lru.New(sysConfigCacheLimit)
 
 	bc := &BlockChain{
 		chainConfig:    chainConfig,
@@ -168,6 +174,7 @@ func NewBlockChain(db ethdb.Database, extdb ethdb.Database, cacheConfig *CacheCo
 		bodyRLPCache:   bodyRLPCache,
 		blockCache:     blockCache,
 		futureBlocks:   futureBlocks,
+		sysConfigs:     sysConfigs,
 		engine:         engine,
 		vmConfig:       vmConfig,
 		badBlocks:      badBlocks,
@@ -189,6 +196,8 @@ func NewBlockChain(db ethdb.Database, extdb ethdb.Database, cacheConfig *CacheCo
 	if err != nil {
 		return nil, nil, err
 	}
+	bc.snaps = snapshot.New(db, bc.stateCache.TrieDB(), bc.CurrentBlock().Root())
+
 	// Check the current state of the block hashes and make sure that we do not have any of the bad blocks in our chain
 	for hash := range BadHashes {
 		if header := bc.GetHeaderByHash(hash); header != nil {
@@ -318,6 +327,11 @@ func (bc *BlockChain) SetHead(head uint64) error {
 	rawdb.WriteHeadBlockHash(bc.db, currentBlock.Hash())
 	rawdb.WriteHeadFastBlockHash(bc.db, currentFastBlock.Hash())
 	err, _ := bc.loadLastState()
+
+	// The snapshot layers may all be above the new head
+	if bc.snaps != nil {
+		bc.snaps.Rebuild(bc.CurrentBlock().Root())
+	}
 	return err
 }
 
@@ -393,7 +407,7 @@ func (bc *BlockChain) State() (*state.StateDB, error) {
 
 // StateAt returns a new mutable state based on a particular point in time.
 func (bc *BlockChain) StateAt(root common.Hash) (*state.StateDB, error) {
-	return state.New(root, bc.stateCache)
+	return state.NewWithSnapshot(root, bc.stateCache, bc.snaps)
 }
 
 // Reset purges the entire blockchain, restoring it to its genesis state.
@@ -421,6 +435,9 @@ func (bc *BlockChain) ResetWithGenesisBlock(genesis *types.Block) error {
 	bc.hc.SetCurrentHeader(bc.genesisBlock.Header())
 	bc.currentFastBlock.Store(bc.genesisBlock)
 
+	if bc.snaps != nil {
+		bc.snaps.Rebuild(genesis.Root())
+	}
 	return nil
 }
 
@@ -695,7 +712,10 @@ func (bc *BlockChain) Stop() {
 		for _, offset := range []uint64{0, 1, triesInMemory - 1} {
 			if number := bc.CurrentBlock().NumberU64(); number > offset {
 				recent := bc.GetBlockByNumber(number - offset)
-
+				if recent == nil {
+					// Below the checkpoint the chain was bootstrapped from
+					continue
+				}
 				log.Info("Writing cached state to disk", "block", recent.Number(), "hash", recent.Hash(), "root", recent.Root())
 				if err := triedb.Commit(recent.Root(), true); err != nil {
 					log.Error("Failed to commit recent state trie", "err", err)
@@ -709,6 +729,13 @@ func (bc *BlockChain) Stop() {
 			log.Error("Dangling trie nodes after full cleanup")
 		}
 	}
+	// Flatten the snapshot layers for the disk layer to be at the head on restart
+	if bc.snaps != nil {
+		if err := bc.snaps.Cap(bc.CurrentBlock().Root(), 0); err != nil {
+			log.Error("Failed to flatten state snapshot", "err", err)
+		}
+		bc.snaps.Stop()
+	}
 	log.Info("Blockchain manager stopped")
 }
 
@@ -938,6 +965,12 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 		close(closeCh)
 		return NonStatTy, err
 	}
+	// Keep as many snapshot layers in memory as tries
+	if bc.snaps != nil {
+		if err := bc.snaps.Cap(root, triesInMemory); err != nil {
+			log.Debug("Failed to cap state snapshot", "root", root, "err", err)
+		}
+	}
 	triedb := bc.stateCache.TrieDB()
 
 	// If we're running an archive node, always flush
@@ -952,6 +985,15 @@ func (bc *BlockChain) WriteBlockWithState(block *types.Block, receipts []*types.
 		triedb.Reference(root, common.Hash{}) // metadata reference to keep trie alive
 		bc.triegc.Push(root, -int64(block.NumberU64()))
 
+		// Flush the states of the checkpoints as they come, state pruning keeps them
+		if interval := bc.cacheConfig.StateCheckpoint; interval > 0 && block.NumberU64()%interval == 0 {
+			if err := triedb.Commit(root, false); err != nil {
+				log.Error("Commit checkpoint state to triedb error", "number", block.NumberU64(), "root", root)
+				close(closeCh)
+				return NonStatTy, err
+			}
+		}
+
 		if current := block.NumberU64(); current > triesInMemory {
 			// If we exceeded our memory allowance, flush matured singleton nodes to disk
 			var (
@@ -1177,7 +1219,7 @@ func (bc *BlockChain) insertChain(chain types.Blocks) (int, []interface{}, []*ty
 		} else {
 			parent = chain[i-1]
 		}
-		state, err := state.New(parent.Root(), bc.stateCache)
+		state, err := state.NewWithSnapshot(parent.Root(), bc.stateCache, bc.snaps)
 		if err != nil {
 			return i, events, coalescedLogs, err
 		}
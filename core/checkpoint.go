@@ -0,0 +1,306 @@
+package core
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/consensus"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/rawdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/state"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/crypto"
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/log"
+	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
+)
+
+// Kinds of the entries following the checkpoint in a checkpoint file.
+const (
+	checkpointNode  = iota // Trie node, contract code or ABI, keyed by its hash
+	checkpointValue        // Contract storage value, keyed by its storage value hash
+)
+
+var (
+	emptyStateRoot = types.EmptyRootHash
+	emptyCodeHash  = crypto.Keccak256(nil)
+)
+
+// Checkpoint is the head of a checkpoint file, the block whose state the file
+// holds, followed by the entries of the state.
+type Checkpoint struct {
+	Genesis  common.Hash
+	Block    *types.Block
+	Receipts []*types.ReceiptForStorage
+
+	// Finality is the header of the block after the checkpoint block, whose
+	// commit seals are by the validators of the node registry in its state.
+	Finality *types.Header
+}
+
+type checkpointEntry struct {
+	Kind uint
+	Blob []byte
+}
+
+// ExportCheckpoint writes to w the canonical block number, its state and the
+// header of the block after it.
+func ExportCheckpoint(bc *BlockChain, number uint64, w io.Writer) error {
+	block := bc.GetBlockByNumber(number)
+	if block == nil {
+		return fmt.Errorf("block %d not found", number)
+	}
+	finality := bc.GetHeaderByNumber(number + 1)
+	if finality == nil {
+		return fmt.Errorf("block %d finalizing the checkpoint not found", number+1)
+	}
+	if _, err := bc.StateAt(block.Root()); err != nil {
+		return fmt.Errorf("state of block %d missing: %v", number, err)
+	}
+	cp := &Checkpoint{Genesis: bc.Genesis().Hash(), Block: block, Finality: finality}
+	for _, receipt := range bc.GetReceiptsByHash(block.Hash()) {
+		cp.Receipts = append(cp.Receipts, (*types.ReceiptForStorage)(receipt))
+	}
+	if err := rlp.Encode(w, cp); err != nil {
+		return err
+	}
+	log.Info("Exporting checkpoint", "number", number, "hash", block.Hash(), "root", block.Root())
+
+	var (
+		start   = time.Now()
+		entries int
+		logged  = time.Now()
+	)
+	write := func(kind uint) func([]byte) error {
+		return func(blob []byte) error {
+			entries++
+			if time.Since(logged) > 8*time.Second {
+				log.Info("Exporting checkpoint state", "entries", entries, "elapsed", common.PrettyDuration(time.Since(start)))
+				logged = time.Now()
+			}
+			return rlp.Encode(w, &checkpointEntry{Kind: kind, Blob: blob})
+		}
+	}
+	if err := walkState(bc.stateCache, block.Root(), write(checkpointNode), write(checkpointValue)); err != nil {
+		return err
+	}
+	log.Info("Exported checkpoint", "number", number, "entries", entries, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// ImportCheckpoint reads a checkpoint file from r into db, whose chain must be
+// at its genesis block. The checkpoint block must be the trusted one, which the
+// state is checked to be complete against, but it is only made the head of the
+// chain by VerifyCheckpoint, once the engine verified its finality.
+func ImportCheckpoint(db ethdb.Database, r io.Reader, trusted common.Hash) (*Checkpoint, error) {
+	genesis := rawdb.ReadCanonicalHash(db, 0)
+	if genesis == (common.Hash{}) {
+		return nil, errors.New("genesis block missing, initialize the database first")
+	}
+	if head := rawdb.ReadHeadBlockHash(db); head != genesis {
+		return nil, errors.New("chain past its genesis block, checkpoints are imported in new databases")
+	}
+	stream := rlp.NewStream(r, 0)
+	cp := new(Checkpoint)
+	if err := stream.Decode(cp); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint: %v", err)
+	}
+	block := cp.Block
+	if block.Hash() != trusted {
+		return nil, fmt.Errorf("untrusted checkpoint block %x, want %x", block.Hash(), trusted)
+	}
+	if cp.Genesis != genesis {
+		return nil, fmt.Errorf("checkpoint of another chain: genesis %x, have %x", cp.Genesis, genesis)
+	}
+	if cp.Finality.ParentHash != block.Hash() || cp.Finality.Number.Uint64() != block.NumberU64()+1 {
+		return nil, errors.New("finality header not the child of the checkpoint block")
+	}
+	txs := block.Transactions()
+	if hash := block.TxHash(); hash != types.DeriveShaWith(txs, true) && hash != types.DeriveShaWith(txs, false) {
+		return nil, errors.New("transactions mismatch the checkpoint block")
+	}
+	receipts := make(types.Receipts, len(cp.Receipts))
+	for i, receipt := range cp.Receipts {
+		receipts[i] = (*types.Receipt)(receipt)
+	}
+	if hash := block.ReceiptHash(); hash != types.DeriveShaWith(receipts, true) && hash != types.DeriveShaWith(receipts, false) {
+		return nil, errors.New("receipts mismatch the checkpoint block")
+	}
+	log.Info("Importing checkpoint", "number", block.Number(), "hash", block.Hash(), "root", block.Root())
+
+	// Store the entries under the hashes of their contents, for any entry
+	// reached from the state root to be the one it references
+	var (
+		start   = time.Now()
+		batch   = db.NewBatch()
+		entries int
+	)
+	for {
+		var entry checkpointEntry
+		if err := stream.Decode(&entry); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("invalid checkpoint entry %d: %v", entries, err)
+		}
+		switch entry.Kind {
+		case checkpointNode:
+			batch.Put(crypto.Keccak256(entry.Blob), entry.Blob)
+		case checkpointValue:
+			rawdb.WritePreimages(batch, 0, map[common.Hash][]byte{state.StorageValueHash(entry.Blob): entry.Blob})
+		default:
+			return nil, fmt.Errorf("unknown kind %d of checkpoint entry %d", entry.Kind, entries)
+		}
+		entries++
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return nil, err
+			}
+			batch.Reset()
+			log.Info("Importing checkpoint state", "entries", entries, "elapsed", common.PrettyDuration(time.Since(start)))
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return nil, err
+	}
+	if err := walkState(state.NewDatabase(db), block.Root(), nil, nil); err != nil {
+		return nil, fmt.Errorf("incomplete checkpoint state: %v", err)
+	}
+	// The block is made canonical for the engine to read its state, the head
+	// stays at the genesis block until the checkpoint is verified
+	batch.Reset()
+	rawdb.WriteBlock(batch, block)
+	rawdb.WriteReceipts(batch, block.Hash(), block.NumberU64(), receipts)
+	rawdb.WriteCanonicalHash(batch, block.Hash(), block.NumberU64())
+	rawdb.WriteTxLookupEntries(batch, block)
+	if err := batch.Write(); err != nil {
+		return nil, err
+	}
+	log.Info("Imported checkpoint", "number", block.Number(), "entries", entries, "elapsed", common.PrettyDuration(time.Since(start)))
+	return cp, nil
+}
+
+// VerifyCheckpoint checks that the consensus engine finalized the checkpoint
+// block imported by ImportCheckpoint, with the validators of the node registry
+// in its state, and only then makes it the head of the chain. The checkpoint
+// block is dropped from the canonical chain if not.
+func (bc *BlockChain) VerifyCheckpoint(cp *Checkpoint) error {
+	if head := bc.CurrentBlock(); head.Hash() != bc.genesisBlock.Hash() {
+		return fmt.Errorf("chain at block %d, checkpoints are verified at the genesis block", head.NumberU64())
+	}
+	header := cp.Block.Header()
+	if hash := rawdb.ReadCanonicalHash(bc.db, header.Number.Uint64()); hash != header.Hash() {
+		return fmt.Errorf("checkpoint block %d not imported", header.Number.Uint64())
+	}
+	if err := bc.verifyCheckpoint(header, cp.Finality); err != nil {
+		rawdb.DeleteCanonicalHash(bc.db, header.Number.Uint64())
+		return err
+	}
+	batch := bc.db.NewBatch()
+	rawdb.WriteCheckpointHash(batch, header.Hash())
+	rawdb.WriteHeadHeaderHash(batch, header.Hash())
+	rawdb.WriteHeadFastBlockHash(batch, header.Hash())
+	rawdb.WriteHeadBlockHash(batch, header.Hash())
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	if err, _ := bc.loadLastState(); err != nil {
+		return err
+	}
+	log.Info("Verified checkpoint", "number", header.Number, "hash", header.Hash())
+	return nil
+}
+
+func (bc *BlockChain) verifyCheckpoint(header, finality *types.Header) error {
+	checkpointer, ok := bc.engine.(consensus.Checkpointer)
+	if !ok {
+		return errors.New("consensus engine unable to verify checkpoints")
+	}
+	if err := checkpointer.StoreCheckpoint(bc, header); err != nil {
+		return err
+	}
+	return bc.engine.VerifyHeader(bc, finality, true)
+}
+
+// walkState calls onNode with every trie node, contract code and ABI of the
+// state of root, and onValue with every contract storage value, failing on
+// any of them missing.
+func walkState(db state.Database, root common.Hash, onNode, onValue func([]byte) error) error {
+	emit := func(fn func([]byte) error, blob []byte) error {
+		if fn == nil {
+			return nil
+		}
+		return fn(blob)
+	}
+	node := func(hash common.Hash) error {
+		blob, err := db.TrieDB().Node(hash)
+		if err != nil {
+			return fmt.Errorf("node %x: %v", hash, err)
+		}
+		return emit(onNode, blob)
+	}
+	tr, err := db.OpenTrie(root)
+	if err != nil {
+		return err
+	}
+	it := tr.NodeIterator(nil)
+	for it.Next(true) {
+		if hash := it.Hash(); hash != (common.Hash{}) {
+			if err := node(hash); err != nil {
+				return err
+			}
+		}
+		if !it.Leaf() {
+			continue
+		}
+		var account state.Account
+		if err := rlp.DecodeBytes(it.LeafBlob(), &account); err != nil {
+			return err
+		}
+		for _, hash := range [][]byte{account.CodeHash, account.AbiHash} {
+			if len(hash) == 0 || bytes.Equal(hash, emptyCodeHash) {
+				continue
+			}
+			if err := node(common.BytesToHash(hash)); err != nil {
+				return err
+			}
+		}
+		if account.Root == emptyStateRoot || account.Root == (common.Hash{}) {
+			continue
+		}
+		st, err := db.OpenStorageTrie(common.BytesToHash(it.LeafKey()), account.Root)
+		if err != nil {
+			return err
+		}
+		sit := st.NodeIterator(nil)
+		for sit.Next(true) {
+			if hash := sit.Hash(); hash != (common.Hash{}) {
+				if err := node(hash); err != nil {
+					return err
+				}
+			}
+			if !sit.Leaf() {
+				continue
+			}
+			// The storage trie holds the hash of the value, the value is its preimage
+			_, content, _, err := rlp.Split(sit.LeafBlob())
+			if err != nil {
+				return err
+			}
+			valueKey := common.BytesToHash(content)
+			value := st.GetKey(valueKey.Bytes())
+			if value == nil {
+				return fmt.Errorf("storage value %x missing", valueKey)
+			}
+			if err := emit(onValue, value); err != nil {
+				return err
+			}
+		}
+		if sit.Error() != nil {
+			return sit.Error()
+		}
+	}
+	return it.Error()
+}
@@ -0,0 +1,181 @@
+package core
+
+import (
+	"bytes"
+	"io"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/rawdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/state"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/vm"
+	"github.com/PlatONEnetwork/PlatONE-Go/crypto"
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/params"
+	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
+)
+
+var (
+	testCheckpointContract = common.Address{0xcc}
+	testCheckpointKey      = []byte("name")
+	testCheckpointValue    = []byte("checkpoint")
+	testCheckpointCode     = []byte{0x00, 0x61, 0x73, 0x6d}
+)
+
+func checkpointGenesis() *Genesis {
+	return &Genesis{
+		Config:    &params.ChainConfig{ChainID: big.NewInt(1)},
+		Timestamp: 1,
+		Alloc:     GenesisAlloc{common.Address{0x01}: {Balance: big.NewInt(1)}},
+	}
+}
+
+// newCheckpointChain creates a chain of two blocks on top of the checkpoint
+// genesis, whose state holds a contract the genesis state lacks.
+func newCheckpointChain(t *testing.T) (*BlockChain, *types.Block) {
+	db := ethdb.NewMemDatabase()
+	genesis := checkpointGenesis().MustCommit(db)
+
+	statedb, _ := state.New(genesis.Root(), state.NewDatabase(db))
+	statedb.SetCode(testCheckpointContract, testCheckpointCode)
+	statedb.SetState(testCheckpointContract, testCheckpointKey, testCheckpointValue)
+	root, err := statedb.Commit(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := statedb.Database().TrieDB().Commit(root, false); err != nil {
+		t.Fatal(err)
+	}
+	parent := genesis
+	for i := 1; i <= 2; i++ {
+		block := types.NewBlock(&types.Header{Number: big.NewInt(int64(i)), ParentHash: parent.Hash(), Root: root}, nil, nil)
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		parent = block
+	}
+	bc, _, err := NewBlockChain(db, nil, nil, checkpointGenesis().Config, sysConfigEngine{}, vm.Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bc, bc.GetBlockByNumber(1)
+}
+
+// Tests that the state of an exported checkpoint is imported into a new
+// database, whose head only moves to the checkpoint once it is verified.
+func TestCheckpointRoundTrip(t *testing.T) {
+	bc, block := newCheckpointChain(t)
+	defer bc.Stop()
+
+	var exported bytes.Buffer
+	if err := ExportCheckpoint(bc, block.NumberU64(), &exported); err != nil {
+		t.Fatal(err)
+	}
+	db := ethdb.NewMemDatabase()
+	genesis := checkpointGenesis().MustCommit(db)
+
+	if _, err := ImportCheckpoint(db, bytes.NewReader(exported.Bytes()), common.Hash{0xff}); err == nil {
+		t.Fatal("checkpoint of an untrusted block imported")
+	}
+	cp, err := ImportCheckpoint(db, bytes.NewReader(exported.Bytes()), block.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cp.Block.Hash() != block.Hash() || cp.Finality.Hash() != bc.GetHeaderByNumber(2).Hash() {
+		t.Fatalf("imported checkpoint of block %x finalized by %x", cp.Block.Hash(), cp.Finality.Hash())
+	}
+	if head := rawdb.ReadHeadBlockHash(db); head != genesis.Hash() {
+		t.Fatalf("head moved to %x before the checkpoint was verified", head)
+	}
+	statedb, err := state.New(block.Root(), state.NewDatabase(db))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code := statedb.GetCode(testCheckpointContract); !bytes.Equal(code, testCheckpointCode) {
+		t.Fatalf("imported code %x, want %x", code, testCheckpointCode)
+	}
+	if value := statedb.GetState(testCheckpointContract, testCheckpointKey); !bytes.Equal(value, testCheckpointValue) {
+		t.Fatalf("imported storage value %q, want %q", value, testCheckpointValue)
+	}
+
+	// The test engine can't verify checkpoints, the block must be dropped
+	imported, _, err := NewBlockChain(db, nil, nil, checkpointGenesis().Config, sysConfigEngine{}, vm.Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer imported.Stop()
+	if err := imported.VerifyCheckpoint(cp); err == nil {
+		t.Fatal("checkpoint verified without a checkpointing engine")
+	}
+	if hash := rawdb.ReadCanonicalHash(db, block.NumberU64()); hash != (common.Hash{}) {
+		t.Fatalf("unverified checkpoint block %x left canonical", hash)
+	}
+	if head := imported.CurrentBlock(); head.Hash() != genesis.Hash() || rawdb.ReadCheckpointHash(db) != (common.Hash{}) {
+		t.Fatalf("head moved to unverified block %d", head.NumberU64())
+	}
+}
+
+// Tests that a checkpoint lacking part of its state is rejected.
+func TestCheckpointIncompleteState(t *testing.T) {
+	bc, block := newCheckpointChain(t)
+	defer bc.Stop()
+
+	var exported bytes.Buffer
+	if err := ExportCheckpoint(bc, block.NumberU64(), &exported); err != nil {
+		t.Fatal(err)
+	}
+	// Copy the checkpoint without the state root node
+	var (
+		stream    = rlp.NewStream(&exported, 0)
+		truncated bytes.Buffer
+		cp        Checkpoint
+	)
+	if err := stream.Decode(&cp); err != nil {
+		t.Fatal(err)
+	}
+	rlp.Encode(&truncated, &cp)
+	for {
+		var entry checkpointEntry
+		if err := stream.Decode(&entry); err == io.EOF {
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		if entry.Kind == checkpointNode && crypto.Keccak256Hash(entry.Blob) == block.Root() {
+			continue
+		}
+		rlp.Encode(&truncated, &entry)
+	}
+	db := ethdb.NewMemDatabase()
+	checkpointGenesis().MustCommit(db)
+
+	_, err := ImportCheckpoint(db, &truncated, block.Hash())
+	if err == nil || !strings.Contains(err.Error(), "incomplete checkpoint state") {
+		t.Fatalf("import error %v, want incomplete state", err)
+	}
+	if hash := rawdb.ReadCanonicalHash(db, block.NumberU64()); hash != (common.Hash{}) {
+		t.Fatalf("checkpoint block %x of an incomplete state made canonical", hash)
+	}
+}
+
+// Tests that a checkpoint is not imported into the chain of another genesis.
+func TestCheckpointWrongGenesis(t *testing.T) {
+	bc, block := newCheckpointChain(t)
+	defer bc.Stop()
+
+	var exported bytes.Buffer
+	if err := ExportCheckpoint(bc, block.NumberU64(), &exported); err != nil {
+		t.Fatal(err)
+	}
+	db := ethdb.NewMemDatabase()
+	other := checkpointGenesis()
+	other.ExtraData = []byte("other")
+	other.MustCommit(db)
+
+	_, err := ImportCheckpoint(db, &exported, block.Hash())
+	if err == nil || !strings.Contains(err.Error(), "checkpoint of another chain") {
+		t.Fatalf("import error %v, want another chain", err)
+	}
+}
@@ -33,5 +33,17 @@ var (
 	// next one expected based on the local chain.
 	ErrNonceTooHigh = errors.New("nonce too high")
 
+	// ErrTxNotYetValid is returned if a transaction is included in a block
+	// before its validity window.
+	ErrTxNotYetValid = errors.New("transaction not yet valid")
+
+	// ErrTxExpired is returned if a transaction is included in a block after
+	// its validity window.
+	ErrTxExpired = errors.New("transaction expired")
+
+	// ErrInvalidBatch is returned if a batch transaction carries a value, is
+	// not sent to the batch address or its calls cannot be decoded.
+	ErrInvalidBatch = errors.New("invalid batch transaction")
+
 	ErrParamaManagerContractAddressNotFound = errors.New("paramManager contract address not found")
 )
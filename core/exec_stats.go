@@ -0,0 +1,87 @@
+package core
+
+import (
+	"sync"
+	"time"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	lru "github.com/hashicorp/golang-lru"
+)
+
+const (
+	execStatsContracts = 4096 // Contracts whose execution times are kept
+	execStatsWeight    = 0.2  // Weight of a new measurement in the moving averages
+)
+
+// ExecStats holds the execution times measured by this node, for the miner to
+// budget the blocks it packs. Being local, they don't bound the blocks of others.
+var ExecStats = NewExecutionStats()
+
+// ExecutionStats keeps moving averages of the wall-clock time transactions
+// take to execute, per target contract, to estimate how long a block takes
+// before executing it.
+type ExecutionStats struct {
+	contracts *lru.Cache    // Average execution time by target, contract creations under the zero address
+	overall   time.Duration // Average execution time of all transactions, for the unknown targets
+	mu        sync.Mutex
+}
+
+// NewExecutionStats creates an empty execution time tracker.
+func NewExecutionStats() *ExecutionStats {
+	contracts, _ := lru.New(execStatsContracts)
+	return &ExecutionStats{contracts: contracts}
+}
+
+// observe records the execution time of a transaction to target started at
+// start.
+func (s *ExecutionStats) observe(target common.Address, start time.Time) {
+	s.Record(target, time.Since(start))
+}
+
+// Record adds the execution time of a transaction to target to the averages.
+func (s *ExecutionStats) Record(target common.Address, elapsed time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	avg := elapsed
+	if cached, ok := s.contracts.Get(target); ok {
+		avg = movingAverage(cached.(time.Duration), elapsed)
+	}
+	s.contracts.Add(target, avg)
+
+	if s.overall == 0 {
+		s.overall = elapsed
+	} else {
+		s.overall = movingAverage(s.overall, elapsed)
+	}
+}
+
+// Estimate returns the expected execution time of tx, the average of all
+// transactions if its target was not measured yet.
+func (s *ExecutionStats) Estimate(tx *types.Transaction) time.Duration {
+	target := common.Address{}
+	if tx.To() != nil {
+		target = *tx.To()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if cached, ok := s.contracts.Get(target); ok {
+		return cached.(time.Duration)
+	}
+	return s.overall
+}
+
+// EstimateBlock returns the expected execution time of txs.
+func (s *ExecutionStats) EstimateBlock(txs types.Transactions) time.Duration {
+	var total time.Duration
+	for _, tx := range txs {
+		total += s.Estimate(tx)
+	}
+	return total
+}
+
+func movingAverage(avg, sample time.Duration) time.Duration {
+	return time.Duration((1-execStatsWeight)*float64(avg) + execStatsWeight*float64(sample))
+}
@@ -0,0 +1,41 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+)
+
+// Tests that execution times are averaged per contract and that the unknown
+// contracts are estimated from all the measurements.
+func TestExecutionStats(t *testing.T) {
+	stats := NewExecutionStats()
+	fast, slow := common.Address{0x01}, common.Address{0x02}
+	toFast := types.NewTransaction(0, fast, new(big.Int), 0, new(big.Int), nil, types.NormalTxType)
+	toSlow := types.NewTransaction(0, slow, new(big.Int), 0, new(big.Int), nil, types.NormalTxType)
+	toOther := types.NewTransaction(0, common.Address{0x03}, new(big.Int), 0, new(big.Int), nil, types.NormalTxType)
+
+	if estimate := stats.Estimate(toOther); estimate != 0 {
+		t.Fatalf("estimate without measurements = %v, want 0", estimate)
+	}
+	stats.Record(fast, time.Millisecond)
+	stats.Record(slow, 100*time.Millisecond)
+	stats.Record(slow, 200*time.Millisecond)
+
+	if estimate := stats.Estimate(toFast); estimate != time.Millisecond {
+		t.Fatalf("estimate of fast contract = %v, want %v", estimate, time.Millisecond)
+	}
+	if estimate := stats.Estimate(toSlow); estimate != 120*time.Millisecond {
+		t.Fatalf("estimate of slow contract = %v, want %v", estimate, 120*time.Millisecond)
+	}
+	other := stats.Estimate(toOther)
+	if other <= time.Millisecond || other >= 200*time.Millisecond {
+		t.Fatalf("estimate of unknown contract = %v, want between the measurements", other)
+	}
+	if total := stats.EstimateBlock(types.Transactions{toFast, toSlow, toOther}); total != 121*time.Millisecond+other {
+		t.Fatalf("block estimate = %v, want %v", total, 121*time.Millisecond+other)
+	}
+}
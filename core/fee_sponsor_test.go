@@ -0,0 +1,162 @@
+package core
+
+import (
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/common/syscontracts"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/state"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/vm"
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/params"
+	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
+)
+
+// Tests that sponsors set their policies in the system contract and that the
+// policies limit the targets and the daily gas they pay for.
+func TestFeeSponsorPolicy(t *testing.T) {
+	var (
+		sponsor    = common.Address{0x01}
+		allowed    = common.Address{0xaa}
+		other      = common.Address{0xbb}
+		statedb, _ = state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+		header     = &types.Header{Number: big.NewInt(1), Time: big.NewInt(1), GasLimit: uint64(common.SysCfg.GetBlockGasLimit())}
+		msg        = types.NewMessage(sponsor, &syscontracts.FeeSponsorManagementAddress, 0, new(big.Int), 0, new(big.Int), nil, false, types.NormalTxType)
+		evm        = vm.NewEVM(NewEVMContext(msg, header, nil, &common.Address{}), statedb, &params.ChainConfig{ChainID: big.NewInt(1)}, vm.Config{})
+		day, next  = uint64(1e9), uint64(1e9 + 24*60*60*1000)
+	)
+	input, err := common.GenerateWasmData(common.CallContractFlag, "setSponsorPolicy", []interface{}{`["` + allowed.Hex() + `"]`, uint64(100)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := evm.Call(vm.AccountRef(sponsor), syscontracts.FeeSponsorManagementAddress, input, 1000000, new(big.Int)); err != nil || evm.Failure() != "" {
+		t.Fatalf("setting the policy failed: %v %s", err, evm.Failure())
+	}
+
+	if err := vm.CheckFeeSponsorship(statedb, common.Address{0x02}, []*common.Address{&allowed}, 10, day); err != vm.ErrNoSponsorPolicy {
+		t.Fatalf("sponsor without policy: %v, want %v", err, vm.ErrNoSponsorPolicy)
+	}
+	if err := vm.CheckFeeSponsorship(statedb, sponsor, []*common.Address{&allowed}, 100, day); err != nil {
+		t.Fatalf("allowed target: %v", err)
+	}
+	if err := vm.CheckFeeSponsorship(statedb, sponsor, []*common.Address{&allowed, &other}, 10, day); err != vm.ErrSponsorTargetNotAllowed {
+		t.Fatalf("other target: %v, want %v", err, vm.ErrSponsorTargetNotAllowed)
+	}
+	if err := vm.CheckFeeSponsorship(statedb, sponsor, []*common.Address{nil}, 10, day); err != vm.ErrSponsorTargetNotAllowed {
+		t.Fatalf("contract creation: %v, want %v", err, vm.ErrSponsorTargetNotAllowed)
+	}
+
+	// the cap holds for the day, and starts over the next one
+	if err := vm.ChargeFeeSponsorship(statedb, sponsor, 60, day); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.CheckFeeSponsorship(statedb, sponsor, []*common.Address{&allowed}, 41, day); err != vm.ErrSponsorCapExceeded {
+		t.Fatalf("over the cap: %v, want %v", err, vm.ErrSponsorCapExceeded)
+	}
+	if err := vm.CheckFeeSponsorship(statedb, sponsor, []*common.Address{&allowed}, 40, day); err != nil {
+		t.Fatalf("within the cap: %v", err)
+	}
+	if err := vm.CheckFeeSponsorship(statedb, sponsor, []*common.Address{&allowed}, 100, next); err != nil {
+		t.Fatalf("next day: %v", err)
+	}
+}
+
+// testFeeToken is a fee token contract keeping the balances of the accounts
+// paying for their gas.
+type testFeeToken struct {
+	balances map[string]int64
+}
+
+func (c *testFeeToken) RequiredGas(input []byte) uint64 { return 0 }
+
+func (c *testFeeToken) Run(input []byte) ([]byte, error) {
+	var data [][]byte
+	if err := rlp.DecodeBytes(input, &data); err != nil {
+		return nil, err
+	}
+	switch string(data[1]) {
+	case "checkBalance":
+		if c.balances[string(data[2])] < common.BytesToInt64(data[3]) {
+			return common.Int64ToBytes(0), nil
+		}
+		return common.Int64ToBytes(1), nil
+	case "withHoldingFee":
+		c.balances[string(data[2])] -= common.BytesToInt64(data[3])
+	case "refundFee":
+		c.balances[string(data[2])] += common.BytesToInt64(data[3])
+	case "getGasPrice":
+		return common.Int64ToBytes(1), nil
+	}
+	return nil, nil
+}
+
+// sponsoredMessage is a message whose fees are paid by a sponsor.
+type sponsoredMessage struct {
+	*types.Message
+	sponsor common.Address
+}
+
+func (m sponsoredMessage) FeePayer() *common.Address { return &m.sponsor }
+
+// Tests that the fees of a sponsored transaction are paid by its sponsor out
+// of the gas its policy covers.
+func TestFeeSponsorTransition(t *testing.T) {
+	var (
+		sender     = common.Address{0x02}
+		sponsor    = common.Address{0x01}
+		allowed    = common.Address{0xaa}
+		token      = common.Address{0xfe}
+		statedb, _ = state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+		day        = uint64(1e9)
+		header     = &types.Header{Number: big.NewInt(1), Time: new(big.Int).SetUint64(day), GasLimit: uint64(common.SysCfg.GetBlockGasLimit())}
+		balances   = map[string]int64{strings.ToLower(sender.String()): 1000000, strings.ToLower(sponsor.String()): 1000000}
+	)
+	vm.PrecompiledContracts[token] = &testFeeToken{balances: balances}
+	defer delete(vm.PrecompiledContracts, token)
+
+	sysCfg := common.NewSystemConfig()
+	sysCfg.SysParam.IsTxUseGas = true
+	sysCfg.SysParam.GasContractAddr = token
+
+	newEVM := func(msg Message) *vm.EVM {
+		evm := vm.NewEVM(NewEVMContext(msg, header, nil, &common.Address{}), statedb, &params.ChainConfig{ChainID: big.NewInt(1)}, vm.Config{})
+		evm.SysConfig = sysCfg
+		return evm
+	}
+	input, err := common.GenerateWasmData(common.CallContractFlag, "setSponsorPolicy", []interface{}{`["` + allowed.Hex() + `"]`, uint64(100000)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	policy := types.NewMessage(sponsor, &syscontracts.FeeSponsorManagementAddress, 0, new(big.Int), 0, new(big.Int), nil, false, types.NormalTxType)
+	evm := newEVM(policy)
+	if _, _, err := evm.Call(vm.AccountRef(sponsor), syscontracts.FeeSponsorManagementAddress, input, 1000000, new(big.Int)); err != nil || evm.Failure() != "" {
+		t.Fatalf("setting the policy failed: %v %s", err, evm.Failure())
+	}
+
+	msg := sponsoredMessage{types.NewMessage(sender, &allowed, 1, new(big.Int), 50000, new(big.Int), nil, false, types.NormalTxType), sponsor}
+	_, used, _, failed, err := NewStateTransition(newEVM(msg), msg, new(GasPool).AddGas(math.MaxUint64)).TransitionDb()
+	if err != nil || failed {
+		t.Fatalf("sponsored transaction failed: %v", err)
+	}
+	if used == 0 {
+		t.Fatal("sponsored transaction used no gas")
+	}
+	if balance := balances[strings.ToLower(sponsor.String())]; balance != 1000000-int64(used) {
+		t.Fatalf("sponsor balance %d, want %d", balance, 1000000-int64(used))
+	}
+	if balance := balances[strings.ToLower(sender.String())]; balance != 1000000 {
+		t.Fatalf("sender balance %d, want untouched", balance)
+	}
+
+	// the gas used is charged to the daily cap of the policy
+	if err := vm.CheckFeeSponsorship(statedb, sponsor, []*common.Address{&allowed}, 100000-used+1, day); err != vm.ErrSponsorCapExceeded {
+		t.Fatalf("over the remaining cap: %v, want %v", err, vm.ErrSponsorCapExceeded)
+	}
+	if err := vm.CheckFeeSponsorship(statedb, sponsor, []*common.Address{&allowed}, 100000-used, day); err != nil {
+		t.Fatalf("within the remaining cap: %v", err)
+	}
+}
@@ -433,6 +433,13 @@ func (hc *HeaderChain) SetHead(head uint64, delFn DeleteCallback) {
 	}
 	batch.Write()
 
+	// The frozen blocks above the new head are dropped from the freezer too
+	if ancients, ok := hc.chainDb.(rawdb.AncientWriter); ok {
+		if err := ancients.TruncateAncients(head + 1); err != nil {
+			log.Error("Failed to truncate ancient blocks", "head", head, "err", err)
+		}
+	}
+
 	// Clear out any stale content from the caches
 	hc.headerCache.Purge()
 	hc.numberCache.Purge()
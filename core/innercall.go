@@ -2,6 +2,7 @@ package core
 
 import (
 	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/state"
 	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
 	"github.com/PlatONEnetwork/PlatONE-Go/core/vm"
 	"math/big"
@@ -10,18 +11,23 @@ import (
 var InnerCallFromAddress = common.HexToAddress("0x1000000000000000000000000000000000000000")
 
 func innerCallContractReadOnly(bc *BlockChain, contractAddr common.Address, input []byte) ([]byte, error) {
-	// Create new call message
-	msg := types.NewMessage(InnerCallFromAddress, &contractAddr, 1, big.NewInt(1), uint64(0xffffffffff), big.NewInt(1), input, false, types.NormalTxType)
-
 	// Get the state
 	state, err := bc.State()
 	if state == nil || err != nil {
 		return nil, err
 	}
-	header := bc.CurrentHeader()
+	return innerCallAt(bc, bc.CurrentHeader(), state, contractAddr, input)
+}
+
+// innerCallAt calls contractAddr with input on statedb, in the context of header.
+func innerCallAt(bc *BlockChain, header *types.Header, statedb *state.StateDB, contractAddr common.Address, input []byte) ([]byte, error) {
+	// Create new call message
+	msg := types.NewMessage(InnerCallFromAddress, &contractAddr, 1, big.NewInt(1), uint64(0xffffffffff), big.NewInt(1), input, false, types.NormalTxType)
 
-	context := NewEVMContext(msg, header, bc, nil)
-	evm := vm.NewEVM(context, state, bc.Config(), vm.Config{})
+	// The header chain does not resolve system configurations, which are read
+	// with inner calls themselves
+	context := NewEVMContext(msg, header, bc.hc, nil)
+	evm := vm.NewEVM(context, statedb, bc.Config(), vm.Config{})
 
 	res, _, err := evm.Call(vm.AccountRef(msg.From()), *msg.To(), input, msg.Gas(), big.NewInt(0))
 	if err != nil {
@@ -7,10 +7,10 @@ import (
 	"sync"
 
 	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
 	"github.com/PlatONEnetwork/PlatONE-Go/life/compiler"
 	"github.com/PlatONEnetwork/PlatONE-Go/log"
 	"github.com/hashicorp/golang-lru/simplelru"
-	"github.com/syndtr/goleveldb/leveldb"
 )
 
 var (
@@ -21,7 +21,7 @@ var (
 
 type WasmLDBCache struct {
 	lru  *simplelru.LRU
-	db   *leveldb.DB
+	db   ethdb.Database
 	lock sync.RWMutex
 }
 
@@ -30,14 +30,25 @@ type WasmModule struct {
 	FunctionCode []compiler.InterpreterCode
 }
 
+// Copy returns a copy of m sharing its code, whose sections may be set without
+// changing m.
+func (m *WasmModule) Copy() *WasmModule {
+	base := *m.Module.Base
+	module := *m.Module
+	module.Base = &base
+	return &WasmModule{Module: &module, FunctionCode: m.FunctionCode}
+}
+
 func WasmCache() *WasmLDBCache {
 	return wasmCache
 }
 
-func SetWasmDB(dataDir string) error {
+// SetWasmDB backs the WASM cache by the database in the cache directory of
+// dataDir, opened with the given storage engine.
+func SetWasmDB(dataDir string, engine string) error {
 	path := filepath.Join(dataDir, DefaultWasmCacheDir)
 
-	db, err := leveldb.OpenFile(path, nil)
+	db, err := ethdb.Open(engine, path, 0, 0)
 	if err != nil {
 		return err
 	}
@@ -61,14 +72,14 @@ func NewWasmCache(size int) (*WasmLDBCache, error) {
 			return
 		}
 		if w.db != nil {
-			if ok, err := w.db.Has(addr.Bytes(), nil); err != nil || !ok {
+			if ok, err := w.db.Has(addr.Bytes()); err != nil || !ok {
 				buffer := new(bytes.Buffer)
 				enc := gob.NewEncoder(buffer)
 				if err := enc.Encode(module); err != nil {
 					log.Error("encode module", "err", err)
 					return
 				}
-				w.db.Put(addr.Bytes(), buffer.Bytes(), nil)
+				w.db.Put(addr.Bytes(), buffer.Bytes())
 			}
 		}
 	}
@@ -83,7 +94,7 @@ func NewWasmCache(size int) (*WasmLDBCache, error) {
 	return w, nil
 }
 
-func NewWasmLDBCache(size int, db *leveldb.DB) (*WasmLDBCache, error) {
+func NewWasmLDBCache(size int, db ethdb.Database) (*WasmLDBCache, error) {
 	w, err := NewWasmCache(size)
 	if err != nil {
 		return nil, err
@@ -92,7 +103,7 @@ func NewWasmLDBCache(size int, db *leveldb.DB) (*WasmLDBCache, error) {
 	return w, nil
 }
 
-func (w *WasmLDBCache) SetDB(db *leveldb.DB) {
+func (w *WasmLDBCache) SetDB(db ethdb.Database) {
 	w.db = db
 }
 
@@ -117,7 +128,7 @@ func (w *WasmLDBCache) Get(key common.Address) (*WasmModule, bool) {
 	value, ok := w.lru.Get(key)
 	if !ok {
 		if w.db != nil {
-			if value, err := w.db.Get(key.Bytes(), nil); err == nil {
+			if value, err := w.db.Get(key.Bytes()); err == nil {
 				module := WasmModule{}
 				buffer := bytes.NewReader(value)
 				dec := gob.NewDecoder(buffer)
@@ -142,7 +153,7 @@ func (w *WasmLDBCache) Contains(key common.Address) bool {
 	if !w.lru.Contains(key) {
 		ok := false
 		if w.db != nil {
-			ok, _ = w.db.Has(key.Bytes(), nil)
+			ok, _ = w.db.Has(key.Bytes())
 		}
 		return ok
 	}
@@ -157,7 +168,7 @@ func (w *WasmLDBCache) Peek(key common.Address) (*WasmModule, bool) {
 	value, ok := w.lru.Peek(key)
 	if !ok {
 		if w.db != nil {
-			if value, err := w.db.Get(key.Bytes(), nil); err == nil {
+			if value, err := w.db.Get(key.Bytes()); err == nil {
 				var module WasmModule
 				buffer := bytes.NewReader(value)
 				dec := gob.NewDecoder(buffer)
@@ -190,7 +201,7 @@ func (w *WasmLDBCache) Remove(key common.Address) {
 	w.lock.Lock()
 	w.lru.Remove(key)
 	if w.db != nil {
-		w.db.Delete(key.Bytes(), nil)
+		w.db.Delete(key.Bytes())
 	}
 	w.lock.Unlock()
 }
@@ -0,0 +1,270 @@
+package core
+
+import (
+	"runtime"
+	"sync"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/state"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/vm"
+	"github.com/PlatONEnetwork/PlatONE-Go/metrics"
+	"github.com/PlatONEnetwork/PlatONE-Go/params"
+)
+
+var (
+	// ParallelBatch is the number of transactions executed ahead at once.
+	ParallelBatch = 4 * runtime.NumCPU()
+
+	parallelMergedCounter     = metrics.NewRegisteredCounter("chain/parallel/merged", nil)
+	parallelReexecutedCounter = metrics.NewRegisteredCounter("chain/parallel/reexecuted", nil)
+	parallelSequentialCounter = metrics.NewRegisteredCounter("chain/parallel/sequential", nil)
+)
+
+// txSpeculation is a transaction executed on its own copy of the state.
+type txSpeculation struct {
+	state   *state.StateDB
+	rw      *state.ReadWriteSet
+	receipt *types.Receipt
+	gas     uint64
+	applied int    // Number of transactions applied when the state was copied
+	base    uint64 // Gas pool when the state was copied
+	pool    uint64 // Gas left in the copy of the gas pool
+	err     error
+}
+
+// ParallelExecutor applies transactions to a state like ApplyTransaction,
+// executing batches of them in parallel on copies of the state first. The
+// result of such an execution is taken over if none of the accounts the
+// transaction touched was written by a transaction applied since the state
+// was copied, otherwise the transaction is executed again. Either way the
+// state ends up the same as when applying the transactions one by one.
+type ParallelExecutor struct {
+	config  *params.ChainConfig
+	bc      ChainContext
+	author  *common.Address
+	gp      *GasPool
+	statedb *state.StateDB
+	header  *types.Header
+	bhash   common.Hash
+	cfg     vm.Config
+	sysCfg  *common.SystemConfig // System parameters in force for the block
+
+	applied int                            // Number of transactions applied
+	specs   map[common.Hash]*txSpeculation // Executed transactions not applied yet, nil for those left to Apply
+	written map[common.Address]int         // Number of transactions applied when each account was last written
+}
+
+// NewParallelExecutor creates an executor applying transactions to statedb,
+// in the block with the given header and hash.
+func NewParallelExecutor(config *params.ChainConfig, bc ChainContext, author *common.Address, gp *GasPool, statedb *state.StateDB, header *types.Header, bhash common.Hash, cfg vm.Config) *ParallelExecutor {
+	return &ParallelExecutor{
+		config:  config,
+		bc:      bc,
+		author:  author,
+		gp:      gp,
+		statedb: statedb,
+		header:  header,
+		bhash:   bhash,
+		cfg:     cfg,
+		sysCfg:  systemConfigAt(bc, header),
+		specs:   make(map[common.Hash]*txSpeculation),
+		written: make(map[common.Address]int),
+	}
+}
+
+// Speculated reports whether tx was part of a batch and not applied yet.
+func (e *ParallelExecutor) Speculated(tx *types.Transaction) bool {
+	_, ok := e.specs[tx.Hash()]
+	return ok
+}
+
+// Speculate starts a new batch, executing txs in parallel on copies of the
+// state as it is now. Transactions of the system contracts are left to
+// Apply, as are all transactions when gas is charged: they all write the gas
+// contract and could not be taken over. The executions of the earlier batches
+// are kept, except those that saw accounts written since.
+func (e *ParallelExecutor) Speculate(txs types.Transactions) {
+	for hash, spec := range e.specs {
+		if spec != nil && e.stale(spec) {
+			delete(e.specs, hash)
+		}
+	}
+	var (
+		wg     sync.WaitGroup
+		specs  = make([]*txSpeculation, len(txs))
+		useGas = e.sysCfg.GetIsTxUseGas()
+		pool   = e.gp.Gas()
+	)
+	for i, tx := range txs {
+		if _, ok := e.specs[tx.Hash()]; ok {
+			continue
+		}
+		if useGas || tx.Type() == types.FwTxType || tx.Type() == types.MigTxType || callsSystemContract(tx) {
+			e.specs[tx.Hash()] = nil
+			continue
+		}
+		wg.Add(1)
+		go func(i int, tx *types.Transaction) {
+			defer wg.Done()
+			specs[i] = e.speculate(tx, pool)
+		}(i, tx)
+	}
+	wg.Wait()
+
+	for i, spec := range specs {
+		if spec != nil {
+			e.specs[txs[i].Hash()] = spec
+		}
+	}
+}
+
+func (e *ParallelExecutor) speculate(tx *types.Transaction, pool uint64) *txSpeculation {
+	statedb := e.statedb.Copy()
+	rw := state.NewReadWriteSet()
+	statedb.TrackAccess(rw)
+	statedb.Prepare(tx.Hash(), e.bhash, 0)
+
+	gp := new(GasPool).AddGas(pool)
+	var usedGas uint64
+	receipt, gas, err := ApplyTransaction(e.config, e.bc, e.author, gp, statedb, e.header, tx, &usedGas, e.cfg)
+	return &txSpeculation{state: statedb, rw: rw, receipt: receipt, gas: gas, applied: e.applied, base: pool, pool: gp.Gas(), err: err}
+}
+
+// stale reports whether an account spec read or wrote was written since its
+// copy of the state was taken.
+func (e *ParallelExecutor) stale(spec *txSpeculation) bool {
+	for _, accounts := range []map[common.Address]struct{}{spec.rw.Reads, spec.rw.Writes} {
+		for addr := range accounts {
+			if applied, ok := e.written[addr]; ok && applied > spec.applied {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Apply applies tx as the transaction with the given index of the block,
+// adding the gas it used to usedGas.
+func (e *ParallelExecutor) Apply(tx *types.Transaction, index int, usedGas *uint64) (*types.Receipt, error) {
+	e.statedb.Prepare(tx.Hash(), e.bhash, index)
+
+	spec := e.specs[tx.Hash()]
+	delete(e.specs, tx.Hash())
+	e.applied++
+	if spec == nil {
+		parallelSequentialCounter.Inc(1)
+	} else if spec.err == nil && !e.stale(spec) && e.gp.Gas() >= e.gasBought(tx) {
+		// Nothing the transaction saw changed and the gas pool covers it as
+		// it did in the copy, the execution holds
+		e.statedb.MergeWrites(spec.state, spec.rw)
+		if spec.pool >= spec.base {
+			e.gp.AddGas(spec.pool - spec.base)
+		} else {
+			e.gp.SubGas(spec.base - spec.pool)
+		}
+		for addr := range spec.rw.Writes {
+			e.written[addr] = e.applied
+		}
+		*usedGas += spec.gas
+
+		receipt := spec.receipt
+		receipt.CumulativeGasUsed = *usedGas
+		receipt.Logs = e.statedb.GetLogs(tx.Hash())
+		parallelMergedCounter.Inc(1)
+		return receipt, nil
+	} else {
+		parallelReexecutedCounter.Inc(1)
+	}
+
+	rw := state.NewReadWriteSet()
+	e.statedb.TrackAccess(rw)
+	receipt, _, err := ApplyTransaction(e.config, e.bc, e.author, e.gp, e.statedb, e.header, tx, usedGas, e.cfg)
+	e.statedb.TrackAccess(nil)
+	for addr := range rw.Writes {
+		e.written[addr] = e.applied
+	}
+	return receipt, err
+}
+
+// gasBought returns the most gas the execution of tx takes from the gas pool
+// before giving back what is left.
+func (e *ParallelExecutor) gasBought(tx *types.Transaction) uint64 {
+	if limit := uint64(e.sysCfg.GetTxGasLimit()); limit > tx.Gas() {
+		return limit
+	}
+	return tx.Gas()
+}
+
+// ScheduleParallel picks at most n of txs to execute in parallel, the first
+// one always. Transactions with an access list are left out when the accounts
+// they declared, send from or call overlap those of a picked transaction with
+// one, so that the batch holds no conflicts known up front. Transactions
+// without one are picked regardless and left to the conflict detection.
+func ScheduleParallel(signer types.Signer, txs types.Transactions, n int) types.Transactions {
+	var (
+		batch    types.Transactions
+		reserved = make(map[common.Address]struct{})
+	)
+	for i, tx := range txs {
+		if len(batch) >= n {
+			break
+		}
+		if tx.AccessList() == nil {
+			batch = append(batch, tx)
+			continue
+		}
+		footprint := tx.AccessList().Addresses()
+		if from, err := types.Sender(signer, tx); err == nil {
+			footprint = append(footprint, from)
+		}
+		for _, to := range callTargets(tx) {
+			footprint = append(footprint, to)
+		}
+		overlaps := false
+		for _, addr := range footprint {
+			if _, ok := reserved[addr]; ok {
+				overlaps = true
+				break
+			}
+		}
+		if overlaps && i > 0 {
+			continue
+		}
+		for _, addr := range footprint {
+			reserved[addr] = struct{}{}
+		}
+		batch = append(batch, tx)
+	}
+	return batch
+}
+
+// callTargets returns the accounts tx calls: its recipient, or the recipients
+// of its calls for a batch transaction.
+func callTargets(tx *types.Transaction) []common.Address {
+	if tx.Type() == types.BatchTxType {
+		calls, _ := types.DecodeBatchCalls(tx.Data())
+		targets := make([]common.Address, 0, len(calls))
+		for _, call := range calls {
+			if call.To != nil {
+				targets = append(targets, *call.To)
+			}
+		}
+		return targets
+	}
+	if tx.To() == nil {
+		return nil
+	}
+	return []common.Address{*tx.To()}
+}
+
+// callsSystemContract reports whether tx calls a system contract, directly or
+// in a batch.
+func callsSystemContract(tx *types.Transaction) bool {
+	for _, to := range callTargets(tx) {
+		if isSystemContract(&to) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,277 @@
+package core
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"math/rand"
+	"testing"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/common/hexutil"
+	"github.com/PlatONEnetwork/PlatONE-Go/common/syscontracts"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/state"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/vm"
+	"github.com/PlatONEnetwork/PlatONE-Go/crypto"
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/params"
+	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
+)
+
+// counterCode increments storage slot 0 and emits an empty log.
+var counterCode = hexutil.MustDecode("0x60005460010160005560006000a000")
+
+// determinismEnv is a state with funded accounts, counter contracts and WASM
+// key-value contracts, on which the same transactions are applied sequentially
+// and in parallel.
+type determinismEnv struct {
+	config    *params.ChainConfig
+	keys      []*ecdsa.PrivateKey
+	contracts []common.Address
+	wasm      []common.Address
+	db        state.Database
+	root      common.Hash
+}
+
+func newDeterminismEnv(t *testing.T, accounts, contracts, wasmContracts int) *determinismEnv {
+	env := &determinismEnv{
+		config: &params.ChainConfig{ChainID: big.NewInt(1), VMInterpreter: "evm"},
+		db:     state.NewDatabase(ethdb.NewMemDatabase()),
+	}
+	var wasmCode []byte
+	if wasmContracts > 0 {
+		env.config.VMInterpreter = "all"
+		code, err := ioutil.ReadFile("../life/contract/getsettest.wasm")
+		if err != nil {
+			t.Fatal(err)
+		}
+		abi, err := ioutil.ReadFile("../life/contract/getsettest.cpp.abi.json")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if wasmCode, err = rlp.EncodeToBytes([][]byte{common.Int64ToBytes(1), code, abi}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	statedb, _ := state.New(common.Hash{}, env.db)
+	for i := 0; i < accounts; i++ {
+		key, _ := crypto.GenerateKey()
+		env.keys = append(env.keys, key)
+		statedb.AddBalance(crypto.PubkeyToAddress(key.PublicKey), big.NewInt(1e18))
+	}
+	for i := 0; i < contracts; i++ {
+		addr := common.BigToAddress(big.NewInt(int64(0x1000 + i)))
+		statedb.SetCode(addr, counterCode)
+		statedb.SetNonce(addr, 1)
+		env.contracts = append(env.contracts, addr)
+	}
+	for i := 0; i < wasmContracts; i++ {
+		addr := common.BigToAddress(big.NewInt(int64(0x2000 + i)))
+		statedb.SetCode(addr, wasmCode)
+		statedb.SetNonce(addr, 1)
+		env.wasm = append(env.wasm, addr)
+	}
+	root, err := statedb.Commit(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	env.db.TrieDB().Commit(root, false)
+	env.root = root
+	return env
+}
+
+// randomTxs creates transfers between the accounts, to new accounts, calls of
+// the contracts and of a system contract, so that some transactions conflict
+// and some do not.
+func (env *determinismEnv) randomTxs(t *testing.T, rnd *rand.Rand, n int) types.Transactions {
+	signer := types.MakeSigner(env.config)
+	kinds := 3
+	if len(env.wasm) > 0 {
+		kinds = 5
+	}
+	txs := make(types.Transactions, n)
+	for i := range txs {
+		key := env.keys[rnd.Intn(len(env.keys))]
+		var tx *types.Transaction
+		switch rnd.Intn(kinds) {
+		case 0:
+			to := crypto.PubkeyToAddress(env.keys[rnd.Intn(len(env.keys))].PublicKey)
+			tx = types.NewTransaction(uint64(i), to, big.NewInt(rnd.Int63n(1000)), 21000, big.NewInt(0), nil, types.NormalTxType)
+		case 1:
+			to := common.BigToAddress(big.NewInt(rnd.Int63()))
+			tx = types.NewTransaction(uint64(i), to, big.NewInt(1), 21000, big.NewInt(0), nil, types.NormalTxType)
+		case 2:
+			to := env.contracts[rnd.Intn(len(env.contracts))]
+			tx = types.NewTransaction(uint64(i), to, big.NewInt(0), 100000, big.NewInt(0), []byte{0x01}, types.NormalTxType)
+		case 3:
+			to := env.wasm[rnd.Intn(len(env.wasm))]
+			data, err := common.GenerateWasmData(common.CallContractFlag, "Set", []interface{}{fmt.Sprintf("key%d", rnd.Intn(3)), rnd.Int31()})
+			if err != nil {
+				t.Fatal(err)
+			}
+			tx = types.NewTransaction(uint64(i), to, big.NewInt(0), 1000000, big.NewInt(0), data, types.NormalTxType)
+		default:
+			target := env.wasm[rnd.Intn(len(env.wasm))]
+			data, err := common.GenerateWasmData(common.CallContractFlag, "setSponsorPolicy", []interface{}{`["` + target.Hex() + `"]`, uint64(rnd.Int63n(1e6))})
+			if err != nil {
+				t.Fatal(err)
+			}
+			tx = types.NewTransaction(uint64(i), syscontracts.FeeSponsorManagementAddress, big.NewInt(0), 1000000, big.NewInt(0), data, types.NormalTxType)
+		}
+		signed, err := types.SignTx(tx, signer, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		txs[i] = signed
+	}
+	return txs
+}
+
+// apply applies txs to a fresh copy of the state, returning the root and
+// receipts.
+func (env *determinismEnv) apply(t *testing.T, txs types.Transactions, parallel bool) (common.Hash, types.Receipts) {
+	statedb, err := state.New(env.root, env.db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		header   = &types.Header{Number: big.NewInt(1), Time: big.NewInt(1), GasLimit: uint64(common.SysCfg.GetBlockGasLimit())}
+		gp       = new(GasPool).AddGas(header.GasLimit)
+		usedGas  = new(uint64)
+		receipts types.Receipts
+		executor = NewParallelExecutor(env.config, nil, &common.Address{}, gp, statedb, header, common.Hash{}, vm.Config{})
+	)
+	for i, tx := range txs {
+		var receipt *types.Receipt
+		if parallel {
+			if !executor.Speculated(tx) {
+				end := i + ParallelBatch
+				if end > len(txs) {
+					end = len(txs)
+				}
+				executor.Speculate(txs[i:end])
+			}
+			receipt, err = executor.Apply(tx, i, usedGas)
+		} else {
+			statedb.Prepare(tx.Hash(), common.Hash{}, i)
+			receipt, _, err = ApplyTransaction(env.config, nil, &common.Address{}, gp, statedb, header, tx, usedGas, vm.Config{})
+		}
+		if err != nil {
+			t.Fatalf("transaction %d: %v", i, err)
+		}
+		receipts = append(receipts, receipt)
+	}
+	return statedb.IntermediateRoot(true), receipts
+}
+
+// Tests that parallel execution yields the state and receipts of sequential
+// execution, over random blocks with varying amounts of conflicts, calling EVM
+// and WASM contracts and a system contract.
+func TestParallelExecutionDeterminism(t *testing.T) {
+	defer common.SetCurrentInterpreterType(common.GetCurrentInterpreterType())
+	common.SetCurrentInterpreterType("all")
+
+	rnd := rand.New(rand.NewSource(1))
+	for _, accounts := range []int{2, 10, 50} {
+		env := newDeterminismEnv(t, accounts, 3, 2)
+		for run := 0; run < 5; run++ {
+			txs := env.randomTxs(t, rnd, 100)
+
+			wantRoot, want := env.apply(t, txs, false)
+			root, receipts := env.apply(t, txs, true)
+			if root != wantRoot {
+				t.Fatalf("accounts %d run %d: root %x, want %x", accounts, run, root, wantRoot)
+			}
+			for i, r := range receipts {
+				w := want[i]
+				if r.Status != w.Status || r.GasUsed != w.GasUsed || r.CumulativeGasUsed != w.CumulativeGasUsed || r.Bloom != w.Bloom || len(r.Logs) != len(w.Logs) {
+					t.Fatalf("accounts %d run %d: receipt %d = %+v, want %+v", accounts, run, i, r, w)
+				}
+				for j, l := range r.Logs {
+					if l.Index != w.Logs[j].Index || l.TxIndex != w.Logs[j].TxIndex || l.TxHash != w.Logs[j].TxHash {
+						t.Fatalf("accounts %d run %d: log %d of receipt %d = %+v, want %+v", accounts, run, j, i, l, w.Logs[j])
+					}
+				}
+			}
+		}
+	}
+}
+
+// Tests that a transaction accessing storage its access list leaves out
+// fails without changing the state.
+func TestAccessListEnforcement(t *testing.T) {
+	defer common.SetCurrentInterpreterType(common.GetCurrentInterpreterType())
+	common.SetCurrentInterpreterType("evm")
+
+	env := newDeterminismEnv(t, 1, 1, 0)
+	signer := types.MakeSigner(env.config)
+	contract := env.contracts[0]
+	for i, tt := range []struct {
+		list   types.AccessList
+		status uint64
+	}{
+		{nil, types.ReceiptStatusSuccessful},
+		{types.AccessList{{Address: contract}}, types.ReceiptStatusSuccessful},
+		{types.AccessList{{Address: contract, StoragePrefixes: []hexutil.Bytes{{0x00}}}}, types.ReceiptStatusSuccessful},
+		{types.AccessList{{Address: contract, StoragePrefixes: []hexutil.Bytes{{0xff}}}}, types.ReceiptStatusFailed},
+	} {
+		tx := types.NewTransaction(0, contract, big.NewInt(0), 100000, big.NewInt(0), []byte{0x01}, types.NormalTxType)
+		if tt.list != nil {
+			tx = tx.WithAccessList(tt.list)
+		}
+		tx, _ = types.SignTx(tx, signer, env.keys[0])
+
+		statedb, _ := state.New(env.root, env.db)
+		header := &types.Header{Number: big.NewInt(1), Time: big.NewInt(1), GasLimit: uint64(common.SysCfg.GetBlockGasLimit())}
+		statedb.Prepare(tx.Hash(), common.Hash{}, 0)
+		receipt, _, err := ApplyTransaction(env.config, nil, &common.Address{}, new(GasPool).AddGas(header.GasLimit), statedb, header, tx, new(uint64), vm.Config{})
+		if err != nil {
+			t.Fatalf("test %d: %v", i, err)
+		}
+		if receipt.Status != tt.status {
+			t.Fatalf("test %d: status %d, want %d", i, receipt.Status, tt.status)
+		}
+		counter := statedb.GetState(contract, make([]byte, 32))
+		if incremented := len(counter) > 0 && counter[len(counter)-1] == 1; incremented != (tt.status == types.ReceiptStatusSuccessful) {
+			t.Fatalf("test %d: counter %x", i, counter)
+		}
+	}
+}
+
+// Tests that transactions declaring overlapping accesses are not scheduled
+// into the same batch.
+func TestScheduleParallel(t *testing.T) {
+	env := newDeterminismEnv(t, 4, 2, 0)
+	signer := types.MakeSigner(env.config)
+	newTx := func(key int, to common.Address, list types.AccessList) *types.Transaction {
+		tx := types.NewTransaction(0, to, big.NewInt(0), 100000, big.NewInt(0), nil, types.NormalTxType)
+		if list != nil {
+			tx = tx.WithAccessList(list)
+		}
+		tx, _ = types.SignTx(tx, signer, env.keys[key])
+		return tx
+	}
+	shared := common.Address{0xee}
+	txs := types.Transactions{
+		newTx(0, env.contracts[0], types.AccessList{{Address: shared}}),
+		newTx(1, env.contracts[1], types.AccessList{{Address: shared}}), // shares an account with the first
+		newTx(2, env.contracts[0], types.AccessList{}),                  // calls the contract of the first
+		newTx(3, env.contracts[1], nil),                                 // declares nothing
+		newTx(2, env.contracts[1], types.AccessList{}),
+	}
+	batch := ScheduleParallel(signer, txs, len(txs))
+	want := types.Transactions{txs[0], txs[3], txs[4]}
+	if len(batch) != len(want) {
+		t.Fatalf("scheduled %d transactions, want %d", len(batch), len(want))
+	}
+	for i := range want {
+		if batch[i] != want[i] {
+			t.Fatalf("transaction %d of the batch is %x, want %x", i, batch[i].Hash(), want[i].Hash())
+		}
+	}
+	if batch := ScheduleParallel(signer, txs, 2); len(batch) != 2 {
+		t.Fatalf("scheduled %d transactions, want 2", len(batch))
+	}
+}
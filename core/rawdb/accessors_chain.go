@@ -59,8 +59,28 @@ func SetBlockReceiptsCache(number uint64, hash common.Hash, receipts types.Recei
 	blockReceiptsCache.setCache(number, hash, receipts)
 }
 
+// readAncient retrieves the item of the given kind of the block with the given
+// hash and number if it was moved to the freezer of db.
+func readAncient(db DatabaseReader, kind string, hash common.Hash, number uint64) []byte {
+	ancients, ok := db.(AncientReader)
+	if !ok || number >= ancients.Ancients() {
+		return nil
+	}
+	frozen, err := ancients.Ancient(freezerHashTable, number)
+	if err != nil || common.BytesToHash(frozen) != hash {
+		return nil
+	}
+	data, _ := ancients.Ancient(kind, number)
+	return data
+}
+
 // ReadCanonicalHash retrieves the hash assigned to a canonical block number.
 func ReadCanonicalHash(db DatabaseReader, number uint64) common.Hash {
+	if ancients, ok := db.(AncientReader); ok && number < ancients.Ancients() {
+		if data, err := ancients.Ancient(freezerHashTable, number); err == nil {
+			return common.BytesToHash(data)
+		}
+	}
 	data, _ := db.Get(headerHashKey(number))
 	if len(data) == 0 {
 		return common.Hash{}
@@ -140,6 +160,32 @@ func WriteHeadFastBlockHash(db DatabaseWriter, hash common.Hash) {
 	}
 }
 
+// ReadCheckpointHash retrieves the hash of the block of the state checkpoint
+// the chain was bootstrapped from, if any.
+func ReadCheckpointHash(db DatabaseReader) common.Hash {
+	data, _ := db.Get(checkpointKey)
+	if len(data) == 0 {
+		return common.Hash{}
+	}
+	return common.BytesToHash(data)
+}
+
+// WriteCheckpointHash stores the hash of the block of the state checkpoint the
+// chain was bootstrapped from.
+func WriteCheckpointHash(db DatabaseWriter, hash common.Hash) {
+	if err := db.Put(checkpointKey, hash.Bytes()); err != nil {
+		log.Crit("Failed to store checkpoint block's hash", "err", err)
+	}
+}
+
+// DeleteCheckpointHash removes the hash of the block of the state checkpoint
+// the chain was bootstrapped from.
+func DeleteCheckpointHash(db DatabaseDeleter) {
+	if err := db.Delete(checkpointKey); err != nil {
+		log.Crit("Failed to delete checkpoint block's hash", "err", err)
+	}
+}
+
 // ReadFastTrieProgress retrieves the number of tries nodes fast synced to allow
 // reporting correct numbers across restarts.
 func ReadFastTrieProgress(db DatabaseReader) uint64 {
@@ -158,14 +204,38 @@ func WriteFastTrieProgress(db DatabaseWriter, count uint64) {
 	}
 }
 
+// ReadReceiptsTail retrieves the number of the oldest block whose receipts were
+// not pruned, 0 if none were.
+func ReadReceiptsTail(db DatabaseReader) uint64 {
+	data, _ := db.Get(receiptsTailKey)
+	if len(data) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(data)
+}
+
+// WriteReceiptsTail stores the number of the oldest block whose receipts were
+// not pruned.
+func WriteReceiptsTail(db DatabaseWriter, number uint64) {
+	if err := db.Put(receiptsTailKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store receipts tail", "err", err)
+	}
+}
+
 // ReadHeaderRLP retrieves a block header in its raw RLP database encoding.
 func ReadHeaderRLP(db DatabaseReader, hash common.Hash, number uint64) rlp.RawValue {
+	if data := readAncient(db, freezerHeaderTable, hash, number); len(data) > 0 {
+		return data
+	}
 	data, _ := db.Get(headerKey(number, hash))
 	return data
 }
 
 // HasHeader verifies the existence of a block header corresponding to the hash.
 func HasHeader(db DatabaseReader, hash common.Hash, number uint64) bool {
+	if data := readAncient(db, freezerHashTable, hash, number); len(data) > 0 {
+		return true
+	}
 	if has, err := db.Has(headerKey(number, hash)); !has || err != nil {
 		return false
 	}
@@ -222,6 +292,9 @@ func DeleteHeader(db DatabaseDeleter, hash common.Hash, number uint64) {
 
 // ReadBodyRLP retrieves the block body (transactions) in RLP encoding.
 func ReadBodyRLP(db DatabaseReader, hash common.Hash, number uint64) rlp.RawValue {
+	if data := readAncient(db, freezerBodiesTable, hash, number); len(data) > 0 {
+		return data
+	}
 	data, _ := db.Get(blockBodyKey(number, hash))
 	return data
 }
@@ -235,6 +308,9 @@ func WriteBodyRLP(db DatabaseWriter, hash common.Hash, number uint64, rlp rlp.Ra
 
 // HasBody verifies the existence of a block body corresponding to the hash.
 func HasBody(db DatabaseReader, hash common.Hash, number uint64) bool {
+	if data := readAncient(db, freezerHashTable, hash, number); len(data) > 0 {
+		return true
+	}
 	if has, err := db.Has(blockBodyKey(number, hash)); !has || err != nil {
 		return false
 	}
@@ -278,7 +354,10 @@ func ReadReceipts(db DatabaseReader, hash common.Hash, number uint64) types.Rece
 		return c
 	}
 	// Retrieve the flattened receipt slice
-	data, _ := db.Get(blockReceiptsKey(number, hash))
+	data := readAncient(db, freezerReceiptsTable, hash, number)
+	if len(data) == 0 {
+		data, _ = db.Get(blockReceiptsKey(number, hash))
+	}
 	if len(data) == 0 {
 		return nil
 	}
@@ -295,6 +374,18 @@ func ReadReceipts(db DatabaseReader, hash common.Hash, number uint64) types.Rece
 	return receipts
 }
 
+// HasAncientReceipts reports whether the receipts of the canonical block with
+// the given number were moved to the freezer of db. The receipts pruned before
+// their block was frozen are not.
+func HasAncientReceipts(db DatabaseReader, number uint64) bool {
+	ancients, ok := db.(AncientReader)
+	if !ok || number >= ancients.Ancients() {
+		return false
+	}
+	data, err := ancients.Ancient(freezerReceiptsTable, number)
+	return err == nil && len(data) > 0
+}
+
 // WriteReceipts stores all the transaction receipts belonging to a block.
 func WriteReceipts(db DatabaseWriter, hash common.Hash, number uint64, receipts types.Receipts) {
 	// Convert the receipts into their storage form and serialize them
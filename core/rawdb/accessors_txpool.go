@@ -0,0 +1,63 @@
+package rawdb
+
+import (
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/log"
+	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
+)
+
+// ReadPoolTxRange retrieves the sequence number of the oldest persisted pool
+// transaction and the one the next transaction will be given.
+func ReadPoolTxRange(db DatabaseReader) (first, next uint64) {
+	data, _ := db.Get(poolTxRangeKey)
+	if len(data) == 0 {
+		return 0, 0
+	}
+	var seqs [2]uint64
+	if err := rlp.DecodeBytes(data, &seqs); err != nil {
+		log.Error("Invalid pool transaction range RLP", "err", err)
+		return 0, 0
+	}
+	return seqs[0], seqs[1]
+}
+
+// WritePoolTxRange stores the sequence numbers of the persisted pool transactions.
+func WritePoolTxRange(db DatabaseWriter, first, next uint64) {
+	data, _ := rlp.EncodeToBytes([2]uint64{first, next})
+	if err := db.Put(poolTxRangeKey, data); err != nil {
+		log.Crit("Failed to store pool transaction range", "err", err)
+	}
+}
+
+// ReadPoolTransaction retrieves the pool transaction persisted with the given
+// sequence number.
+func ReadPoolTransaction(db DatabaseReader, seq uint64) *types.Transaction {
+	data, _ := db.Get(poolTxKey(seq))
+	if len(data) == 0 {
+		return nil
+	}
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(data, tx); err != nil {
+		log.Error("Invalid pool transaction RLP", "seq", seq, "err", err)
+		return nil
+	}
+	return tx
+}
+
+// WritePoolTransaction persists a pool transaction with the given sequence number.
+func WritePoolTransaction(db DatabaseWriter, seq uint64, tx *types.Transaction) {
+	data, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		log.Crit("Failed to RLP encode pool transaction", "err", err)
+	}
+	if err := db.Put(poolTxKey(seq), data); err != nil {
+		log.Crit("Failed to store pool transaction", "err", err)
+	}
+}
+
+// DeletePoolTransaction removes the pool transaction with the given sequence number.
+func DeletePoolTransaction(db DatabaseDeleter, seq uint64) {
+	if err := db.Delete(poolTxKey(seq)); err != nil {
+		log.Crit("Failed to delete pool transaction", "err", err)
+	}
+}
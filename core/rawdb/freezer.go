@@ -0,0 +1,319 @@
+package rawdb
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/log"
+	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
+)
+
+// Kinds of the items stored for every frozen block.
+const (
+	freezerHashTable     = "hashes"
+	freezerHeaderTable   = "headers"
+	freezerBodiesTable   = "bodies"
+	freezerReceiptsTable = "receipts"
+)
+
+var freezerTables = []string{freezerHashTable, freezerHeaderTable, freezerBodiesTable, freezerReceiptsTable}
+
+const (
+	// freezerRecheckInterval is the interval of the checks for blocks old
+	// enough to be frozen.
+	freezerRecheckInterval = time.Minute
+
+	// freezerBatchLimit is the maximum number of blocks frozen at once.
+	freezerBatchLimit = 30000
+)
+
+// Freezer is an append-only store of the canonical blocks, from the genesis
+// block on, with one flat file of every kind of item and an index of it.
+type Freezer struct {
+	frozen uint64 // Number of blocks frozen, accessed atomically
+
+	tables map[string]*freezerTable
+	lock   sync.Mutex // Serializes the appends and truncations
+}
+
+// OpenFreezer opens the freezer in dir, creating it if needed.
+func OpenFreezer(dir string) (*Freezer, error) {
+	f := &Freezer{tables: make(map[string]*freezerTable)}
+	for _, name := range freezerTables {
+		table, err := newFreezerTable(dir, name)
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		f.tables[name] = table
+	}
+	// A crash may have left the tables with different lengths
+	frozen := f.tables[freezerHashTable].Items()
+	for _, table := range f.tables {
+		if items := table.Items(); items < frozen {
+			frozen = items
+		}
+	}
+	if err := f.truncate(frozen); err != nil {
+		f.Close()
+		return nil, err
+	}
+	atomic.StoreUint64(&f.frozen, frozen)
+	log.Info("Opened ancient block store", "dir", dir, "blocks", frozen)
+	return f, nil
+}
+
+// Ancients returns the number of frozen blocks.
+func (f *Freezer) Ancients() uint64 {
+	return atomic.LoadUint64(&f.frozen)
+}
+
+// Ancient returns the item of the given kind of a frozen block.
+func (f *Freezer) Ancient(kind string, number uint64) ([]byte, error) {
+	table := f.tables[kind]
+	if table == nil {
+		return nil, fmt.Errorf("unknown ancient kind %q", kind)
+	}
+	if number >= f.Ancients() {
+		return nil, errOutOfBounds
+	}
+	return table.Retrieve(number)
+}
+
+// appendAncient adds the items of the block following the frozen ones.
+func (f *Freezer) appendAncient(number uint64, hash common.Hash, header, body, receipts []byte) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	items := map[string][]byte{
+		freezerHashTable:     hash.Bytes(),
+		freezerHeaderTable:   header,
+		freezerBodiesTable:   body,
+		freezerReceiptsTable: receipts,
+	}
+	for _, name := range freezerTables {
+		if err := f.tables[name].Append(number, items[name]); err != nil {
+			// Drop the items of the block already appended to the other tables
+			f.truncate(number)
+			return err
+		}
+	}
+	atomic.StoreUint64(&f.frozen, number+1)
+	return nil
+}
+
+// TruncateAncients drops the frozen blocks from number items onwards.
+func (f *Freezer) TruncateAncients(items uint64) error {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return f.truncate(items)
+}
+
+func (f *Freezer) truncate(items uint64) error {
+	for _, table := range f.tables {
+		if err := table.Truncate(items); err != nil {
+			return err
+		}
+	}
+	if items < f.Ancients() {
+		atomic.StoreUint64(&f.frozen, items)
+	}
+	return nil
+}
+
+// Sync flushes the frozen blocks to disk.
+func (f *Freezer) Sync() error {
+	for _, table := range f.tables {
+		if err := table.Sync(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close closes the files of the freezer.
+func (f *Freezer) Close() error {
+	var errs []error
+	for _, table := range f.tables {
+		if err := table.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%v", errs)
+	}
+	return nil
+}
+
+// ReadAncientBlock assembles the frozen block with the given number back from
+// its header and body.
+func ReadAncientBlock(db AncientReader, number uint64) (*types.Block, error) {
+	data, err := db.Ancient(freezerHeaderTable, number)
+	if err != nil {
+		return nil, err
+	}
+	header := new(types.Header)
+	if err := rlp.DecodeBytes(data, header); err != nil {
+		return nil, fmt.Errorf("invalid header of ancient block %d: %v", number, err)
+	}
+	if data, err = db.Ancient(freezerBodiesTable, number); err != nil {
+		return nil, err
+	}
+	body := new(types.Body)
+	if err := rlp.DecodeBytes(data, body); err != nil {
+		return nil, fmt.Errorf("invalid body of ancient block %d: %v", number, err)
+	}
+	return types.NewBlockWithHeader(header).WithBody(body.Transactions), nil
+}
+
+// freeze moves the canonical blocks at least threshold blocks below the head
+// block of db to the freezer, until quit is closed.
+func (f *Freezer) freeze(db ethdb.Database, threshold uint64, quit chan struct{}) {
+	for {
+		if err := f.freezeBatch(db, threshold); err != nil {
+			log.Error("Failed to freeze ancient blocks", "err", err)
+		}
+		select {
+		case <-quit:
+			return
+		case <-time.After(freezerRecheckInterval):
+		}
+	}
+}
+
+// freezeBatch moves up to freezerBatchLimit of the blocks old enough to the
+// freezer, then deletes them from db. The genesis block stays in db too.
+func (f *Freezer) freezeBatch(db ethdb.Database, threshold uint64) error {
+	// A chain bootstrapped from a state checkpoint lacks the blocks before it
+	if ReadCheckpointHash(db) != (common.Hash{}) {
+		return nil
+	}
+	hash := ReadHeadBlockHash(db)
+	if hash == (common.Hash{}) {
+		return nil
+	}
+	head := ReadHeaderNumber(db, hash)
+	if head == nil || *head < threshold {
+		return nil
+	}
+	var (
+		first = f.Ancients()
+		limit = *head - threshold
+	)
+	if limit > first+freezerBatchLimit {
+		limit = first + freezerBatchLimit
+	}
+	if first >= limit {
+		return nil
+	}
+	start := time.Now()
+
+	hashes := make([]common.Hash, 0, limit-first)
+	for number := first; number < limit; number++ {
+		// The blocks are read from db only, those not frozen yet are there
+		hash, _ := db.Get(headerHashKey(number))
+		if len(hash) == 0 {
+			return fmt.Errorf("canonical hash missing for block %d", number)
+		}
+		header, _ := db.Get(headerKey(number, common.BytesToHash(hash)))
+		if len(header) == 0 {
+			return fmt.Errorf("header missing for block %d", number)
+		}
+		body, _ := db.Get(blockBodyKey(number, common.BytesToHash(hash)))
+		if len(body) == 0 {
+			return fmt.Errorf("body missing for block %d", number)
+		}
+		receipts, _ := db.Get(blockReceiptsKey(number, common.BytesToHash(hash)))
+		if err := f.appendAncient(number, common.BytesToHash(hash), header, body, receipts); err != nil {
+			return err
+		}
+		hashes = append(hashes, common.BytesToHash(hash))
+	}
+	// Make the blocks durable in the freezer before deleting them from db
+	if err := f.Sync(); err != nil {
+		return err
+	}
+	batch := db.NewBatch()
+	for i, hash := range hashes {
+		number := first + uint64(i)
+		if number == 0 {
+			continue
+		}
+		DeleteCanonicalHash(batch, number)
+		DeleteReceipts(batch, hash, number)
+		DeleteBody(batch, hash, number)
+		if err := batch.Delete(headerKey(number, hash)); err != nil {
+			return err
+		}
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	log.Info("Moved blocks to the ancient store", "blocks", len(hashes), "frozen", f.Ancients(), "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// freezerdb is a database whose oldest canonical blocks are in a freezer.
+type freezerdb struct {
+	ethdb.Database
+	*Freezer
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewDatabaseWithFreezer returns db with the blocks stored in the freezer in
+// dir readable through the accessors, moving the canonical blocks at least
+// threshold blocks older than the head block there. A zero threshold only
+// reads the blocks already frozen.
+func NewDatabaseWithFreezer(db ethdb.Database, dir string, threshold uint64) (ethdb.Database, error) {
+	freezer, err := OpenFreezer(dir)
+	if err != nil {
+		return nil, err
+	}
+	fdb := &freezerdb{Database: db, Freezer: freezer, quit: make(chan struct{})}
+	if threshold > 0 {
+		fdb.wg.Add(1)
+		go func() {
+			defer fdb.wg.Done()
+			freezer.freeze(db, threshold, fdb.quit)
+		}()
+	}
+	return fdb, nil
+}
+
+// NewIteratorWithPrefix iterates over the entries of the key-value store, the
+// frozen blocks are not part of them.
+func (db *freezerdb) NewIteratorWithPrefix(prefix []byte) ethdb.Iterator {
+	return db.Database.(ethdb.Iteratee).NewIteratorWithPrefix(prefix)
+}
+
+// Compact compacts the key-value store if its engine supports it.
+func (db *freezerdb) Compact(start, limit []byte) error {
+	if compacter, ok := db.Database.(ethdb.Compacter); ok {
+		return compacter.Compact(start, limit)
+	}
+	return nil
+}
+
+// Close stops moving blocks to the freezer, then closes both stores.
+func (db *freezerdb) Close() {
+	close(db.quit)
+	db.wg.Wait()
+
+	if err := db.Freezer.Close(); err != nil {
+		log.Error("Failed to close ancient block store", "err", err)
+	}
+	db.Database.Close()
+}
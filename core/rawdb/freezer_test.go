@@ -0,0 +1,134 @@
+package rawdb
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
+)
+
+// Tests that a freezer table drops on reopening the items not fully written.
+func TestFreezerTableRepair(t *testing.T) {
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	table, err := newFreezerTable(dir, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := uint64(0); i < 10; i++ {
+		if err := table.Append(i, bytes.Repeat([]byte{byte(i)}, int(i)+1)); err != nil {
+			t.Fatalf("append %d failed: %v", i, err)
+		}
+	}
+	if err := table.Append(11, []byte{11}); err == nil {
+		t.Fatal("appended an item out of order")
+	}
+	table.Close()
+
+	// Cut the data of the last item short, as a crash would
+	data := filepath.Join(dir, "test.rdat")
+	stat, _ := os.Stat(data)
+	if err := os.Truncate(data, stat.Size()-1); err != nil {
+		t.Fatal(err)
+	}
+	if table, err = newFreezerTable(dir, "test"); err != nil {
+		t.Fatal(err)
+	}
+	defer table.Close()
+
+	if items := table.Items(); items != 9 {
+		t.Fatalf("items mismatch after repair: have %d, want 9", items)
+	}
+	for i := uint64(0); i < 9; i++ {
+		if blob, err := table.Retrieve(i); err != nil || !bytes.Equal(blob, bytes.Repeat([]byte{byte(i)}, int(i)+1)) {
+			t.Fatalf("item %d mismatch: have %x, %v", i, blob, err)
+		}
+	}
+	if _, err := table.Retrieve(9); err != errOutOfBounds {
+		t.Fatalf("dropped item error mismatch: have %v, want %v", err, errOutOfBounds)
+	}
+}
+
+// Tests that the blocks moved to the freezer are still read by the accessors.
+func TestFreezerAccessors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "freezer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	kvdb := ethdb.NewMemDatabase()
+	db, err := NewDatabaseWithFreezer(kvdb, dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var blocks []*types.Block
+	parent := common.Hash{}
+	for i := int64(0); i < 10; i++ {
+		block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(i), ParentHash: parent, Extra: []byte("freezer")})
+		WriteBlock(db, block)
+		WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		WriteReceipts(db, block.Hash(), block.NumberU64(), types.Receipts{{CumulativeGasUsed: uint64(i), Logs: []*types.Log{}}})
+		blocks = append(blocks, block)
+		parent = block.Hash()
+	}
+	WriteHeadBlockHash(db, parent)
+
+	freezer := db.(*freezerdb).Freezer
+	if err := freezer.freezeBatch(kvdb, 4); err != nil {
+		t.Fatalf("freezing failed: %v", err)
+	}
+	if frozen := freezer.Ancients(); frozen != 5 {
+		t.Fatalf("frozen blocks mismatch: have %d, want 5", frozen)
+	}
+	for _, block := range blocks {
+		hash, number := block.Hash(), block.NumberU64()
+		if stored, _ := kvdb.Has(headerKey(number, hash)); stored != (number == 0 || number >= 5) {
+			t.Fatalf("block %d: key-value store holding it %v", number, stored)
+		}
+		if have := ReadCanonicalHash(db, number); have != hash {
+			t.Fatalf("block %d: canonical hash mismatch: have %x, want %x", number, have, hash)
+		}
+		if !HasHeader(db, hash, number) || !HasBody(db, hash, number) {
+			t.Fatalf("block %d: header or body reported missing", number)
+		}
+		if have := ReadBlock(db, hash, number); have == nil || have.Hash() != hash {
+			t.Fatalf("block %d: block mismatch: have %v", number, have)
+		}
+		if receipts := ReadReceipts(db, hash, number); len(receipts) != 1 || receipts[0].CumulativeGasUsed != number {
+			t.Fatalf("block %d: receipts mismatch: have %v", number, receipts)
+		}
+		if number < 5 {
+			if have, err := ReadAncientBlock(freezer, number); err != nil || have.Hash() != hash {
+				t.Fatalf("block %d: ancient block mismatch: have %v, %v", number, have, err)
+			}
+		}
+	}
+	// A block of another chain at a frozen height is not read from the freezer
+	other := &types.Header{Number: big.NewInt(3), Extra: []byte("other")}
+	if HasHeader(db, other.Hash(), 3) {
+		t.Fatal("header of another chain reported present")
+	}
+	// Dropping the frozen blocks above a rewound head
+	if err := freezer.TruncateAncients(3); err != nil {
+		t.Fatalf("truncation failed: %v", err)
+	}
+	if HasHeader(db, blocks[3].Hash(), 3) {
+		t.Fatal("truncated header reported present")
+	}
+	if have := ReadCanonicalHash(db, 2); have != blocks[2].Hash() {
+		t.Fatalf("canonical hash mismatch after truncation: have %x, want %x", have, blocks[2].Hash())
+	}
+}
@@ -0,0 +1,135 @@
+package rawdb
+
+import (
+	"bytes"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
+)
+
+// DatabaseStat is the number and size of the entries of a kind of data.
+type DatabaseStat struct {
+	Name  string
+	Count uint64
+	Size  common.StorageSize
+}
+
+// Kinds of the entries of the chain database, in the order of the stats of
+// InspectDatabase.
+const (
+	inspectHeaders = iota
+	inspectCanonicalHashes
+	inspectHeaderNumbers
+	inspectBodies
+	inspectReceipts
+	inspectConfirmSigns
+	inspectTxLookups
+	inspectBloomBits
+	inspectChainIndexes
+	inspectPoolTxs
+	inspectTrieNodes
+	inspectPreimages
+	inspectSnapshotAccounts
+	inspectSnapshotStorage
+	inspectIstanbulSnapshots
+	inspectConfigs
+	inspectMetadata
+	inspectUnknown
+	inspectKinds // Number of kinds
+)
+
+var (
+	// istanbulSnapshotPrefix is the key prefix of the Istanbul vote snapshots.
+	istanbulSnapshotPrefix = []byte("istanbul-snapshot")
+
+	// metadataKeys are the keys of the single entries tracking the chain.
+	metadataKeys = [][]byte{
+		databaseVerisionKey, headHeaderKey, headBlockKey, headFastBlockKey, fastTrieProgressKey,
+		poolTxRangeKey, snapshotRootKey, receiptsTailKey, checkpointKey,
+	}
+
+	// inspectNames are the names of the kinds of entries in the stats.
+	inspectNames = [inspectKinds]string{
+		inspectHeaders:           "Headers",
+		inspectCanonicalHashes:   "Canonical hashes",
+		inspectHeaderNumbers:     "Header numbers",
+		inspectBodies:            "Bodies",
+		inspectReceipts:          "Receipts",
+		inspectConfirmSigns:      "Confirm signatures",
+		inspectTxLookups:         "Transaction lookups",
+		inspectBloomBits:         "Bloom bits",
+		inspectChainIndexes:      "Chain indexes",
+		inspectPoolTxs:           "Pool transactions",
+		inspectTrieNodes:         "Trie nodes, codes and ABIs",
+		inspectPreimages:         "Preimages",
+		inspectSnapshotAccounts:  "Snapshot accounts",
+		inspectSnapshotStorage:   "Snapshot storage",
+		inspectIstanbulSnapshots: "Istanbul snapshots",
+		inspectConfigs:           "Chain configurations",
+		inspectMetadata:          "Metadata",
+		inspectUnknown:           "Unknown",
+	}
+)
+
+// InspectDatabase returns the number and size of the entries of every kind of
+// data of the chain database, keys included, ending with the unknown ones.
+func InspectDatabase(db ethdb.Iteratee) ([]DatabaseStat, error) {
+	stats := make([]DatabaseStat, inspectKinds)
+	for kind := range stats {
+		stats[kind].Name = inspectNames[kind]
+	}
+	it := db.NewIteratorWithPrefix(nil)
+	defer it.Release()
+
+	for it.Next() {
+		key := it.Key()
+		kind := inspectKind(key)
+		stats[kind].Count++
+		stats[kind].Size += common.StorageSize(len(key) + len(it.Value()))
+	}
+	return stats, it.Error()
+}
+
+// inspectKind returns the kind of the entry with the given key.
+func inspectKind(key []byte) int {
+	switch {
+	case bytes.HasPrefix(key, headerPrefix) && len(key) == len(headerPrefix)+8+common.HashLength:
+		return inspectHeaders
+	case bytes.HasPrefix(key, headerPrefix) && len(key) == len(headerPrefix)+8+len(headerHashSuffix) && bytes.HasSuffix(key, headerHashSuffix):
+		return inspectCanonicalHashes
+	case bytes.HasPrefix(key, headerNumberPrefix) && len(key) == len(headerNumberPrefix)+common.HashLength:
+		return inspectHeaderNumbers
+	case bytes.HasPrefix(key, blockBodyPrefix) && len(key) == len(blockBodyPrefix)+8+common.HashLength:
+		return inspectBodies
+	case bytes.HasPrefix(key, blockReceiptsPrefix) && len(key) == len(blockReceiptsPrefix)+8+common.HashLength:
+		return inspectReceipts
+	case bytes.HasPrefix(key, blockConfirmSignsPrefix) && len(key) == len(blockConfirmSignsPrefix)+8+common.HashLength:
+		return inspectConfirmSigns
+	case bytes.HasPrefix(key, txLookupPrefix) && len(key) == len(txLookupPrefix)+common.HashLength:
+		return inspectTxLookups
+	case bytes.HasPrefix(key, bloomBitsPrefix) && len(key) == len(bloomBitsPrefix)+2+8+common.HashLength:
+		return inspectBloomBits
+	case bytes.HasPrefix(key, poolTxPrefix) && len(key) == len(poolTxPrefix)+8:
+		return inspectPoolTxs
+	case len(key) == common.HashLength:
+		return inspectTrieNodes
+	case bytes.HasPrefix(key, istanbulSnapshotPrefix):
+		return inspectIstanbulSnapshots
+	case bytes.HasPrefix(key, []byte("i")):
+		return inspectChainIndexes
+	case bytes.HasPrefix(key, preimagePrefix) && len(key) == len(preimagePrefix)+common.HashLength:
+		return inspectPreimages
+	case bytes.HasPrefix(key, SnapshotAccountPrefix) && len(key) == len(SnapshotAccountPrefix)+common.HashLength:
+		return inspectSnapshotAccounts
+	case bytes.HasPrefix(key, SnapshotStoragePrefix) && len(key) == len(SnapshotStoragePrefix)+2*common.HashLength:
+		return inspectSnapshotStorage
+	case bytes.HasPrefix(key, configPrefix):
+		return inspectConfigs
+	}
+	for _, meta := range metadataKeys {
+		if bytes.Equal(key, meta) {
+			return inspectMetadata
+		}
+	}
+	return inspectUnknown
+}
@@ -0,0 +1,46 @@
+package rawdb
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
+)
+
+// Tests that the entries of the database are counted by kind.
+func TestInspectDatabase(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+
+	block := types.NewBlock(&types.Header{Number: big.NewInt(1)}, nil, nil)
+	WriteBlock(db, block)
+	WriteCanonicalHash(db, block.Hash(), 1)
+	WriteHeadBlockHash(db, block.Hash())
+	db.Put(common.Hash{'i'}.Bytes(), []byte("node"))
+	db.Put(append([]byte("istanbul-snapshot"), block.Hash().Bytes()...), []byte("snapshot"))
+	db.Put([]byte("unknown"), []byte("value"))
+
+	stats, err := InspectDatabase(db)
+	if err != nil {
+		t.Fatalf("inspection failed: %v", err)
+	}
+	want := map[string]uint64{
+		"Headers":                    1,
+		"Canonical hashes":           1,
+		"Header numbers":             1,
+		"Bodies":                     1,
+		"Trie nodes, codes and ABIs": 1,
+		"Istanbul snapshots":         1,
+		"Metadata":                   1,
+		"Unknown":                    1,
+	}
+	for _, stat := range stats {
+		if stat.Count != want[stat.Name] {
+			t.Errorf("%s: count mismatch: have %d, want %d", stat.Name, stat.Count, want[stat.Name])
+		}
+		if (stat.Count == 0) != (stat.Size == 0) {
+			t.Errorf("%s: size %v of %d entries", stat.Name, stat.Size, stat.Count)
+		}
+	}
+}
@@ -31,3 +31,19 @@ type DatabaseWriter interface {
 type DatabaseDeleter interface {
 	Delete(key []byte) error
 }
+
+// AncientReader wraps the methods reading the blocks moved to the freezer.
+type AncientReader interface {
+	// Ancients returns the number of blocks in the freezer, those from the
+	// genesis block on.
+	Ancients() uint64
+
+	// Ancient returns the item of the given kind of a frozen block.
+	Ancient(kind string, number uint64) ([]byte, error)
+}
+
+// AncientWriter wraps the methods dropping blocks from the freezer.
+type AncientWriter interface {
+	// TruncateAncients drops the frozen blocks from number items onwards.
+	TruncateAncients(items uint64) error
+}
@@ -0,0 +1,232 @@
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/rawdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/log"
+	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
+)
+
+const (
+	// receiptPruneInterval is the interval of the checks for receipts old
+	// enough to be pruned.
+	receiptPruneInterval = time.Minute
+
+	// receiptPruneLimit is the maximum number of blocks whose receipts are
+	// pruned at once.
+	receiptPruneLimit = 10000
+)
+
+// PrunedError is returned for the receipts and logs of the blocks older than
+// the retention horizon, which were pruned.
+type PrunedError struct {
+	Tail uint64 // Number of the oldest block whose receipts were not pruned
+}
+
+func (e *PrunedError) Error() string {
+	return fmt.Sprintf("pruned history: receipts and logs before block %d are not kept", e.Tail)
+}
+
+// CheckReceiptsPruned returns a PrunedError if the receipts of block number
+// were pruned from db. The receipts moved to the ancient store are kept.
+func CheckReceiptsPruned(db ethdb.Database, number uint64) error {
+	if tail := rawdb.ReadReceiptsTail(db); number < tail && !rawdb.HasAncientReceipts(db, number) {
+		return &PrunedError{Tail: tail}
+	}
+	return nil
+}
+
+// ReceiptArchiver receives the receipts of the blocks whose receipts are about
+// to be pruned.
+type ReceiptArchiver interface {
+	// ArchiveReceipts receives the receipts of block.
+	ArchiveReceipts(block *types.Block, receipts types.Receipts) error
+
+	// Sync makes the receipts received durable, they are deleted after.
+	Sync() error
+
+	// Close releases the resources of the archiver.
+	Close() error
+}
+
+// ArchivedReceipts is a record of the file written by a FileReceiptArchiver.
+// The records of a block are repeated if pruning failed after archiving them.
+type ArchivedReceipts struct {
+	Number   uint64
+	Hash     common.Hash
+	TxHashes []common.Hash
+	Receipts []*types.ReceiptForStorage
+}
+
+// FileReceiptArchiver appends the receipts it receives to a file, as a stream
+// of RLP encoded ArchivedReceipts.
+type FileReceiptArchiver struct {
+	file *os.File
+	buf  *bufio.Writer
+}
+
+// NewFileReceiptArchiver opens the file at path for appending the receipts it
+// receives to it, creating it if needed.
+func NewFileReceiptArchiver(path string) (*FileReceiptArchiver, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+	return &FileReceiptArchiver{file: file, buf: bufio.NewWriter(file)}, nil
+}
+
+// ArchiveReceipts implements ReceiptArchiver.
+func (a *FileReceiptArchiver) ArchiveReceipts(block *types.Block, receipts types.Receipts) error {
+	record := &ArchivedReceipts{Number: block.NumberU64(), Hash: block.Hash()}
+	for _, tx := range block.Transactions() {
+		record.TxHashes = append(record.TxHashes, tx.Hash())
+	}
+	for _, receipt := range receipts {
+		record.Receipts = append(record.Receipts, (*types.ReceiptForStorage)(receipt))
+	}
+	return rlp.Encode(a.buf, record)
+}
+
+// Sync implements ReceiptArchiver.
+func (a *FileReceiptArchiver) Sync() error {
+	if err := a.buf.Flush(); err != nil {
+		return err
+	}
+	return a.file.Sync()
+}
+
+// Close implements ReceiptArchiver.
+func (a *FileReceiptArchiver) Close() error {
+	if err := a.Sync(); err != nil {
+		a.file.Close()
+		return err
+	}
+	return a.file.Close()
+}
+
+// ReceiptPruner deletes the receipts and logs of the canonical blocks older
+// than a retention horizon, handing them to an archiver first. The lookups of
+// their transactions are kept: the transactions are still served from the
+// block bodies, and the transaction pool rejects their replays by them.
+type ReceiptPruner struct {
+	db        ethdb.Database
+	retention uint64
+	archiver  ReceiptArchiver
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewReceiptPruner creates a pruner keeping the receipts of the retention most
+// recent blocks of db. The archiver may be nil.
+func NewReceiptPruner(db ethdb.Database, retention uint64, archiver ReceiptArchiver) *ReceiptPruner {
+	return &ReceiptPruner{
+		db:        db,
+		retention: retention,
+		archiver:  archiver,
+		quit:      make(chan struct{}),
+	}
+}
+
+// Start prunes the receipts old enough in the background until Stop.
+func (p *ReceiptPruner) Start() {
+	p.wg.Add(1)
+	go func() {
+		defer p.wg.Done()
+		for {
+			if err := p.prune(); err != nil {
+				log.Error("Failed to prune receipts", "err", err)
+			}
+			select {
+			case <-p.quit:
+				return
+			case <-time.After(receiptPruneInterval):
+			}
+		}
+	}()
+	log.Info("Started receipt pruning", "retention", p.retention, "tail", rawdb.ReadReceiptsTail(p.db))
+}
+
+// Stop stops pruning and closes the archiver.
+func (p *ReceiptPruner) Stop() {
+	close(p.quit)
+	p.wg.Wait()
+
+	if p.archiver != nil {
+		if err := p.archiver.Close(); err != nil {
+			log.Error("Failed to close receipt archive", "err", err)
+		}
+	}
+}
+
+// prune prunes the receipts of up to receiptPruneLimit of the blocks older than
+// the retention horizon.
+func (p *ReceiptPruner) prune() error {
+	hash := rawdb.ReadHeadBlockHash(p.db)
+	if hash == (common.Hash{}) {
+		return nil
+	}
+	number := rawdb.ReadHeaderNumber(p.db, hash)
+	if number == nil || *number < p.retention {
+		return nil
+	}
+	var (
+		tail  = rawdb.ReadReceiptsTail(p.db)
+		limit = *number - p.retention
+	)
+	// The blocks before the checkpoint a chain was bootstrapped from are missing
+	if hash := rawdb.ReadCheckpointHash(p.db); hash != (common.Hash{}) {
+		if number := rawdb.ReadHeaderNumber(p.db, hash); number != nil && *number > tail {
+			tail = *number
+		}
+	}
+	// The ancient store is append-only, the receipts moved there are kept
+	if ancients, ok := p.db.(rawdb.AncientReader); ok && ancients.Ancients() > tail {
+		tail = ancients.Ancients()
+	}
+	if limit > tail+receiptPruneLimit {
+		limit = tail + receiptPruneLimit
+	}
+	if tail >= limit {
+		return nil
+	}
+	start := time.Now()
+
+	batch := p.db.NewBatch()
+	for number := tail; number < limit; number++ {
+		hash := rawdb.ReadCanonicalHash(p.db, number)
+		if hash == (common.Hash{}) {
+			return fmt.Errorf("canonical hash missing for block %d", number)
+		}
+		block := rawdb.ReadBlock(p.db, hash, number)
+		if block == nil {
+			return fmt.Errorf("block %d missing", number)
+		}
+		if p.archiver != nil {
+			if err := p.archiver.ArchiveReceipts(block, rawdb.ReadReceipts(p.db, hash, number)); err != nil {
+				return err
+			}
+		}
+		rawdb.DeleteReceipts(batch, hash, number)
+	}
+	// Make the receipts durable in the archive before deleting them
+	if p.archiver != nil {
+		if err := p.archiver.Sync(); err != nil {
+			return err
+		}
+	}
+	rawdb.WriteReceiptsTail(batch, limit)
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	log.Info("Pruned receipts", "blocks", limit-tail, "tail", limit, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
@@ -0,0 +1,262 @@
+package core
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/rawdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/state"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/event"
+	"github.com/PlatONEnetwork/PlatONE-Go/params"
+	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
+)
+
+// newPrunerChain creates a database with a chain of n blocks, each with a
+// transaction whose validity window closed at block 1 and one without window.
+func newPrunerChain(t *testing.T, n int) (ethdb.Database, []*types.Block) {
+	db := ethdb.NewMemDatabase()
+	genesis := (&Genesis{Config: &params.ChainConfig{ChainID: big.NewInt(1)}}).MustCommit(db)
+
+	blocks := []*types.Block{genesis}
+	for i := 1; i <= n; i++ {
+		txs := types.Transactions{
+			types.NewTransaction(uint64(2*i), common.Address{0x01}, new(big.Int), 0, new(big.Int), nil, types.NormalTxType).WithValidityWindow(types.ValidityWindow{ValidUntil: 1}),
+			types.NewTransaction(uint64(2*i+1), common.Address{0x01}, new(big.Int), 0, new(big.Int), nil, types.NormalTxType),
+		}
+		receipts := types.Receipts{
+			&types.Receipt{Status: types.ReceiptStatusSuccessful, TxHash: txs[0].Hash(), Logs: []*types.Log{}},
+			&types.Receipt{Status: types.ReceiptStatusSuccessful, TxHash: txs[1].Hash(), Logs: []*types.Log{}},
+		}
+		header := &types.Header{Number: big.NewInt(int64(i)), ParentHash: blocks[i-1].Hash(), Root: genesis.Root(), Time: big.NewInt(int64(i))}
+		block := types.NewBlock(header, txs, receipts)
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), receipts)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		rawdb.WriteTxLookupEntries(db, block)
+		blocks = append(blocks, block)
+	}
+	rawdb.WriteHeadBlockHash(db, blocks[n].Hash())
+	return db, blocks
+}
+
+// testArchiver records the blocks it archives, and whether their receipts were
+// still in the database when they were synced.
+type testArchiver struct {
+	db       ethdb.Database
+	blocks   []*types.Block
+	synced   int
+	kept     bool
+	syncErr  error
+	receipts int
+}
+
+func (a *testArchiver) ArchiveReceipts(block *types.Block, receipts types.Receipts) error {
+	a.blocks = append(a.blocks, block)
+	a.receipts += len(receipts)
+	return nil
+}
+
+func (a *testArchiver) Sync() error {
+	a.kept = true
+	for _, block := range a.blocks[a.synced:] {
+		if block.Transactions().Len() > 0 && rawdb.ReadReceipts(a.db, block.Hash(), block.NumberU64()) == nil {
+			a.kept = false
+		}
+	}
+	a.synced = len(a.blocks)
+	return a.syncErr
+}
+
+func (a *testArchiver) Close() error { return nil }
+
+// Tests that the receipts of the blocks older than the retention horizon are
+// pruned after being archived, moving the tail, and reported as pruned.
+func TestReceiptPrunerTail(t *testing.T) {
+	db, blocks := newPrunerChain(t, 10)
+	archiver := &testArchiver{db: db}
+	pruner := NewReceiptPruner(db, 4, archiver)
+
+	if err := pruner.prune(); err != nil {
+		t.Fatal(err)
+	}
+	if tail := rawdb.ReadReceiptsTail(db); tail != 6 {
+		t.Fatalf("tail %d, want 6", tail)
+	}
+	if len(archiver.blocks) != 6 || archiver.receipts != 10 || !archiver.kept {
+		t.Fatalf("archived %d blocks with %d receipts, kept until synced: %v", len(archiver.blocks), archiver.receipts, archiver.kept)
+	}
+	for _, block := range blocks[1:] {
+		receipts := rawdb.ReadReceipts(db, block.Hash(), block.NumberU64())
+		if pruned := block.NumberU64() < 6; pruned != (receipts == nil) {
+			t.Fatalf("block %d: receipts %v, pruned %v", block.NumberU64(), receipts, pruned)
+		}
+	}
+	if err, ok := CheckReceiptsPruned(db, 5).(*PrunedError); !ok || err.Tail != 6 {
+		t.Fatalf("block 5 error %v, want pruned before 6", err)
+	}
+	if err := CheckReceiptsPruned(db, 6); err != nil {
+		t.Fatalf("block 6 error %v, want none", err)
+	}
+
+	// Nothing left to prune until the head moves
+	if err := pruner.prune(); err != nil {
+		t.Fatal(err)
+	}
+	if len(archiver.blocks) != 6 || rawdb.ReadReceiptsTail(db) != 6 {
+		t.Fatalf("pruned again without a new head: %d archived", len(archiver.blocks))
+	}
+}
+
+// prunerAncientDB is a database whose oldest blocks were moved to an ancient
+// store, receipts holding the frozen receipts of each block.
+type prunerAncientDB struct {
+	ethdb.Database
+	receipts [][]byte
+}
+
+func (db *prunerAncientDB) Ancients() uint64 { return uint64(len(db.receipts)) }
+
+func (db *prunerAncientDB) Ancient(kind string, number uint64) ([]byte, error) {
+	if kind != "receipts" || number >= db.Ancients() {
+		return nil, errors.New("not frozen")
+	}
+	return db.receipts[number], nil
+}
+
+// Tests that the receipts of the ancient store are neither pruned nor reported
+// as pruned, unless they were pruned before their block was frozen.
+func TestReceiptPrunerAncients(t *testing.T) {
+	kvdb, _ := newPrunerChain(t, 10)
+	rawdb.WriteReceiptsTail(kvdb, 2)
+
+	// Blocks 0 and 1 were frozen after their receipts were pruned
+	db := &prunerAncientDB{Database: kvdb, receipts: [][]byte{nil, nil, {0xc0}, {0xc0}}}
+	archiver := &testArchiver{db: db}
+	if err := NewReceiptPruner(db, 4, archiver).prune(); err != nil {
+		t.Fatal(err)
+	}
+	if len(archiver.blocks) != 2 || archiver.blocks[0].NumberU64() != 4 {
+		t.Fatalf("archived %d blocks, want 4 and 5", len(archiver.blocks))
+	}
+	for number, pruned := range map[uint64]bool{1: true, 3: false, 5: true, 6: false} {
+		if err := CheckReceiptsPruned(db, number); (err != nil) != pruned {
+			t.Fatalf("block %d error %v, want pruned %v", number, err, pruned)
+		}
+	}
+}
+
+// Tests that nothing is deleted if the archive fails to make the receipts
+// durable.
+func TestReceiptPrunerArchiveFailure(t *testing.T) {
+	db, blocks := newPrunerChain(t, 10)
+	pruner := NewReceiptPruner(db, 4, &testArchiver{db: db, syncErr: errors.New("disk full")})
+
+	if err := pruner.prune(); err == nil {
+		t.Fatal("pruned without an archive")
+	}
+	if tail := rawdb.ReadReceiptsTail(db); tail != 0 {
+		t.Fatalf("tail moved to %d", tail)
+	}
+	for _, block := range blocks[1:] {
+		if rawdb.ReadReceipts(db, block.Hash(), block.NumberU64()) == nil {
+			t.Fatalf("receipts of block %d deleted", block.NumberU64())
+		}
+	}
+}
+
+// Tests that the file archiver writes the receipts as a stream of records.
+func TestFileReceiptArchiver(t *testing.T) {
+	dir, err := ioutil.TempDir("", "receipts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "receipts.rlp")
+
+	db, blocks := newPrunerChain(t, 10)
+	archiver, err := NewFileReceiptArchiver(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pruner := NewReceiptPruner(db, 4, archiver)
+	if err := pruner.prune(); err != nil {
+		t.Fatal(err)
+	}
+	pruner.Stop()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	stream := rlp.NewStream(file, 0)
+	for number := uint64(0); ; number++ {
+		var record ArchivedReceipts
+		if err := stream.Decode(&record); err == io.EOF {
+			if number != 6 {
+				t.Fatalf("archived %d blocks, want 6", number)
+			}
+			break
+		} else if err != nil {
+			t.Fatal(err)
+		}
+		block := blocks[number]
+		if record.Number != number || record.Hash != block.Hash() || len(record.Receipts) != block.Transactions().Len() {
+			t.Fatalf("record %d: block %d %x with %d receipts", number, record.Number, record.Hash, len(record.Receipts))
+		}
+		for i, tx := range block.Transactions() {
+			if record.TxHashes[i] != tx.Hash() || record.Receipts[i].TxHash != tx.Hash() {
+				t.Fatalf("record %d: transaction %d mismatch", number, i)
+			}
+		}
+	}
+}
+
+// prunerPoolChain is the chain of a transaction pool only validating
+// transactions on top of head.
+type prunerPoolChain struct {
+	head *types.Block
+}
+
+func (c *prunerPoolChain) CurrentBlock() *types.Block { return c.head }
+
+func (c *prunerPoolChain) GetBlock(hash common.Hash, number uint64) *types.Block { return nil }
+
+func (c *prunerPoolChain) GetState(header *types.Header) (*state.StateDB, error) {
+	return nil, errors.New("no state")
+}
+
+func (c *prunerPoolChain) SubscribeChainHeadEvent(ch chan<- ChainHeadEvent) event.Subscription {
+	return new(event.Feed).Subscribe(ch)
+}
+
+// Tests that the transactions of the pruned blocks are still found by their
+// hashes and rejected as replays.
+func TestReceiptPrunerReplay(t *testing.T) {
+	db, blocks := newPrunerChain(t, 10)
+	if err := NewReceiptPruner(db, 4, nil).prune(); err != nil {
+		t.Fatal(err)
+	}
+	pool := &TxPool{db: db, chain: &prunerPoolChain{head: blocks[10]}}
+
+	expired, open := blocks[1].Transactions()[0], blocks[1].Transactions()[1]
+	for _, tx := range []*types.Transaction{expired, open} {
+		if found, hash, _, _ := rawdb.ReadTransaction(db, tx.Hash()); found == nil || hash != blocks[1].Hash() {
+			t.Fatalf("pruned transaction %x not found", tx.Hash())
+		}
+	}
+	if err := pool.validateTx(expired, false); err != ErrTxExpired {
+		t.Fatalf("expired transaction error %v, want %v", err, ErrTxExpired)
+	}
+	if err := pool.validateTx(open, false); err != ErrTransactionRepeat {
+		t.Fatalf("pruned transaction error %v, want %v", err, ErrTransactionRepeat)
+	}
+}
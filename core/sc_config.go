@@ -10,12 +10,29 @@ import (
 	"github.com/PlatONEnetwork/PlatONE-Go/p2p"
 )
 
+// sysContractCaller calls a method of a system contract without changing the
+// state it runs on.
+type sysContractCaller func(contractAddr common.Address, funcName string, funcParams []interface{}) ([]byte, error)
+
+// latestCaller calls the system contracts in the state of the head of bc.
+func latestCaller(bc *BlockChain) sysContractCaller {
+	return func(contractAddr common.Address, funcName string, funcParams []interface{}) ([]byte, error) {
+		return InnerCallContractReadOnly(bc, contractAddr, funcName, funcParams)
+	}
+}
+
 func UpdateParamSysContractConfig(bc *BlockChain, sysContractConf *common.SystemConfig) {
+	loadParamConfig(latestCaller(bc), sysContractConf)
+}
+
+// loadParamConfig reads the parameters of sysContractConf from the parameter
+// manager, keeping the current values of those it does not return.
+func loadParamConfig(call sysContractCaller, sysContractConf *common.SystemConfig) {
 	paramAddr := syscontracts.ParameterManagementAddress
 
 	funcName := "getTxGasLimit"
 	funcParams := []interface{}{}
-	res, err := InnerCallContractReadOnly(bc, paramAddr, funcName, funcParams)
+	res, err := call(paramAddr, funcName, funcParams)
 	if res != nil && nil == err {
 		ret := common.CallResAsInt64(res)
 		if ret > 0 {
@@ -25,7 +42,7 @@ func UpdateParamSysContractConfig(bc *BlockChain, sysContractConf *common.System
 
 	funcName = "getBlockGasLimit"
 	funcParams = []interface{}{}
-	res, err = InnerCallContractReadOnly(bc, paramAddr, funcName, funcParams)
+	res, err = call(paramAddr, funcName, funcParams)
 	if res != nil && nil == err {
 		ret := common.CallResAsInt64(res)
 		if ret > 0 {
@@ -35,7 +52,7 @@ func UpdateParamSysContractConfig(bc *BlockChain, sysContractConf *common.System
 
 	funcName = "getCheckContractDeployPermission"
 	funcParams = []interface{}{}
-	res, err = InnerCallContractReadOnly(bc, paramAddr, funcName, funcParams)
+	res, err = call(paramAddr, funcName, funcParams)
 	if res != nil && nil == err {
 		ret := common.CallResAsInt64(res)
 		sysContractConf.SysParam.CheckContractDeployPermission = ret
@@ -43,7 +60,7 @@ func UpdateParamSysContractConfig(bc *BlockChain, sysContractConf *common.System
 
 	funcName = "getIsProduceEmptyBlock"
 	funcParams = []interface{}{}
-	res, err = InnerCallContractReadOnly(bc, paramAddr, funcName, funcParams)
+	res, err = call(paramAddr, funcName, funcParams)
 	if res != nil && nil == err {
 		ret := common.CallResAsInt64(res)
 		sysContractConf.SysParam.IsProduceEmptyBlock = ret == 1
@@ -51,7 +68,7 @@ func UpdateParamSysContractConfig(bc *BlockChain, sysContractConf *common.System
 
 	funcName = "getIsTxUseGas"
 	funcParams = []interface{}{}
-	res, err = InnerCallContractReadOnly(bc, paramAddr, funcName, funcParams)
+	res, err = call(paramAddr, funcName, funcParams)
 	if res != nil && nil == err {
 		ret := common.CallResAsInt64(res)
 		sysContractConf.SysParam.IsTxUseGas = ret == 1
@@ -59,7 +76,7 @@ func UpdateParamSysContractConfig(bc *BlockChain, sysContractConf *common.System
 
 	funcName = "getIsBlockUseTrieHash"
 	funcParams = []interface{}{}
-	res, err = InnerCallContractReadOnly(bc, paramAddr, funcName, funcParams)
+	res, err = call(paramAddr, funcName, funcParams)
 	if res != nil && nil == err {
 		ret := common.CallResAsInt64(res)
 		sysContractConf.SysParam.IsBlockUseTrieHash = ret == 1
@@ -67,7 +84,7 @@ func UpdateParamSysContractConfig(bc *BlockChain, sysContractConf *common.System
 
 	funcName = "getVRFParams"
 	funcParams = []interface{}{}
-	res, err = InnerCallContractReadOnly(bc, paramAddr, funcName, funcParams)
+	res, err = call(paramAddr, funcName, funcParams)
 	if res != nil && nil == err {
 		strRes := common.CallResAsString(res)
 		var tmpVrfParam common.VRFParams
@@ -82,7 +99,7 @@ func UpdateParamSysContractConfig(bc *BlockChain, sysContractConf *common.System
 
 	funcName = "getGasContractName"
 	funcParams = []interface{}{}
-	res, err = InnerCallContractReadOnly(bc, paramAddr, funcName, funcParams)
+	res, err = call(paramAddr, funcName, funcParams)
 	if res != nil && nil == err {
 		sysContractConf.SysParam.GasContractName = common.CallResAsString(res)
 	}
@@ -91,7 +108,7 @@ func UpdateParamSysContractConfig(bc *BlockChain, sysContractConf *common.System
 		cnsAddr := syscontracts.CnsManagementAddress
 		funcName = "getContractAddress"
 		funcParams = []interface{}{sysContractConf.SysParam.GasContractName, "latest"}
-		res, err = InnerCallContractReadOnly(bc, cnsAddr, funcName, funcParams)
+		res, err = call(cnsAddr, funcName, funcParams)
 		if res != nil && nil == err {
 			sysContractConf.SysParam.GasContractAddr = common.HexToAddress(common.CallResAsString(res))
 		}
@@ -99,11 +116,19 @@ func UpdateParamSysContractConfig(bc *BlockChain, sysContractConf *common.System
 }
 
 func UpdateNodeSysContractConfig(bc *BlockChain, sysContractConf *common.SystemConfig) {
+	if loadNodeConfig(latestCaller(bc), sysContractConf) {
+		p2p.UpdatePeer()
+	}
+}
+
+// loadNodeConfig reads the nodes of sysContractConf from the node manager, and
+// reports whether they were updated.
+func loadNodeConfig(call sysContractCaller, sysContractConf *common.SystemConfig) bool {
 	funcName := "getAllNodes"
 	funcParams := []interface{}{}
-	res, err := InnerCallContractReadOnly(bc, syscontracts.NodeManagementAddress, funcName, funcParams)
+	res, err := call(syscontracts.NodeManagementAddress, funcName, funcParams)
 	if nil != err {
-		return
+		return false
 	}
 
 	strRes := common.CallResAsString(res)
@@ -116,8 +141,9 @@ func UpdateNodeSysContractConfig(bc *BlockChain, sysContractConf *common.SystemC
 	} else {
 		sysContractConf.Nodes = tmp.Data
 		sysContractConf.GenerateNodeData()
-		p2p.UpdatePeer()
+		return true
 	}
+	return false
 }
 
 func UpdateSysContractConfig(bc *BlockChain, sysContractConf *common.SystemConfig) {
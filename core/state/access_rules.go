@@ -0,0 +1,86 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+)
+
+// UndeclaredAccessError is the failure of a transaction that accessed an
+// account or storage key missing from its access list.
+type UndeclaredAccessError struct {
+	Address common.Address
+	Key     []byte // nil for an access to the account itself
+}
+
+func (e *UndeclaredAccessError) Error() string {
+	if e.Key == nil {
+		return fmt.Sprintf("undeclared access to account %x", e.Address)
+	}
+	return fmt.Sprintf("undeclared access to storage %x of account %x", e.Key, e.Address)
+}
+
+// accessRules limits the accounts and storage a transaction accesses to its
+// access list and the accounts every transaction needs.
+type accessRules struct {
+	list      types.AccessList
+	implicit  map[common.Address]struct{}
+	violation error
+}
+
+func (r *accessRules) checkAccount(addr common.Address) {
+	if r.violation != nil {
+		return
+	}
+	if _, ok := r.implicit[addr]; ok {
+		return
+	}
+	for _, tuple := range r.list {
+		if tuple.Address == addr {
+			return
+		}
+	}
+	r.violation = &UndeclaredAccessError{Address: addr}
+}
+
+func (r *accessRules) checkStorage(addr common.Address, key []byte) {
+	if r.violation != nil {
+		return
+	}
+	for _, tuple := range r.list {
+		if tuple.Address == addr {
+			if !r.list.Covers(addr, key) {
+				r.violation = &UndeclaredAccessError{Address: addr, Key: common.CopyBytes(key)}
+			}
+			return
+		}
+	}
+	r.checkAccount(addr)
+}
+
+// SetAccessList limits the accesses from now on to the accounts and storage
+// in list and to the implicit accounts, whose storage is only limited if they
+// are in the list too. The first access outside them is kept for
+// AccessViolation, even if the call making it is reverted. A nil list lifts
+// the limits.
+func (self *StateDB) SetAccessList(list types.AccessList, implicit []common.Address) {
+	if list == nil {
+		self.access = nil
+		return
+	}
+	rules := &accessRules{list: list, implicit: make(map[common.Address]struct{}, len(implicit))}
+	for _, addr := range implicit {
+		rules.implicit[addr] = struct{}{}
+	}
+	self.access = rules
+}
+
+// AccessViolation returns the first access outside the access list, nil if
+// there was none.
+func (self *StateDB) AccessViolation() error {
+	if self.access == nil {
+		return nil
+	}
+	return self.access.violation
+}
@@ -0,0 +1,51 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/crypto"
+)
+
+// proofList collects the trie nodes of a proof, from the root node on.
+type proofList [][]byte
+
+func (n *proofList) Put(key []byte, value []byte) error {
+	*n = append(*n, common.CopyBytes(value))
+	return nil
+}
+
+// GetProof returns the state trie nodes proving the account of addr, or its
+// absence.
+func (self *StateDB) GetProof(addr common.Address) ([][]byte, error) {
+	var proof proofList
+	err := self.trie.Prove(crypto.Keccak256(addr.Bytes()), 0, &proof)
+	return proof, err
+}
+
+// GetStorageProof returns the storage trie nodes of addr proving the value
+// set by the contract for key, or its absence.
+func (self *StateDB) GetStorageProof(addr common.Address, key []byte) ([][]byte, error) {
+	st := self.StorageTrie(addr)
+	if st == nil {
+		return nil, fmt.Errorf("account %x doesn't exist", addr)
+	}
+	keyTrie, _, _ := getKeyValue(addr, key, nil)
+
+	var proof proofList
+	err := st.Prove(crypto.Keccak256([]byte(keyTrie)), 0, &proof)
+	return proof, err
+}
+
+// StorageKeyHash returns the key in the storage trie of addr of the entry the
+// contract set for key.
+func StorageKeyHash(addr common.Address, key []byte) common.Hash {
+	keyTrie, _, _ := getKeyValue(addr, key, nil)
+	return crypto.Keccak256Hash([]byte(keyTrie))
+}
+
+// StorageValueHash returns the hash of value the storage tries hold in place
+// of it.
+func StorageValueHash(value []byte) common.Hash {
+	return storageValueKey(value)
+}
@@ -0,0 +1,251 @@
+// Package pruner deletes from a stopped node's database the state trie nodes
+// and contract data no longer reachable from the block states it keeps.
+package pruner
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/rawdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/state"
+	"github.com/PlatONEnetwork/PlatONE-Go/crypto"
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/log"
+	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
+	"github.com/PlatONEnetwork/PlatONE-Go/trie"
+)
+
+// logInterval is the time between two progress logs of the sweep.
+const logInterval = 8 * time.Second
+
+var (
+	ErrNoHistory        = errors.New("state history must keep at least the head state")
+	ErrMissingHeadState = errors.New("state of the head block missing, restart the node to flush it")
+	ErrUnsupportedDB    = errors.New("database does not support iteration")
+	ErrBeyondCheckpoint = errors.New("pruning would delete states after the latest finalized checkpoint")
+
+	emptyRoot = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+	emptyCode = crypto.Keccak256Hash(nil)
+)
+
+// Config selects the block states kept by a pruning.
+type Config struct {
+	History    uint64 // Number of recent block states kept
+	Checkpoint uint64 // Interval of the block states kept whatever their age, 0 for none
+	Finalized  uint64 // Latest finalized checkpoint, the states after it are never pruned
+}
+
+// Pruner deletes the state data of a database unreachable from the kept
+// block states. The node using the database must be stopped.
+type Pruner struct {
+	db     ethdb.Database
+	config Config
+}
+
+// New creates a pruner of db keeping the states selected by config.
+func New(db ethdb.Database, config Config) *Pruner {
+	return &Pruner{db: db, config: config}
+}
+
+// Prune marks the trie nodes, codes and ABIs of the kept states, then deletes
+// all the other state entries of the database.
+func (p *Pruner) Prune() error {
+	head := rawdb.ReadHeadBlockHash(p.db)
+	number := rawdb.ReadHeaderNumber(p.db, head)
+	if number == nil {
+		return errors.New("head block not found")
+	}
+	roots, err := p.keptRoots(*number)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	marked := make(map[common.Hash]struct{})
+	triedb := trie.NewDatabase(p.db)
+	for _, root := range roots {
+		if err := mark(triedb, root, marked); err != nil {
+			return err
+		}
+	}
+	log.Info("Marked reachable state", "states", len(roots), "entries", len(marked), "elapsed", common.PrettyDuration(time.Since(start)))
+
+	if err := p.sweep(marked); err != nil {
+		return err
+	}
+	// Make sure the head state survived in full before claiming success
+	statedb, err := state.New(roots[0], state.NewDatabase(p.db))
+	if err != nil {
+		return err
+	}
+	it := state.NewNodeIterator(statedb)
+	for it.Next() {
+	}
+	return it.Error
+}
+
+// keptRoots returns the roots of the kept states present in the database,
+// the one of the head first.
+func (p *Pruner) keptRoots(head uint64) ([]common.Hash, error) {
+	if p.config.History == 0 {
+		return nil, ErrNoHistory
+	}
+	oldest := uint64(0)
+	if head >= p.config.History {
+		oldest = head - p.config.History + 1
+	}
+	if oldest > p.config.Finalized {
+		return nil, fmt.Errorf("%v: the history of %d blocks starts at %d, after checkpoint %d", ErrBeyondCheckpoint, p.config.History, oldest, p.config.Finalized)
+	}
+	var (
+		roots []common.Hash
+		seen  = make(map[common.Hash]bool)
+	)
+	keep := func(number uint64) {
+		header := rawdb.ReadHeader(p.db, rawdb.ReadCanonicalHash(p.db, number), number)
+		if header == nil || seen[header.Root] {
+			return
+		}
+		if ok, _ := p.db.Has(header.Root[:]); ok {
+			seen[header.Root] = true
+			roots = append(roots, header.Root)
+		}
+	}
+	if keep(head); len(roots) == 0 {
+		return nil, ErrMissingHeadState
+	}
+	for number := head; number > oldest; {
+		number--
+		keep(number)
+	}
+	if p.config.Checkpoint > 0 {
+		for number := uint64(0); number < oldest; number += p.config.Checkpoint {
+			keep(number)
+		}
+	}
+	return roots, nil
+}
+
+// mark adds to marked the hashes of the nodes, codes and ABIs of the state
+// with the given root. Subtries already marked are skipped, the states of
+// consecutive blocks sharing most of their nodes.
+func mark(triedb *trie.Database, root common.Hash, marked map[common.Hash]struct{}) error {
+	accounts, err := trie.New(root, triedb)
+	if err != nil {
+		return err
+	}
+	return markTrie(accounts.NodeIterator(nil), marked, func(blob []byte) error {
+		var account state.Account
+		if err := rlp.Decode(bytes.NewReader(blob), &account); err != nil {
+			return err
+		}
+		for _, hash := range [][]byte{account.CodeHash, account.AbiHash} {
+			if len(hash) > 0 && !bytes.Equal(hash, emptyCode[:]) {
+				marked[common.BytesToHash(hash)] = struct{}{}
+			}
+		}
+		if account.Root == emptyRoot || account.Root == (common.Hash{}) {
+			return nil
+		}
+		if _, ok := marked[account.Root]; ok {
+			return nil
+		}
+		storage, err := trie.New(account.Root, triedb)
+		if err != nil {
+			return err
+		}
+		return markTrie(storage.NodeIterator(nil), marked, nil)
+	})
+}
+
+// markTrie marks the nodes of a trie not marked yet, calling leaf with the
+// values of their leaves.
+func markTrie(it trie.NodeIterator, marked map[common.Hash]struct{}, leaf func([]byte) error) error {
+	for descend := true; it.Next(descend); {
+		descend = true
+		if hash := it.Hash(); hash != (common.Hash{}) {
+			if _, ok := marked[hash]; ok {
+				descend = false
+				continue
+			}
+			marked[hash] = struct{}{}
+		}
+		if it.Leaf() && leaf != nil {
+			if err := leaf(it.LeafBlob()); err != nil {
+				return err
+			}
+		}
+	}
+	return it.Error()
+}
+
+// sweep deletes the state entries of the database missing from marked: the
+// keys the size of a hash, under which trie nodes, codes and ABIs are stored.
+func (p *Pruner) sweep(marked map[common.Hash]struct{}) error {
+	var (
+		start   = time.Now()
+		logged  = time.Now()
+		batch   = p.db.NewBatch()
+		deleted int
+		size    common.StorageSize
+		err     error
+	)
+	iterErr := forEachKey(p.db, func(key, value []byte) bool {
+		if len(key) != common.HashLength {
+			return true
+		}
+		if _, ok := marked[common.BytesToHash(key)]; ok {
+			return true
+		}
+		if err = batch.Delete(key); err != nil {
+			return false
+		}
+		deleted++
+		size += common.StorageSize(len(key) + len(value))
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err = batch.Write(); err != nil {
+				return false
+			}
+			batch.Reset()
+		}
+		if time.Since(logged) > logInterval {
+			log.Info("Pruning state data", "deleted", deleted, "size", size, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+		return true
+	})
+	if iterErr != nil {
+		return iterErr
+	}
+	if err != nil {
+		return err
+	}
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	log.Info("Pruned state data", "deleted", deleted, "size", size, "elapsed", common.PrettyDuration(time.Since(start)))
+
+	if db, ok := p.db.(ethdb.Compacter); ok {
+		start = time.Now()
+		if err := db.Compact(nil, nil); err != nil {
+			return err
+		}
+		log.Info("Compacted database", "elapsed", common.PrettyDuration(time.Since(start)))
+	}
+	return nil
+}
+
+// forEachKey calls fn with the entries of db until it returns false.
+func forEachKey(db ethdb.Database, fn func(key, value []byte) bool) error {
+	iteratee, ok := db.(ethdb.Iteratee)
+	if !ok {
+		return ErrUnsupportedDB
+	}
+	it := iteratee.NewIteratorWithPrefix(nil)
+	defer it.Release()
+	for it.Next() && fn(it.Key(), it.Value()) {
+	}
+	return it.Error()
+}
@@ -0,0 +1,100 @@
+package pruner
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/rawdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/state"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
+)
+
+// makeChain writes a chain whose blocks each change the state, and returns
+// the roots of their states.
+func makeChain(t *testing.T, db *ethdb.MemDatabase, blocks int) []common.Hash {
+	var (
+		sdb      = state.NewDatabase(db)
+		roots    []common.Hash
+		root     common.Hash
+		parent   common.Hash
+		contract = common.Address{0xc0}
+	)
+	for i := 0; i < blocks; i++ {
+		statedb, err := state.New(root, sdb)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if i == 0 {
+			statedb.SetCode(contract, []byte{0x00, 0x61, 0x73, 0x6d})
+		}
+		statedb.SetState(contract, []byte{byte(i)}, []byte{byte(i + 1)})
+		statedb.AddBalance(common.Address{byte(i + 1)}, big.NewInt(int64(i+1)))
+		if root, err = statedb.Commit(true); err != nil {
+			t.Fatal(err)
+		}
+		if err := sdb.TrieDB().Commit(root, false); err != nil {
+			t.Fatal(err)
+		}
+		header := &types.Header{Number: big.NewInt(int64(i)), ParentHash: parent, Root: root}
+		rawdb.WriteHeader(db, header)
+		rawdb.WriteCanonicalHash(db, header.Hash(), uint64(i))
+		rawdb.WriteHeadBlockHash(db, header.Hash())
+		parent = header.Hash()
+		roots = append(roots, root)
+	}
+	return roots
+}
+
+// complete reports whether the whole state with the given root is in db.
+func complete(db ethdb.Database, root common.Hash) bool {
+	statedb, err := state.New(root, state.NewDatabase(db))
+	if err != nil {
+		return false
+	}
+	it := state.NewNodeIterator(statedb)
+	for it.Next() {
+	}
+	return it.Error == nil
+}
+
+// Tests that pruning keeps the recent states and the checkpoints in full, and
+// deletes the others.
+func TestPrune(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	roots := makeChain(t, db, 6)
+
+	if err := New(db, Config{History: 2, Checkpoint: 3, Finalized: 4}).Prune(); err != nil {
+		t.Fatal(err)
+	}
+	for i, root := range roots {
+		kept := i == 0 || i == 3 || i >= 4
+		if has, _ := db.Has(root[:]); has != kept {
+			t.Errorf("state %d present: %v, want %v", i, has, kept)
+		}
+		if kept && !complete(db, root) {
+			t.Errorf("state %d incomplete after pruning", i)
+		}
+	}
+	if rawdb.ReadHeader(db, rawdb.ReadCanonicalHash(db, 5), 5) == nil {
+		t.Fatal("pruning deleted a header")
+	}
+}
+
+// Tests that pruning refuses to delete the states after the latest finalized
+// checkpoint.
+func TestPruneBeyondCheckpoint(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	roots := makeChain(t, db, 6)
+	entries := db.Len()
+
+	err := New(db, Config{History: 2, Finalized: 3}).Prune()
+	if err == nil || !strings.Contains(err.Error(), ErrBeyondCheckpoint.Error()) {
+		t.Fatalf("pruning error %v, want %v", err, ErrBeyondCheckpoint)
+	}
+	if db.Len() != entries || !complete(db, roots[0]) {
+		t.Fatal("refused pruning changed the database")
+	}
+}
@@ -0,0 +1,76 @@
+package state
+
+import (
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+)
+
+// ReadWriteSet records the accounts a transaction read and wrote. Transactions
+// executed in parallel on copies of the same state are checked against each
+// other with it: one that touched an account written by a transaction before
+// it saw stale state.
+//
+// Accounts are the unit of conflict, so reading a storage slot of an account
+// conflicts with a balance change of it. This keeps the check independent of
+// the way values are stored in the account.
+type ReadWriteSet struct {
+	Reads  map[common.Address]struct{}
+	Writes map[common.Address]struct{}
+}
+
+// NewReadWriteSet creates an empty set.
+func NewReadWriteSet() *ReadWriteSet {
+	return &ReadWriteSet{
+		Reads:  make(map[common.Address]struct{}),
+		Writes: make(map[common.Address]struct{}),
+	}
+}
+
+// TrackAccess records the accounts loaded from now on into rw and, when the
+// state is finalised, the accounts changed. A nil rw stops recording.
+func (self *StateDB) TrackAccess(rw *ReadWriteSet) {
+	self.rwset = rw
+}
+
+// MergeWrites takes over the accounts written to src, a copy of the state
+// finalised after executing a single transaction, together with the logs and
+// preimages of the transaction. The logs are renumbered for the transaction
+// prepared on this state.
+func (self *StateDB) MergeWrites(src *StateDB, rw *ReadWriteSet) {
+	for addr := range rw.Writes {
+		object, exist := src.stateObjects[addr]
+		if !exist {
+			continue
+		}
+		object = object.deepCopy(self)
+		self.setStateObject(object)
+		if self.snap != nil {
+			// Take over the storage changes recorded for the snapshots
+			if _, ok := src.snapDestructs[object.addrHash]; ok {
+				self.snapDestructs[object.addrHash] = struct{}{}
+			}
+			if slots, ok := src.snapStorage[object.addrHash]; ok {
+				self.snapStorage[object.addrHash] = copySnapSlots(slots)
+			}
+		}
+		if object.deleted {
+			self.deleteStateObject(object)
+		} else {
+			self.updateStateObject(object)
+		}
+		self.stateObjectsDirty[addr] = struct{}{}
+	}
+	for _, l := range src.logs[src.thash] {
+		cpy := *l
+		cpy.TxHash = self.thash
+		cpy.BlockHash = self.bhash
+		cpy.TxIndex = uint(self.txIndex)
+		cpy.Index = self.logSize
+		self.logs[self.thash] = append(self.logs[self.thash], &cpy)
+		self.logSize++
+	}
+	for hash, preimage := range src.preimages {
+		if _, ok := self.preimages[hash]; !ok {
+			self.preimages[hash] = preimage
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package snapshot
+
+import (
+	"sync"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+)
+
+// diffLayer holds the changes a block made to the state of its parent layer.
+type diffLayer struct {
+	parent layer
+	root   common.Hash
+	stale  bool
+
+	destructs map[common.Hash]struct{}               // Accounts deleted or recreated, losing their storage
+	accounts  map[common.Hash][]byte                 // Account trie values, nil for the deleted accounts
+	storage   map[common.Hash]map[common.Hash][]byte // Storage values by account, nil for the deleted ones
+
+	lock sync.RWMutex
+}
+
+func newDiffLayer(parent layer, root common.Hash, destructs map[common.Hash]struct{}, accounts map[common.Hash][]byte, storage map[common.Hash]map[common.Hash][]byte) *diffLayer {
+	return &diffLayer{
+		parent:    parent,
+		root:      root,
+		destructs: destructs,
+		accounts:  accounts,
+		storage:   storage,
+	}
+}
+
+// Root returns the root of the state of the layer.
+func (dl *diffLayer) Root() common.Hash {
+	return dl.root
+}
+
+// Stale reports whether the layer was flattened or discarded.
+func (dl *diffLayer) Stale() bool {
+	dl.lock.RLock()
+	defer dl.lock.RUnlock()
+
+	return dl.stale
+}
+
+func (dl *diffLayer) markStale() {
+	dl.lock.Lock()
+	defer dl.lock.Unlock()
+
+	dl.stale = true
+}
+
+func (dl *diffLayer) parentLayer() layer {
+	dl.lock.RLock()
+	defer dl.lock.RUnlock()
+
+	return dl.parent
+}
+
+func (dl *diffLayer) setParent(parent layer) {
+	dl.lock.Lock()
+	defer dl.lock.Unlock()
+
+	dl.parent = parent
+}
+
+// Account returns the account trie value of the account with the given hash,
+// looking it up in the parent layers if the block did not change it.
+func (dl *diffLayer) Account(hash common.Hash) ([]byte, error) {
+	dl.lock.RLock()
+	if dl.stale {
+		dl.lock.RUnlock()
+		return nil, ErrSnapshotStale
+	}
+	if data, ok := dl.accounts[hash]; ok {
+		dl.lock.RUnlock()
+		return data, nil
+	}
+	if _, ok := dl.destructs[hash]; ok {
+		dl.lock.RUnlock()
+		return nil, nil
+	}
+	parent := dl.parent
+	dl.lock.RUnlock()
+
+	return parent.Account(hash)
+}
+
+// Storage returns a storage value of the account with the given hash, looking
+// it up in the parent layers if the block did not change it.
+func (dl *diffLayer) Storage(accountHash, storageHash common.Hash) ([]byte, error) {
+	dl.lock.RLock()
+	if dl.stale {
+		dl.lock.RUnlock()
+		return nil, ErrSnapshotStale
+	}
+	if data, ok := dl.storage[accountHash][storageHash]; ok {
+		dl.lock.RUnlock()
+		return data, nil
+	}
+	if _, ok := dl.destructs[accountHash]; ok {
+		dl.lock.RUnlock()
+		return nil, nil
+	}
+	parent := dl.parent
+	dl.lock.RUnlock()
+
+	return parent.Storage(accountHash, storageHash)
+}
@@ -0,0 +1,147 @@
+package snapshot
+
+import (
+	"bytes"
+	"sync"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/rawdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/log"
+	"github.com/PlatONEnetwork/PlatONE-Go/trie"
+)
+
+// diskLayer is the flat state persisted in the database, complete or being
+// generated from the trie of its root.
+type diskLayer struct {
+	diskdb ethdb.Database
+	triedb *trie.Database
+	root   common.Hash
+	stale  bool
+
+	genMarker []byte             // Hash of the last account generated, empty before the first, nil once complete
+	genAbort  chan chan struct{} // Stops the running generation, nil if none
+
+	lock sync.RWMutex
+}
+
+// Root returns the root of the state of the layer.
+func (dl *diskLayer) Root() common.Hash {
+	return dl.root
+}
+
+// Stale reports whether the layer was replaced by a newer disk layer.
+func (dl *diskLayer) Stale() bool {
+	dl.lock.RLock()
+	defer dl.lock.RUnlock()
+
+	return dl.stale
+}
+
+func (dl *diskLayer) markStale() {
+	dl.lock.Lock()
+	defer dl.lock.Unlock()
+
+	dl.stale = true
+}
+
+// covered reports whether the account with the given hash, storage included,
+// has been generated according to marker.
+func covered(marker []byte, hash common.Hash) bool {
+	return marker == nil || (len(marker) > 0 && bytes.Compare(hash[:], marker) <= 0)
+}
+
+// Account returns the account trie value of the account with the given hash.
+func (dl *diskLayer) Account(hash common.Hash) ([]byte, error) {
+	dl.lock.RLock()
+	defer dl.lock.RUnlock()
+
+	if dl.stale {
+		return nil, ErrSnapshotStale
+	}
+	if !covered(dl.genMarker, hash) {
+		return nil, ErrNotCoveredYet
+	}
+	return rawdb.ReadAccountSnapshot(dl.diskdb, hash), nil
+}
+
+// Storage returns a storage value of the account with the given hash.
+func (dl *diskLayer) Storage(accountHash, storageHash common.Hash) ([]byte, error) {
+	dl.lock.RLock()
+	defer dl.lock.RUnlock()
+
+	if dl.stale {
+		return nil, ErrSnapshotStale
+	}
+	if !covered(dl.genMarker, accountHash) {
+		return nil, ErrNotCoveredYet
+	}
+	return rawdb.ReadStorageSnapshot(dl.diskdb, accountHash, storageHash), nil
+}
+
+// flatten writes the changes of diff, the layer on top of dl, to the database
+// and returns the disk layer of the state of diff replacing dl. A running
+// generation only has the accounts it already covered written, and goes on
+// with the trie of the new root.
+func (dl *diskLayer) flatten(diff *diffLayer) *diskLayer {
+	dl.stopGeneration()
+	dl.markStale()
+	diff.markStale()
+
+	marker := dl.genMarker
+	batch := dl.diskdb.NewBatch()
+	write := func() {
+		if err := batch.Write(); err != nil {
+			log.Crit("Failed to write state snapshot", "err", err)
+		}
+		batch.Reset()
+	}
+	// The snapshot is incomplete until all the changes are written
+	if marker == nil {
+		rawdb.DeleteSnapshotRoot(batch)
+	}
+	for hash := range diff.destructs {
+		if !covered(marker, hash) {
+			continue
+		}
+		rawdb.DeleteAccountSnapshot(batch, hash)
+		if err := deleteStorage(dl.diskdb, batch, hash); err != nil {
+			log.Error("Failed to delete snapshot storage", "account", hash, "err", err)
+		}
+	}
+	for hash, data := range diff.accounts {
+		if !covered(marker, hash) {
+			continue
+		}
+		if data == nil {
+			rawdb.DeleteAccountSnapshot(batch, hash)
+		} else {
+			rawdb.WriteAccountSnapshot(batch, hash, data)
+		}
+	}
+	for accountHash, slots := range diff.storage {
+		if !covered(marker, accountHash) {
+			continue
+		}
+		for storageHash, data := range slots {
+			if len(data) == 0 {
+				rawdb.DeleteStorageSnapshot(batch, accountHash, storageHash)
+			} else {
+				rawdb.WriteStorageSnapshot(batch, accountHash, storageHash, data)
+			}
+		}
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			write()
+		}
+	}
+	if marker == nil {
+		rawdb.WriteSnapshotRoot(batch, diff.root)
+	}
+	write()
+
+	disk := &diskLayer{diskdb: dl.diskdb, triedb: dl.triedb, root: diff.root, genMarker: marker}
+	if marker != nil {
+		disk.startGeneration()
+	}
+	return disk
+}
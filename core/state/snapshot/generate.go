@@ -0,0 +1,212 @@
+package snapshot
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/rawdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/log"
+	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
+	"github.com/PlatONEnetwork/PlatONE-Go/trie"
+)
+
+// logInterval is the time between two progress logs of the generation.
+const logInterval = 8 * time.Second
+
+var (
+	errUnsupportedDB = errors.New("database does not support iteration")
+
+	emptyRoot = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+)
+
+// account is the head of the account trie values, up to the storage root
+// the generation needs.
+type account struct {
+	Nonce    uint64
+	FwActive uint64
+	Balance  *big.Int
+	Root     common.Hash
+	Rest     []rlp.RawValue `rlp:"tail"`
+}
+
+// startGeneration starts generating the accounts after the marker of dl in
+// the background.
+func (dl *diskLayer) startGeneration() {
+	dl.genAbort = make(chan chan struct{})
+	go dl.generate(dl.genAbort)
+}
+
+// stopGeneration stops the generation of dl, if running, once the accounts
+// generated so far are written.
+func (dl *diskLayer) stopGeneration() {
+	if dl.genAbort == nil {
+		return
+	}
+	done := make(chan struct{})
+	dl.genAbort <- done
+	<-done
+	dl.genAbort = nil
+}
+
+// generate writes the accounts of the trie of the layer root after its marker
+// with their storage, moving the marker past the accounts written. Accounts
+// are written whole, so that the ones past the marker are never on disk. Once
+// done, or if the trie is missing, it waits for abort.
+func (dl *diskLayer) generate(abort chan chan struct{}) {
+	dl.lock.RLock()
+	marker := dl.genMarker
+	dl.lock.RUnlock()
+
+	var (
+		start    = time.Now()
+		logged   = time.Now()
+		batch    = dl.diskdb.NewBatch()
+		accounts int
+		slots    int
+	)
+	// flush writes the batch and makes the accounts up to last readable
+	flush := func(last []byte) {
+		if err := batch.Write(); err != nil {
+			log.Crit("Failed to write state snapshot", "err", err)
+		}
+		batch.Reset()
+
+		dl.lock.Lock()
+		dl.genMarker = last
+		dl.lock.Unlock()
+	}
+	done, err := func() (chan struct{}, error) {
+		accTrie, err := trie.NewSecure(dl.root, dl.triedb, 0)
+		if err != nil {
+			return nil, err
+		}
+		it := trie.NewIterator(accTrie.NodeIterator(marker))
+		for it.Next() {
+			if len(marker) > 0 && bytes.Equal(it.Key, marker) {
+				continue
+			}
+			select {
+			case done := <-abort:
+				return done, nil
+			default:
+			}
+			hash := common.BytesToHash(it.Key)
+			rawdb.WriteAccountSnapshot(batch, hash, it.Value)
+
+			var acc account
+			if err := rlp.DecodeBytes(it.Value, &acc); err != nil {
+				return nil, err
+			}
+			if acc.Root != emptyRoot && acc.Root != (common.Hash{}) {
+				storeTrie, err := trie.NewSecure(acc.Root, dl.triedb, 0)
+				if err != nil {
+					return nil, err
+				}
+				// The storage trie holds the hashes of the values, kept as preimages
+				storeIt := trie.NewIterator(storeTrie.NodeIterator(nil))
+				for storeIt.Next() {
+					_, content, _, err := rlp.Split(storeIt.Value)
+					if err != nil {
+						return nil, err
+					}
+					if value := storeTrie.GetKey(common.BytesToHash(content).Bytes()); len(value) > 0 {
+						rawdb.WriteStorageSnapshot(batch, hash, common.BytesToHash(storeIt.Key), value)
+					}
+					slots++
+				}
+				if storeIt.Err != nil {
+					return nil, storeIt.Err
+				}
+			}
+			accounts++
+			marker = common.CopyBytes(it.Key)
+			if batch.ValueSize() >= ethdb.IdealBatchSize {
+				flush(marker)
+			}
+			if time.Since(logged) > logInterval {
+				log.Info("Generating state snapshot", "root", dl.root, "at", hash, "accounts", accounts, "slots", slots, "elapsed", common.PrettyDuration(time.Since(start)))
+				logged = time.Now()
+			}
+		}
+		return nil, it.Err
+	}()
+	switch {
+	case done != nil:
+		flush(marker)
+		log.Debug("Paused state snapshot generation", "root", dl.root, "accounts", accounts, "slots", slots)
+
+	case err != nil:
+		// The trie of the root may have been garbage collected, the generation
+		// goes on with the root of the next flattened layer
+		batch.Reset()
+		log.Debug("Stalled state snapshot generation", "root", dl.root, "err", err)
+		done = <-abort
+
+	default:
+		rawdb.WriteSnapshotRoot(batch, dl.root)
+		flush(nil)
+		log.Info("Generated state snapshot", "root", dl.root, "accounts", accounts, "slots", slots, "elapsed", common.PrettyDuration(time.Since(start)))
+		done = <-abort
+	}
+	close(done)
+}
+
+// iterable reports whether the keys of db can be iterated over, which the
+// snapshot needs to delete accounts.
+func iterable(db ethdb.Database) bool {
+	_, ok := db.(ethdb.Iteratee)
+	return ok
+}
+
+// forEachKey calls fn with the keys of db of the given length starting with
+// prefix.
+func forEachKey(db ethdb.Database, prefix []byte, length int, fn func(key []byte)) error {
+	iteratee, ok := db.(ethdb.Iteratee)
+	if !ok {
+		return errUnsupportedDB
+	}
+	it := iteratee.NewIteratorWithPrefix(prefix)
+	defer it.Release()
+	for it.Next() {
+		if len(it.Key()) == length {
+			fn(common.CopyBytes(it.Key()))
+		}
+	}
+	return it.Error()
+}
+
+// deleteStorage adds to batch the deletion of the storage values of the
+// account with the given hash.
+func deleteStorage(db ethdb.Database, batch ethdb.Batch, hash common.Hash) error {
+	prefix := append(append([]byte{}, rawdb.SnapshotStoragePrefix...), hash.Bytes()...)
+	return forEachKey(db, prefix, len(prefix)+common.HashLength, func(key []byte) {
+		batch.Delete(key)
+	})
+}
+
+// wipeSnapshot deletes all the accounts and storage values of the flat state.
+func wipeSnapshot(db ethdb.Database) error {
+	batch := db.NewBatch()
+	var err error
+	del := func(key []byte) {
+		batch.Delete(key)
+		if batch.ValueSize() >= ethdb.IdealBatchSize && err == nil {
+			err = batch.Write()
+			batch.Reset()
+		}
+	}
+	if e := forEachKey(db, rawdb.SnapshotAccountPrefix, 1+common.HashLength, del); e != nil {
+		return e
+	}
+	if e := forEachKey(db, rawdb.SnapshotStoragePrefix, 1+2*common.HashLength, del); e != nil {
+		return e
+	}
+	if err != nil {
+		return err
+	}
+	return batch.Write()
+}
@@ -0,0 +1,222 @@
+// Package snapshot maintains a flat key/value copy of the accounts and the
+// storage of the recent states next to their tries, for reads not walking the
+// tries.
+//
+// The snapshot of a state is a stack of layers: the disk layer, persisted in
+// the database, and on top of it a diff layer per recent block holding the
+// changes the block made. Diff layers beyond the configured depth are
+// flattened into the disk layer.
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/rawdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/log"
+	"github.com/PlatONEnetwork/PlatONE-Go/trie"
+)
+
+var (
+	// ErrSnapshotStale is returned by the reads of a layer flattened or
+	// discarded since it was retrieved. The state must be read from the trie.
+	ErrSnapshotStale = errors.New("snapshot stale")
+
+	// ErrNotCoveredYet is returned by the reads of the disk layer for the
+	// entries its generation has not reached yet.
+	ErrNotCoveredYet = errors.New("not covered yet")
+
+	// errUnknownLayer is returned when the layer of a state root is missing.
+	errUnknownLayer = errors.New("unknown snapshot layer")
+)
+
+// Snapshot is the flat copy of a state.
+type Snapshot interface {
+	// Root returns the root of the state.
+	Root() common.Hash
+
+	// Account returns the account trie value of the account with the given
+	// hash, nil if the account does not exist.
+	Account(hash common.Hash) ([]byte, error)
+
+	// Storage returns the storage value under the given hashed key of the
+	// account with the given hash, nil if there is none.
+	Storage(accountHash, storageHash common.Hash) ([]byte, error)
+}
+
+// layer is a Snapshot part of a tree.
+type layer interface {
+	Snapshot
+
+	// Stale reports whether the layer was flattened or discarded.
+	Stale() bool
+}
+
+// Tree is the set of the snapshots of the recent states, sharing a disk
+// layer. It is safe for concurrent use.
+type Tree struct {
+	diskdb ethdb.Database
+	triedb *trie.Database
+	disk   *diskLayer
+	layers map[common.Hash]layer
+
+	lock sync.RWMutex
+}
+
+// New opens the snapshots of the states in diskdb. The disk layer is expected
+// at root, the state of the head block: if it is not, after an unclean
+// shutdown or when the snapshot was never generated, it is regenerated in the
+// background from the trie of root in triedb.
+//
+// Nil is returned if the snapshot cannot be maintained in diskdb.
+func New(diskdb ethdb.Database, triedb *trie.Database, root common.Hash) *Tree {
+	if !iterable(diskdb) {
+		log.Warn("State snapshot unsupported by the database")
+		return nil
+	}
+	t := &Tree{
+		diskdb: diskdb,
+		triedb: triedb,
+		layers: make(map[common.Hash]layer),
+	}
+	if rawdb.ReadSnapshotRoot(diskdb) == root {
+		t.disk = &diskLayer{diskdb: diskdb, triedb: triedb, root: root}
+		log.Info("Loaded state snapshot", "root", root)
+	} else {
+		t.disk = t.regenerate(root)
+	}
+	t.layers[root] = t.disk
+	return t
+}
+
+// Snapshot returns the snapshot of the state with the given root, nil if it
+// is unknown.
+func (t *Tree) Snapshot(root common.Hash) Snapshot {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+
+	if snap, ok := t.layers[root]; ok {
+		return snap
+	}
+	return nil
+}
+
+// Update adds the snapshot of the state root, built on the state parent with
+// the given changes: the destructed accounts, which lose their storage, and
+// the new account and storage values, nil for the deleted ones. The maps are
+// owned by the snapshot from then on.
+func (t *Tree) Update(root, parent common.Hash, destructs map[common.Hash]struct{}, accounts map[common.Hash][]byte, storage map[common.Hash]map[common.Hash][]byte) error {
+	if root == parent {
+		return nil
+	}
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if _, ok := t.layers[root]; ok {
+		return nil
+	}
+	base, ok := t.layers[parent]
+	if !ok {
+		return fmt.Errorf("%v: parent %x", errUnknownLayer, parent)
+	}
+	t.layers[root] = newDiffLayer(base, root, destructs, accounts, storage)
+	return nil
+}
+
+// Cap flattens into the disk layer the diff layers below the first layers
+// ones under the snapshot of root, and discards the snapshots not built on
+// top of the new disk layer.
+func (t *Tree) Cap(root common.Hash, layers int) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	snap, ok := t.layers[root]
+	if !ok {
+		return fmt.Errorf("%v: %x", errUnknownLayer, root)
+	}
+	var chain []*diffLayer
+	for {
+		diff, ok := snap.(*diffLayer)
+		if !ok {
+			break
+		}
+		chain = append(chain, diff)
+		snap = diff.parentLayer()
+	}
+	if len(chain) <= layers {
+		return nil
+	}
+	for i := len(chain) - 1; i >= layers; i-- {
+		t.disk = t.disk.flatten(chain[i])
+		t.layers[t.disk.root] = t.disk
+	}
+	if layers > 0 {
+		chain[layers-1].setParent(t.disk)
+	}
+	for root, snap := range t.layers {
+		if !t.descends(snap) {
+			if diff, ok := snap.(*diffLayer); ok {
+				diff.markStale()
+			}
+			delete(t.layers, root)
+		}
+	}
+	return nil
+}
+
+// descends reports whether snap is the disk layer or built on top of it.
+func (t *Tree) descends(snap layer) bool {
+	for {
+		switch l := snap.(type) {
+		case *diskLayer:
+			return l == t.disk
+		case *diffLayer:
+			if l.Stale() {
+				return false
+			}
+			snap = l.parentLayer()
+		}
+	}
+}
+
+// Rebuild discards all the snapshots and regenerates the disk layer from the
+// state root, after the chain was rewound below the disk layer.
+func (t *Tree) Rebuild(root common.Hash) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.disk.stopGeneration()
+	t.disk.markStale()
+	for _, snap := range t.layers {
+		if diff, ok := snap.(*diffLayer); ok {
+			diff.markStale()
+		}
+	}
+	t.disk = t.regenerate(root)
+	t.layers = map[common.Hash]layer{root: t.disk}
+}
+
+// Stop stops the generation of the disk layer, which resumes from scratch
+// the next time the snapshots are opened.
+func (t *Tree) Stop() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.disk.stopGeneration()
+}
+
+// regenerate wipes the flat state of the database and starts generating a
+// disk layer for root.
+func (t *Tree) regenerate(root common.Hash) *diskLayer {
+	log.Info("Regenerating state snapshot", "root", root)
+	rawdb.DeleteSnapshotRoot(t.diskdb)
+	if err := wipeSnapshot(t.diskdb); err != nil {
+		log.Error("Failed to wipe state snapshot", "err", err)
+	}
+	disk := &diskLayer{diskdb: t.diskdb, triedb: t.triedb, root: root, genMarker: []byte{}}
+	disk.startGeneration()
+	return disk
+}
@@ -364,6 +364,17 @@ func (self *stateObject) GetCommittedState(db Database, key string) []byte {
 		}
 	}
 
+	// Otherwise load the value from the snapshot, which holds the values themselves
+	if snap := self.db.snap; snap != nil {
+		if value, err := snap.Storage(self.addrHash, crypto.Keccak256Hash([]byte(key))); err == nil {
+			if len(value) > 0 {
+				valueKey = storageValueKey(value)
+			}
+			self.originStorage[key] = valueKey
+			self.originValueStorage[valueKey] = value
+			return value
+		}
+	}
 	// Otherwise load the valueKey from trie
 	enc, err := self.getTrie(db).TryGet([]byte(key))
 	if err != nil {
@@ -427,6 +438,7 @@ func (self *stateObject) updateTrie(db Database) Trie {
 		}
 
 		self.originStorage[key] = valueKey
+		self.db.recordStorage(self.addrHash, key, valueKey, self.dirtyValueStorage[valueKey])
 
 		if valueKey == emptyStorage {
 			self.setError(self.trie.TryDelete([]byte(key)))
@@ -520,6 +532,7 @@ func (self *stateObject) deepCopy(db *StateDB) *stateObject {
 	stateObject.rawFwData = self.rawFwData
 	stateObject.fwData = self.fwData
 	stateObject.code = self.code
+	stateObject.abi = self.abi
 	stateObject.dirtyStorage = self.dirtyStorage.Copy()
 	stateObject.dirtyValueStorage = self.dirtyValueStorage.Copy()
 	stateObject.originStorage = self.originStorage.Copy()
@@ -30,6 +30,7 @@ import (
 	"github.com/PlatONEnetwork/PlatONE-Go/crypto/sha3"
 
 	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/state/snapshot"
 	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
 	"github.com/PlatONEnetwork/PlatONE-Go/crypto"
 	"github.com/PlatONEnetwork/PlatONE-Go/log"
@@ -64,6 +65,14 @@ type StateDB struct {
 	db   Database
 	trie Trie
 
+	// Flat state snapshot read before the tries, and the changes committed
+	// states add to the snapshots
+	snaps         *snapshot.Tree
+	snap          snapshot.Snapshot
+	snapDestructs map[common.Hash]struct{}
+	snapAccounts  map[common.Hash][]byte
+	snapStorage   map[common.Hash]map[common.Hash][]byte
+
 	// This map holds 'live' objects, which will get modified while processing a state transition.
 	stateObjects      map[common.Address]*stateObject
 	stateObjectsDirty map[common.Address]struct{}
@@ -91,24 +100,53 @@ type StateDB struct {
 	validRevisions []revision
 	nextRevisionId int
 
+	// Accounts read and written, recorded for parallel execution
+	rwset *ReadWriteSet
+
+	// Accesses allowed to the transaction being executed
+	access *accessRules
+
 	lock sync.Mutex
 }
 
 // Create a new state from a given trie.
 func New(root common.Hash, db Database) (*StateDB, error) {
+	return NewWithSnapshot(root, db, nil)
+}
+
+// NewWithSnapshot creates a new state from a given trie, reading the accounts
+// and storage from the snapshot of root in snaps when there is one.
+func NewWithSnapshot(root common.Hash, db Database, snaps *snapshot.Tree) (*StateDB, error) {
 	tr, err := db.OpenTrie(root)
 	if err != nil {
 		return nil, err
 	}
-	return &StateDB{
+	s := &StateDB{
 		db:                db,
 		trie:              tr,
+		snaps:             snaps,
 		stateObjects:      make(map[common.Address]*stateObject),
 		stateObjectsDirty: make(map[common.Address]struct{}),
 		logs:              make(map[common.Hash][]*types.Log),
 		preimages:         make(map[common.Hash][]byte),
 		journal:           newJournal(),
-	}, nil
+	}
+	s.openSnapshot(root)
+	return s, nil
+}
+
+// openSnapshot selects the snapshot of root to read from and clears the
+// changes recorded for the snapshots.
+func (self *StateDB) openSnapshot(root common.Hash) {
+	self.snap = nil
+	if self.snaps == nil {
+		return
+	}
+	if self.snap = self.snaps.Snapshot(root); self.snap != nil {
+		self.snapDestructs = make(map[common.Hash]struct{})
+		self.snapAccounts = make(map[common.Hash][]byte)
+		self.snapStorage = make(map[common.Hash]map[common.Hash][]byte)
+	}
 }
 
 // setError remembers the first non-nil error it is called with.
@@ -130,6 +168,7 @@ func (self *StateDB) Reset(root common.Hash) error {
 		return err
 	}
 	self.trie = tr
+	self.openSnapshot(root)
 	self.stateObjects = make(map[common.Address]*stateObject)
 	self.stateObjectsDirty = make(map[common.Address]struct{})
 	self.thash = common.Hash{}
@@ -153,6 +192,11 @@ func (self *StateDB) AddLog(log *types.Log) {
 	self.logSize++
 }
 
+// LogSize returns the number of logs added to the block so far.
+func (self *StateDB) LogSize() uint {
+	return self.logSize
+}
+
 func (self *StateDB) GetLogs(hash common.Hash) []*types.Log {
 	return self.logs[hash]
 }
@@ -258,8 +302,21 @@ func (self *StateDB) GetCodeHash(addr common.Address) common.Hash {
 	return common.BytesToHash(stateObject.CodeHash())
 }
 
+// GetStorageRoot returns the root of the storage trie of the given account,
+// as committed in the state, the zero hash if it doesn't exist.
+func (self *StateDB) GetStorageRoot(addr common.Address) common.Hash {
+	stateObject := self.getStateObject(addr)
+	if stateObject == nil {
+		return common.Hash{}
+	}
+	return stateObject.data.Root
+}
+
 // GetState retrieves a value from the given account's storage trie.
 func (self *StateDB) GetState(addr common.Address, key []byte) []byte {
+	if self.access != nil {
+		self.access.checkStorage(addr, key)
+	}
 	stateObject := self.getStateObject(addr)
 	keyTrie, _, _ := getKeyValue(addr, key, nil)
 	if stateObject != nil {
@@ -270,6 +327,9 @@ func (self *StateDB) GetState(addr common.Address, key []byte) []byte {
 
 // GetCommittedState retrieves a value from the given account's committed storage trie.
 func (self *StateDB) GetCommittedState(addr common.Address, key []byte) []byte {
+	if self.access != nil {
+		self.access.checkStorage(addr, key)
+	}
 	stateObject := self.getStateObject(addr)
 	if stateObject != nil {
 		var buffer bytes.Buffer
@@ -348,6 +408,9 @@ func (self *StateDB) SetCode(addr common.Address, code []byte) {
 }
 
 func (self *StateDB) SetState(address common.Address, key, value []byte) {
+	if self.access != nil {
+		self.access.checkStorage(address, key)
+	}
 	stateObject := self.GetOrNewStateObject(address)
 	keyTrie, valueKey, value := getKeyValue(address, key, value)
 	if stateObject != nil {
@@ -362,7 +425,15 @@ func getKeyValue(address common.Address, key []byte, value []byte) (string, comm
 	keyTrie := buffer.String()
 
 	//if value != nil && !bytes.Equal(value,[]byte{}){
-	buffer.Reset()
+	return keyTrie, storageValueKey(value), value
+	//}
+	//return keyTrie, common.Hash{}, value
+}
+
+// storageValueKey returns the hash of a storage value, which the storage trie
+// holds in place of the value.
+func storageValueKey(value []byte) common.Hash {
+	var buffer bytes.Buffer
 	buffer.WriteString(storagePrefix)
 	buffer.WriteString(string(value))
 
@@ -370,10 +441,24 @@ func getKeyValue(address common.Address, key []byte, value []byte) (string, comm
 	keccak := sha3.NewKeccak256()
 	keccak.Write(buffer.Bytes())
 	keccak.Sum(valueKey[:0])
+	return valueKey
+}
 
-	return keyTrie, valueKey, value
-	//}
-	//return keyTrie, common.Hash{}, value
+// recordStorage records for the snapshots the new value of a storage entry of
+// the account with the given hash.
+func (self *StateDB) recordStorage(addrHash common.Hash, key string, valueKey common.Hash, value []byte) {
+	if self.snap == nil {
+		return
+	}
+	slots, ok := self.snapStorage[addrHash]
+	if !ok {
+		slots = make(map[common.Hash][]byte)
+		self.snapStorage[addrHash] = slots
+	}
+	if valueKey == emptyStorage {
+		value = nil
+	}
+	slots[crypto.Keccak256Hash([]byte(key))] = value
 }
 
 // Suicide marks the given account as suicided.
@@ -409,6 +494,10 @@ func (self *StateDB) updateStateObject(stateObject *stateObject) {
 		panic(fmt.Errorf("can't encode object at %x: %v", addr[:], err))
 	}
 	self.setError(self.trie.TryUpdate(addr[:], data))
+
+	if self.snap != nil {
+		self.snapAccounts[stateObject.addrHash] = data
+	}
 }
 
 // deleteStateObject removes the given object from the state trie.
@@ -416,10 +505,22 @@ func (self *StateDB) deleteStateObject(stateObject *stateObject) {
 	stateObject.deleted = true
 	addr := stateObject.Address()
 	self.setError(self.trie.TryDelete(addr[:]))
+
+	if self.snap != nil {
+		self.snapDestructs[stateObject.addrHash] = struct{}{}
+		self.snapAccounts[stateObject.addrHash] = nil
+		delete(self.snapStorage, stateObject.addrHash)
+	}
 }
 
 // Retrieve a state object given by the address. Returns nil if not found.
 func (self *StateDB) getStateObject(addr common.Address) (stateObject *stateObject) {
+	if self.rwset != nil {
+		self.rwset.Reads[addr] = struct{}{}
+	}
+	if self.access != nil {
+		self.access.checkAccount(addr)
+	}
 	// Prefer 'live' objects.
 	if obj := self.stateObjects[addr]; obj != nil {
 		if obj.deleted {
@@ -428,8 +529,17 @@ func (self *StateDB) getStateObject(addr common.Address) (stateObject *stateObje
 		return obj
 	}
 
-	// Load the object from the database.
-	enc, err := self.trie.TryGet(addr[:])
+	// Load the object from the snapshot if it has the account, the trie otherwise.
+	var (
+		enc []byte
+		err error
+	)
+	if self.snap != nil {
+		enc, err = self.snap.Account(crypto.Keccak256Hash(addr[:]))
+	}
+	if self.snap == nil || err != nil {
+		enc, err = self.trie.TryGet(addr[:])
+	}
 	if len(enc) == 0 {
 		self.setError(err)
 		return nil
@@ -467,7 +577,15 @@ func (self *StateDB) createObject(addr common.Address) (newobj, prev *stateObjec
 	if prev == nil {
 		self.journal.append(createObjectChange{account: &addr})
 	} else {
-		self.journal.append(resetObjectChange{prev: prev})
+		change := resetObjectChange{prev: prev}
+		if self.snap != nil {
+			// The new object does not inherit the storage of the previous one
+			_, change.prevdestruct = self.snapDestructs[prev.addrHash]
+			change.prevstorage = self.snapStorage[prev.addrHash]
+			self.snapDestructs[prev.addrHash] = struct{}{}
+			delete(self.snapStorage, prev.addrHash)
+		}
+		self.journal.append(change)
 	}
 	self.setStateObject(newobj)
 	return newobj, prev
@@ -545,6 +663,8 @@ func (self *StateDB) Copy() *StateDB {
 	state := &StateDB{
 		db:                self.db,
 		trie:              self.db.CopyTrie(self.trie),
+		snaps:             self.snaps,
+		snap:              self.snap,
 		stateObjects:      make(map[common.Address]*stateObject, len(self.journal.dirties)),
 		stateObjectsDirty: make(map[common.Address]struct{}, len(self.journal.dirties)),
 		refund:            self.refund,
@@ -584,9 +704,31 @@ func (self *StateDB) Copy() *StateDB {
 	for hash, preimage := range self.preimages {
 		state.preimages[hash] = preimage
 	}
+	if self.snap != nil {
+		state.snapDestructs = make(map[common.Hash]struct{}, len(self.snapDestructs))
+		for hash := range self.snapDestructs {
+			state.snapDestructs[hash] = struct{}{}
+		}
+		state.snapAccounts = make(map[common.Hash][]byte, len(self.snapAccounts))
+		for hash, data := range self.snapAccounts {
+			state.snapAccounts[hash] = data
+		}
+		state.snapStorage = make(map[common.Hash]map[common.Hash][]byte, len(self.snapStorage))
+		for hash, slots := range self.snapStorage {
+			state.snapStorage[hash] = copySnapSlots(slots)
+		}
+	}
 	return state
 }
 
+func copySnapSlots(slots map[common.Hash][]byte) map[common.Hash][]byte {
+	cpy := make(map[common.Hash][]byte, len(slots))
+	for hash, data := range slots {
+		cpy[hash] = data
+	}
+	return cpy
+}
+
 // Snapshot returns an identifier for the current revision of the state.
 func (self *StateDB) Snapshot() int {
 	id := self.nextRevisionId
@@ -638,6 +780,9 @@ func (s *StateDB) Finalise(deleteEmptyObjects bool) {
 			s.updateStateObject(stateObject)
 		}
 		s.stateObjectsDirty[addr] = struct{}{}
+		if s.rwset != nil {
+			s.rwset.Writes[addr] = struct{}{}
+		}
 	}
 	// Invalidate journal because reverting across transactions is not allowed.
 	s.clearJournalAndRefund()
@@ -713,7 +858,13 @@ func (s *StateDB) Commit(deleteEmptyObjects bool) (root common.Hash, err error)
 		}
 		return nil
 	})
-
+	if err == nil && s.snap != nil {
+		// The snapshot of the new state takes over the recorded changes
+		if err := s.snaps.Update(root, s.snap.Root(), s.snapDestructs, s.snapAccounts, s.snapStorage); err != nil {
+			log.Warn("Failed to update state snapshot", "root", root, "parent", s.snap.Root(), "err", err)
+		}
+		s.openSnapshot(root)
+	}
 	log.Debug("Trie cache stats after commit", "misses", trie.CacheMisses(), "unloads", trie.CacheUnloads())
 	return root, err
 }
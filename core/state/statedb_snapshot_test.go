@@ -0,0 +1,191 @@
+package state
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/rawdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/state/snapshot"
+	"github.com/PlatONEnetwork/PlatONE-Go/crypto"
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
+	"github.com/PlatONEnetwork/PlatONE-Go/trie"
+)
+
+// waitSnapshot waits for the generation of the flat state of root.
+func waitSnapshot(t *testing.T, db ethdb.Database, root common.Hash) {
+	for deadline := time.Now().Add(5 * time.Second); rawdb.ReadSnapshotRoot(db) != root; {
+		if time.Now().After(deadline) {
+			t.Fatalf("snapshot of %x not generated", root)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// checkSnapshotRoot rebuilds the tries from the flat state persisted in db and
+// checks their root against the state root.
+func checkSnapshotRoot(t *testing.T, db *ethdb.MemDatabase, root common.Hash) {
+	var (
+		accounts = make(map[common.Hash][]byte)
+		storage  = make(map[common.Hash]map[common.Hash][]byte)
+	)
+	for _, key := range db.Keys() {
+		switch {
+		case len(key) == 1+common.HashLength && bytes.HasPrefix(key, rawdb.SnapshotAccountPrefix):
+			accounts[common.BytesToHash(key[1:])], _ = db.Get(key)
+
+		case len(key) == 1+2*common.HashLength && bytes.HasPrefix(key, rawdb.SnapshotStoragePrefix):
+			hash := common.BytesToHash(key[1 : 1+common.HashLength])
+			if storage[hash] == nil {
+				storage[hash] = make(map[common.Hash][]byte)
+			}
+			storage[hash][common.BytesToHash(key[1+common.HashLength:])], _ = db.Get(key)
+		}
+	}
+	triedb := trie.NewDatabase(ethdb.NewMemDatabase())
+	accTrie, _ := trie.New(common.Hash{}, triedb)
+	for hash, data := range accounts {
+		var account Account
+		if err := rlp.DecodeBytes(data, &account); err != nil {
+			t.Fatalf("account %x: %v", hash, err)
+		}
+		// The storage trie holds the hashes of the values under the hashed keys
+		storeTrie, _ := trie.New(common.Hash{}, triedb)
+		for key, value := range storage[hash] {
+			valueKey := storageValueKey(value)
+			enc, _ := rlp.EncodeToBytes(bytes.TrimLeft(valueKey[:], "\x00"))
+			storeTrie.Update(key[:], enc)
+		}
+		if storeTrie.Hash() != account.Root {
+			t.Errorf("account %x: storage root %x, want %x", hash, storeTrie.Hash(), account.Root)
+		}
+		accTrie.Update(hash[:], data)
+	}
+	for hash := range storage {
+		if _, ok := accounts[hash]; !ok {
+			t.Errorf("storage left for missing account %x", hash)
+		}
+	}
+	if accTrie.Hash() != root {
+		t.Errorf("snapshot root %x, want %x", accTrie.Hash(), root)
+	}
+}
+
+// commitSnapshotState commits statedb and its tries.
+func commitSnapshotState(t *testing.T, statedb *StateDB) common.Hash {
+	root, err := statedb.Commit(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := statedb.Database().TrieDB().Commit(root, false); err != nil {
+		t.Fatal(err)
+	}
+	return root
+}
+
+// Tests that the states read through the flat snapshot match the tries while
+// blocks change it, and that the flattened snapshot matches the state root.
+func TestFlatSnapshotReads(t *testing.T) {
+	var (
+		db       = ethdb.NewMemDatabase()
+		sdb      = NewDatabase(db)
+		contract = common.Address{0xc0}
+		doomed   = common.Address{0xd0}
+	)
+	statedb, _ := New(common.Hash{}, sdb)
+	statedb.SetNonce(contract, 1)
+	statedb.SetNonce(doomed, 1)
+	for i := byte(1); i <= 16; i++ {
+		statedb.AddBalance(common.Address{i}, big.NewInt(int64(i)))
+		statedb.SetState(contract, []byte{i}, []byte{i, i})
+	}
+	statedb.SetState(doomed, []byte("key"), []byte("value"))
+	root := commitSnapshotState(t, statedb)
+
+	snaps := snapshot.New(db, sdb.TrieDB(), root)
+	defer snaps.Stop()
+	waitSnapshot(t, db, root)
+	checkSnapshotRoot(t, db, root)
+
+	for block := byte(1); block <= 3; block++ {
+		statedb, _ := NewWithSnapshot(root, sdb, snaps)
+		if statedb.snap == nil {
+			t.Fatalf("block %d: no snapshot of the parent state", block)
+		}
+		statedb.AddBalance(common.Address{block}, big.NewInt(100))
+		statedb.SetState(contract, []byte{block}, nil)
+		statedb.SetState(contract, []byte{0x10 + block}, []byte{block})
+		statedb.Finalise(true)
+		if block == 2 {
+			statedb.Suicide(doomed)
+		}
+		root = commitSnapshotState(t, statedb)
+		if err := snaps.Cap(root, 1); err != nil {
+			t.Fatal(err)
+		}
+		trieState, _ := New(root, sdb)
+		snapState, _ := NewWithSnapshot(root, sdb, snaps)
+		if snapState.snap == nil {
+			t.Fatalf("block %d: no snapshot of the new state", block)
+		}
+		for i := byte(0); i <= 0x14; i++ {
+			if have, want := snapState.GetBalance(common.Address{i}), trieState.GetBalance(common.Address{i}); have.Cmp(want) != 0 {
+				t.Errorf("block %d: balance of %d %v, want %v", block, i, have, want)
+			}
+			if have, want := snapState.GetState(contract, []byte{i}), trieState.GetState(contract, []byte{i}); !bytes.Equal(have, want) {
+				t.Errorf("block %d: slot %d %x, want %x", block, i, have, want)
+			}
+		}
+		if have, want := snapState.Exist(doomed), trieState.Exist(doomed); have != want {
+			t.Errorf("block %d: destructed account exists %v, want %v", block, have, want)
+		}
+	}
+	if err := snaps.Cap(root, 0); err != nil {
+		t.Fatal(err)
+	}
+	if rawdb.ReadSnapshotRoot(db) != root {
+		t.Fatalf("flattened snapshot not at the head state")
+	}
+	checkSnapshotRoot(t, db, root)
+}
+
+// Tests that a flat state left behind the head state, as after an unclean
+// shutdown, is regenerated for the head state.
+func TestFlatSnapshotRegeneration(t *testing.T) {
+	var (
+		db       = ethdb.NewMemDatabase()
+		sdb      = NewDatabase(db)
+		contract = common.Address{0xc0}
+	)
+	statedb, _ := New(common.Hash{}, sdb)
+	statedb.SetNonce(contract, 1)
+	statedb.SetNonce(common.Address{0xd0}, 1)
+	statedb.SetState(contract, []byte("a"), []byte("1"))
+	statedb.SetState(common.Address{0xd0}, []byte("b"), []byte("2"))
+	root := commitSnapshotState(t, statedb)
+
+	snaps := snapshot.New(db, sdb.TrieDB(), root)
+	waitSnapshot(t, db, root)
+
+	// Move the head state on without flattening the snapshot layer
+	statedb, _ = NewWithSnapshot(root, sdb, snaps)
+	statedb.Suicide(common.Address{0xd0})
+	statedb.SetState(contract, []byte("a"), []byte("3"))
+	head := commitSnapshotState(t, statedb)
+	snaps.Stop()
+
+	if rawdb.ReadSnapshotRoot(db) != root {
+		t.Fatalf("unflattened layer persisted")
+	}
+	snaps = snapshot.New(db, sdb.TrieDB(), head)
+	defer snaps.Stop()
+	waitSnapshot(t, db, head)
+	checkSnapshotRoot(t, db, head)
+
+	if data, _ := snaps.Snapshot(head).Account(crypto.Keccak256Hash(common.Address{0xd0}.Bytes())); data != nil {
+		t.Fatalf("deleted account left in the regenerated snapshot")
+	}
+}
@@ -0,0 +1,79 @@
+package state
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
+	"github.com/PlatONEnetwork/PlatONE-Go/trie"
+)
+
+// StorageEntry is an entry of the storage of a contract.
+type StorageEntry struct {
+	Hash  common.Hash // Key of the entry in the storage trie, the hash of the full key
+	Key   []byte      // Key set by the contract, nil if the full key is unknown
+	Value []byte
+}
+
+// StorageRange is a page of the storage of a contract, in the order of the
+// keys of the storage trie.
+type StorageRange struct {
+	Root    common.Hash // Root of the storage trie
+	Entries []StorageEntry
+	Next    []byte // Storage trie key of the first entry of the next page, nil after the last page
+}
+
+// StorageRange returns up to max entries of the storage of addr, from the one
+// with the given storage trie key on. The keys set by the contract are decoded
+// from the full keys, prefixed by the address as WasmStateDB stores them. The
+// range must hold at least one entry, for the next page to start further on.
+func (self *StateDB) StorageRange(addr common.Address, start []byte, max int) (*StorageRange, error) {
+	if max <= 0 {
+		return nil, fmt.Errorf("invalid storage range size %d", max)
+	}
+	st := self.StorageTrie(addr)
+	if st == nil {
+		return nil, fmt.Errorf("account %x doesn't exist", addr)
+	}
+	var (
+		prefix = addr.String()
+		result = &StorageRange{Root: st.Hash()}
+		it     = trie.NewIterator(st.NodeIterator(start))
+	)
+	for i := 0; i < max && it.Next(); i++ {
+		_, content, _, err := rlp.Split(it.Value)
+		if err != nil {
+			return nil, err
+		}
+		// The storage trie holds the hash of the value, the value is its preimage
+		entry := StorageEntry{
+			Hash:  common.BytesToHash(it.Key),
+			Value: st.GetKey(common.BytesToHash(content).Bytes()),
+		}
+		if key := string(st.GetKey(it.Key)); strings.HasPrefix(key, prefix) {
+			entry.Key = []byte(key[len(prefix):])
+		}
+		result.Entries = append(result.Entries, entry)
+	}
+	if it.Next() {
+		result.Next = common.CopyBytes(it.Key)
+	}
+	return result, it.Err
+}
+
+// ProveStorage writes to proofDb the nodes of the storage trie of addr proving
+// the entries with the given storage trie keys.
+func (self *StateDB) ProveStorage(addr common.Address, hashes []common.Hash, proofDb ethdb.Putter) error {
+	st := self.StorageTrie(addr)
+	if st == nil {
+		return fmt.Errorf("account %x doesn't exist", addr)
+	}
+	for _, hash := range hashes {
+		if err := st.Prove(hash.Bytes(), 0, proofDb); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package state
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/trie"
+)
+
+// Tests that the storage of a contract is enumerated page by page with the
+// keys set by the contract, and that the entries are proven by the root.
+func TestStorageRange(t *testing.T) {
+	state, _ := New(common.Hash{}, NewDatabase(ethdb.NewMemDatabase()))
+	addr := common.BytesToAddress([]byte("contract"))
+
+	want := map[string]string{"a": "1", "bb": "22", "key\x00c": "333", "d": "4444", "e": "55555"}
+	for key, value := range want {
+		state.SetState(addr, []byte(key), []byte(value))
+	}
+	root, err := state.Commit(false)
+	if err != nil {
+		t.Fatalf("commit failed: %v", err)
+	}
+	state, _ = New(root, state.Database())
+
+	var (
+		have  = make(map[string]string)
+		start []byte
+		pages int
+	)
+	for {
+		page, err := state.StorageRange(addr, start, 2)
+		if err != nil {
+			t.Fatalf("page %d: %v", pages, err)
+		}
+		pages++
+		var hashes []common.Hash
+		for _, entry := range page.Entries {
+			have[string(entry.Key)] = string(entry.Value)
+			hashes = append(hashes, entry.Hash)
+		}
+		proof := ethdb.NewMemDatabase()
+		if err := state.ProveStorage(addr, hashes, proof); err != nil {
+			t.Fatalf("page %d: proof failed: %v", pages, err)
+		}
+		for _, hash := range hashes {
+			if _, _, err := trie.VerifyProof(page.Root, hash.Bytes(), proof); err != nil {
+				t.Fatalf("page %d: entry %x not proven: %v", pages, hash, err)
+			}
+		}
+		if page.Next == nil {
+			break
+		}
+		if bytes.Equal(page.Next, start) {
+			t.Fatal("resume token not moving forward")
+		}
+		start = page.Next
+	}
+	if pages != 3 {
+		t.Errorf("pages mismatch: have %d, want 3", pages)
+	}
+	if len(have) != len(want) {
+		t.Fatalf("entries mismatch: have %v, want %v", have, want)
+	}
+	for key, value := range want {
+		if have[key] != value {
+			t.Errorf("entry %q mismatch: have %q, want %q", key, have[key], value)
+		}
+	}
+	if _, err := state.StorageRange(common.Address{1}, nil, 1); err == nil {
+		t.Error("storage of a missing account returned")
+	}
+	if _, err := state.StorageRange(addr, nil, 0); err == nil {
+		t.Error("empty range returned")
+	}
+}
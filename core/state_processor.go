@@ -18,6 +18,7 @@ package core
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/PlatONEnetwork/PlatONE-Go/common"
 	"github.com/PlatONEnetwork/PlatONE-Go/consensus"
@@ -66,13 +67,33 @@ func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg
 		gp       = new(GasPool).AddGas(block.GasLimit())
 	)
 
+	var executor *ParallelExecutor
+	if p.config.ParallelExecution {
+		executor = NewParallelExecutor(p.config, p.bc, nil, gp, statedb, header, block.Hash(), cfg)
+	}
 	// Iterate over and process the individual transactios
-	for i, tx := range block.Transactions() {
+	txs := block.Transactions()
+	for i, tx := range txs {
 		rpc.MonitorWriteData(rpc.TransactionExecuteStartTime, tx.Hash().String(), "", p.bc.extdb)
 		txHash := tx.Hash()
-		statedb.Prepare(txHash, block.Hash(), i)
 		log.Trace("Perform Transaction", "txHash", fmt.Sprintf("%x", txHash[:log.LogHashLen]), "blockNumber", block.Number())
-		receipt, _, err := ApplyTransaction(p.config, p.bc, nil, gp, statedb, header, tx, usedGas, cfg)
+		var (
+			receipt *types.Receipt
+			err     error
+		)
+		if executor != nil {
+			if !executor.Speculated(tx) {
+				end := i + ParallelBatch
+				if end > len(txs) {
+					end = len(txs)
+				}
+				executor.Speculate(txs[i:end])
+			}
+			receipt, err = executor.Apply(tx, i, usedGas)
+		} else {
+			statedb.Prepare(txHash, block.Hash(), i)
+			receipt, _, err = ApplyTransaction(p.config, p.bc, nil, gp, statedb, header, tx, usedGas, cfg)
+		}
 		rpc.MonitorWriteData(rpc.TransactionExecuteEndTime, tx.Hash().String(), "", p.bc.extdb)
 		if err != nil {
 			rpc.MonitorWriteData(rpc.TransactionExecuteStatus, tx.Hash().String(), "false", p.bc.extdb)
@@ -99,18 +120,28 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *commo
 	var gas uint64
 	var gasPrice int64
 	var failed bool
+	var revertReason string
+	var calls []types.CallResult
 	var err error
 	signer := types.MakeSigner(config)
 	to := common.Address{}
 	if tx.To() != nil {
 		to = *tx.To()
 	}
+	defer ExecStats.observe(to, time.Now())
+	sysCfg := systemConfigAt(bc, header)
+
 	if tx.Data() == nil && statedb.GetCode(to) == nil {
+		// plain transfers skip the state transition, check their window here
+		if err := checkValidityWindow(tx.ValidityWindow(), header.Number.Uint64(), header.Time.Uint64()); err != nil {
+			return nil, 0, err
+		}
 		value := tx.Value()
 		from, _ = types.Sender(signer, tx)
 		if statedb.GetBalance(from).Cmp(value) < 0 {
 			failed = true
 			err = vm.ErrInsufficientBalance
+			revertReason = err.Error()
 		} else {
 			statedb.SubBalance(from, value)
 			statedb.AddBalance(to, value)
@@ -134,6 +165,12 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *commo
 		vmenv := vm.NewEVM(context, statedb, config, cfg)
 		// Apply the transaction to the current state (included in the env)
 		_, gas, gasPrice, failed, err = ApplyMessage(vmenv, msg, gp)
+		revertReason = vmenv.Failure()
+		calls = vmenv.Calls()
+		// a transaction outside its validity window cannot be part of the block
+		if err == ErrTxNotYetValid || err == ErrTxExpired {
+			return nil, 0, err
+		}
 
 	}
 
@@ -156,7 +193,7 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *commo
 		}
 	}
 
-	if common.SysCfg.GetIsTxUseGas() {
+	if sysCfg.GetIsTxUseGas() {
 		data := [][]byte{}
 		data = append(data, []byte(common.Int64ToBytes(gasPrice)))
 		encodeData, _ := rlp.EncodeToBytes(data)
@@ -179,6 +216,8 @@ func ApplyTransaction(config *params.ChainConfig, bc ChainContext, author *commo
 	receipt := types.NewReceipt(root, failed, *usedGas)
 	receipt.TxHash = tx.Hash()
 	receipt.GasUsed = gas
+	receipt.RevertReason = revertReason
+	receipt.Calls = calls
 	// if the transaction created a contract, store the creation address in the receipt.
 	if tx.To() == nil && err == nil {
 		receipt.ContractAddress = crypto.CreateAddress(from, statedb.GetNonce(from)-1)
@@ -18,6 +18,7 @@ package core
 
 import (
 	"errors"
+	"fmt"
 	"math"
 	"math/big"
 	"strings"
@@ -25,7 +26,9 @@ import (
 	"github.com/PlatONEnetwork/PlatONE-Go/common/syscontracts"
 
 	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
 	"github.com/PlatONEnetwork/PlatONE-Go/core/vm"
+	"github.com/PlatONEnetwork/PlatONE-Go/crypto"
 	"github.com/PlatONEnetwork/PlatONE-Go/life/utils"
 	"github.com/PlatONEnetwork/PlatONE-Go/log"
 	"github.com/PlatONEnetwork/PlatONE-Go/params"
@@ -81,6 +84,10 @@ type Message interface {
 	Nonce() uint64
 	CheckNonce() bool
 	Data() []byte
+	TxType() uint64
+	ValidityWindow() *types.ValidityWindow
+	AccessList() types.AccessList
+	FeePayer() *common.Address
 }
 
 // IntrinsicGas computes the 'intrinsic gas' for a message with the given data.
@@ -158,7 +165,7 @@ func (st *StateTransition) useGas(amount uint64) error {
 }
 
 func (st *StateTransition) buyGas() error {
-	gas := uint64(common.SysCfg.GetTxGasLimit())
+	gas := uint64(sysConfig(st.evm).GetTxGasLimit())
 	if err := st.gp.SubGas(gas); err != nil {
 		return err
 	}
@@ -168,8 +175,31 @@ func (st *StateTransition) buyGas() error {
 	return nil
 }
 
+// payer returns the account paying the fees of the message: its fee payer if
+// it has one, its sender otherwise.
+func (st *StateTransition) payer() common.Address {
+	if payer := st.msg.FeePayer(); payer != nil {
+		return *payer
+	}
+	return st.msg.From()
+}
+
+// feeTargets returns the accounts a transaction of the given type calls, the
+// recipients of its calls for a batch, nil standing for a contract creation.
+func feeTargets(txType uint64, to *common.Address, data []byte) []*common.Address {
+	if txType != types.BatchTxType {
+		return []*common.Address{to}
+	}
+	calls, _ := types.DecodeBatchCalls(data)
+	targets := make([]*common.Address, len(calls))
+	for i := range calls {
+		targets[i] = calls[i].To
+	}
+	return targets
+}
+
 func (st *StateTransition) buyContractGas(contractAddr common.Address) error {
-	addr := st.msg.From().String()
+	addr := st.payer().String()
 	addr = strings.ToLower(addr)
 	params := []interface{}{addr, st.msg.Gas()}
 
@@ -210,6 +240,20 @@ func (st *StateTransition) preCheck() error {
 	return st.buyGas()
 }
 
+// checkValidityWindow checks that a block with the given number and timestamp
+// lies within the validity window of a transaction, if it has one.
+func checkValidityWindow(w *types.ValidityWindow, number, time uint64) error {
+	switch {
+	case w == nil:
+		return nil
+	case w.NotYetValid(number, time):
+		return ErrTxNotYetValid
+	case w.Expired(number, time):
+		return ErrTxExpired
+	}
+	return nil
+}
+
 func (st *StateTransition) preContractGasCheck(contractAddr common.Address) error {
 	return st.buyContractGas(contractAddr)
 }
@@ -274,14 +318,109 @@ func fwCheck(stateDb vm.StateDB, contractAddr common.Address, caller common.Addr
 }
 
 func (st *StateTransition) ifUseContractTokenAsFee() (common.Address, bool) {
-	isUseContractToken := common.SysCfg.GetIsTxUseGas()
-	contractAddr := common.SysCfg.GetGasContractAddress()
+	isUseContractToken := sysConfig(st.evm).GetIsTxUseGas()
+	contractAddr := sysConfig(st.evm).GetGasContractAddress()
 	if contractAddr == zeroAddress {
 		return contractAddr, false
 	}
 	return contractAddr, isUseContractToken
 }
 
+// executeBatch executes the calls of a batch transaction in order, keeping
+// their changes only if all of them succeed. The outcomes of the calls up to
+// the first failed one are recorded for the receipt.
+func (st *StateTransition) executeBatch(sender vm.AccountRef) (ret []byte, err error) {
+	msg, evm := st.msg, st.evm
+	if st.value.Sign() != 0 || msg.To() == nil || *msg.To() != syscontracts.BatchInvokeAddress {
+		return nil, ErrInvalidBatch
+	}
+	calls, err := types.DecodeBatchCalls(st.data)
+	if err != nil {
+		return nil, ErrInvalidBatch
+	}
+	var (
+		snapshot = st.state.Snapshot()
+		results  = make([]types.CallResult, 0, len(calls))
+	)
+	for i, call := range calls {
+		var (
+			logs   = st.state.LogSize()
+			result = types.CallResult{Status: types.ReceiptStatusSuccessful}
+		)
+		if call.To == nil {
+			if !checkContractDeployPermission(msg.From(), evm) {
+				ret, err = nil, PermissionErr
+			} else {
+				restore := st.limitAccess(crypto.CreateAddress(msg.From(), st.state.GetNonce(msg.From())))
+				ret, result.ContractAddress, st.gas, err = evm.Create(sender, call.Data, st.gas, call.Value)
+				ret, err = restore(ret, err)
+			}
+		} else if out, pass := fwCheck(evm.StateDB, *call.To, msg.From(), call.Data); !pass {
+			ret, err = out, PermissionErr
+		} else {
+			restore := st.limitAccess(*call.To)
+			ret, st.gas, err = evm.Call(sender, *call.To, call.Data, st.gas, call.Value)
+			ret, err = restore(ret, err)
+		}
+		result.Logs = uint64(st.state.LogSize() - logs)
+		if err != nil {
+			// Undo all calls, their logs included
+			st.state.RevertToSnapshot(snapshot)
+			for j := range results {
+				results[j].ContractAddress = common.Address{}
+				results[j].Logs = 0
+			}
+			result.Status = types.ReceiptStatusFailed
+			result.ContractAddress = common.Address{}
+			result.Logs = 0
+			result.RevertReason = vm.RevertReason(ret, err)
+			evm.RecordCalls(append(results, result))
+			return nil, fmt.Errorf("batch call %d: %s", i, result.RevertReason)
+		}
+		results = append(results, result)
+	}
+	evm.RecordCalls(results)
+	return ret, nil
+}
+
+// limitAccess limits the accesses of the call to target to the access list
+// of the message, if it has one. The returned function lifts the limits
+// after the call, failing it and undoing its changes if it accessed anything
+// outside them.
+func (st *StateTransition) limitAccess(target common.Address) func([]byte, error) ([]byte, error) {
+	list := st.msg.AccessList()
+	if list == nil {
+		return func(ret []byte, err error) ([]byte, error) { return ret, err }
+	}
+	implicit := []common.Address{
+		st.msg.From(), target, st.evm.Coinbase,
+		syscontracts.UserManagementAddress, syscontracts.NodeManagementAddress,
+		syscontracts.CnsManagementAddress, syscontracts.ParameterManagementAddress,
+		syscontracts.FirewallManagementAddress, syscontracts.GroupManagementAddress,
+		syscontracts.ContractDataProcessorAddress, syscontracts.FeeSponsorManagementAddress,
+	}
+	for addr := range vm.PrecompiledContracts {
+		implicit = append(implicit, addr)
+	}
+	for addr := range vm.PlatONEPrecompiledContracts {
+		implicit = append(implicit, addr)
+	}
+	snapshot := st.state.Snapshot()
+	st.state.SetAccessList(list, implicit)
+	return func(ret []byte, err error) ([]byte, error) {
+		violation := st.state.AccessViolation()
+		st.state.SetAccessList(nil, nil)
+		if violation == nil {
+			return ret, err
+		}
+		// The nonce of the sender is kept, a creation increments it in the call
+		nonce := st.state.GetNonce(st.msg.From())
+		st.state.RevertToSnapshot(snapshot)
+		st.state.SetNonce(st.msg.From(), nonce)
+		return nil, violation
+	}
+}
+
 // TransitionDb will transition the state by applying the current message and
 // returning the result including the used gas. It returns an error if failed.
 // An error indicates a consensus issue.
@@ -296,10 +435,19 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, gasPrice
 		msg    = st.msg
 		sender = vm.AccountRef(msg.From())
 	)
+	if err = checkValidityWindow(msg.ValidityWindow(), evm.BlockNumber.Uint64(), evm.Time.Uint64()); err != nil {
+		return
+	}
 	isCallSysParam := isCallParamManager(msg.To())
 	feeContractAddr, isUseContractToken := st.ifUseContractTokenAsFee()
 	isUseContractToken = isUseContractToken && msg.Nonce() != 0 && !isCallSysParam
 	if isUseContractToken {
+		// A sponsor only pays for what its policy covers
+		if payer := msg.FeePayer(); payer != nil {
+			if err = vm.CheckFeeSponsorship(st.state, *payer, feeTargets(msg.TxType(), msg.To(), st.data), msg.Gas(), evm.Time.Uint64()); err != nil {
+				return
+			}
+		}
 		if err = st.preContractGasCheck(feeContractAddr); err != nil {
 			log.Error("PreContractGasCheck", "err:", err)
 			return
@@ -327,13 +475,20 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, gasPrice
 		return nil, 0, gasPrice, false, err
 	}
 
-	if contractCreation {
+	if msg.TxType() == types.BatchTxType {
+		st.state.SetNonce(msg.From(), st.state.GetNonce(sender.Address())+1)
+		ret, vmerr = st.executeBatch(sender)
+	} else if contractCreation {
 		allowDeployContract := checkContractDeployPermission(sender.Address(), evm)
 		if !allowDeployContract {
 			st.state.SetNonce(msg.From(), st.state.GetNonce(sender.Address())+1)
+			evm.RecordFailure(nil, PermissionErr)
 			return nil, 0, gasPrice, true, PermissionErr
 		}
+		created := crypto.CreateAddress(msg.From(), st.state.GetNonce(msg.From()))
+		restore := st.limitAccess(created)
 		ret, _, st.gas, vmerr = evm.Create(sender, st.data, st.gas, st.value)
+		ret, vmerr = restore(ret, vmerr)
 	} else {
 		st.state.SetNonce(msg.From(), st.state.GetNonce(sender.Address())+1)
 		var pass bool
@@ -344,9 +499,12 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, gasPrice
 		} else {
 			// Increment the nonce for the next transaction
 			// If the transaction is cns-type, do not increment the nonce
+			restore := st.limitAccess(st.to())
 			ret, st.gas, vmerr = evm.Call(sender, st.to(), st.data, st.gas, st.value)
+			ret, vmerr = restore(ret, vmerr)
 		}
 	}
+	evm.RecordFailure(ret, vmerr)
 	if vmerr != nil {
 		log.Debug("VM returned with error", "err", vmerr)
 		// The only possible consensus-error would be if there wasn't
@@ -358,6 +516,9 @@ func (st *StateTransition) TransitionDb() (ret []byte, usedGas uint64, gasPrice
 	}
 	if isUseContractToken {
 		err = st.refundContractGas(feeContractAddr)
+		if payer := msg.FeePayer(); payer != nil && err == nil {
+			err = vm.ChargeFeeSponsorship(st.state, *payer, st.gasUsed(), evm.Time.Uint64())
+		}
 	} else {
 		st.refundGas()
 	}
@@ -390,7 +551,7 @@ func (st *StateTransition) refundContractGas(contractAddr common.Address) error
 	// Return ETH for remaining gas, exchanged at the original rate.
 	//remaining := new(big.Int).Mul(new(big.Int).SetUint64(st.gas), st.gasPrice)
 	//st.state.AddBalance(st.msg.From(), remaining)
-	addr := st.msg.From().String()
+	addr := st.payer().String()
 	addr = strings.ToLower(addr)
 	params := []interface{}{addr, st.gas}
 	_, _, err := st.doCallContract(contractAddr, "refundFee", params)
@@ -421,8 +582,17 @@ func (st *StateTransition) gasUsed() uint64 {
 	return st.initialGas - st.gas
 }
 
+// sysConfig returns the system parameters in force for the block evm runs
+// in, the ones after the head of the chain if its context has none.
+func sysConfig(evm *vm.EVM) *common.SystemConfig {
+	if evm.SysConfig != nil {
+		return evm.SysConfig
+	}
+	return common.SysCfg
+}
+
 func checkContractDeployPermission(sender common.Address, evm *vm.EVM) bool {
-	checkPermission := common.SysCfg.IfCheckContractDeployPermission()
+	checkPermission := sysConfig(evm).IfCheckContractDeployPermission()
 	if checkPermission == 0 {
 		return true
 	}
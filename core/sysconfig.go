@@ -0,0 +1,108 @@
+package core
+
+import (
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/common/syscontracts"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/crypto"
+	"github.com/PlatONEnetwork/PlatONE-Go/log"
+)
+
+const sysConfigCacheLimit = 256
+
+// SystemConfigReader is implemented by the chains resolving the system
+// configuration in force at each of their blocks.
+type SystemConfigReader interface {
+	// SystemConfigAt returns the system configuration in force for the block
+	// of header, nil if it cannot be resolved.
+	SystemConfigAt(header *types.Header) *common.SystemConfig
+}
+
+// systemConfigAt returns the system configuration in force for the block of
+// header on chain, the one after the head if chain does not resolve them.
+func systemConfigAt(chain interface{}, header *types.Header) *common.SystemConfig {
+	if reader, ok := chain.(SystemConfigReader); ok {
+		if config := reader.SystemConfigAt(header); config != nil {
+			return config
+		}
+	}
+	return common.SysCfg
+}
+
+// sysConfigContracts are the system contracts whose storage the system
+// configuration is read from, directly or by the calls they make.
+var sysConfigContracts = []common.Address{
+	syscontracts.UserManagementAddress, syscontracts.NodeManagementAddress,
+	syscontracts.CnsManagementAddress, syscontracts.ParameterManagementAddress,
+	syscontracts.FirewallManagementAddress, syscontracts.GroupManagementAddress,
+	syscontracts.ContractDataProcessorAddress, syscontracts.FeeSponsorManagementAddress,
+}
+
+// sysConfigStorage identifies the storage of the system contracts in a state,
+// which the system configuration only depends on.
+type sysConfigStorage common.Hash
+
+// SystemConfigAt returns the system configuration in force for the block of
+// header: the one the system contracts hold in the state of its parent, or in
+// the genesis state for the genesis block.
+func (bc *BlockChain) SystemConfigAt(header *types.Header) *common.SystemConfig {
+	number := header.Number.Uint64()
+	if number == 0 {
+		return bc.systemConfig(0, bc.genesisBlock.Header())
+	}
+	parent := bc.GetHeader(header.ParentHash, number-1)
+	if parent == nil {
+		return nil
+	}
+	return bc.systemConfig(number, parent)
+}
+
+// SystemConfigByNumber returns the system configuration in force for the
+// block number on top of the canonical chain, which may be the next block to
+// be proposed.
+func (bc *BlockChain) SystemConfigByNumber(number uint64) *common.SystemConfig {
+	if number == 0 {
+		return bc.systemConfig(0, bc.genesisBlock.Header())
+	}
+	parent := bc.GetHeaderByNumber(number - 1)
+	if parent == nil {
+		return nil
+	}
+	return bc.systemConfig(number, parent)
+}
+
+// systemConfig returns the system configuration in force for the block number
+// built on parent. The blocks sharing the storage of the system contracts in
+// the states of their parents share their configuration, whatever the calls
+// that led to them.
+func (bc *BlockChain) systemConfig(number uint64, parent *types.Header) *common.SystemConfig {
+	hash := parent.Hash()
+	if cached, ok := bc.sysConfigs.Get(hash); ok {
+		return cached.(*common.SystemConfig)
+	}
+	statedb, err := bc.StateAt(parent.Root)
+	if err != nil {
+		log.Debug("Failed to resolve system configuration", "number", number, "parent", hash, "err", err)
+		return nil
+	}
+	var roots []byte
+	for _, addr := range sysConfigContracts {
+		roots = append(roots, statedb.GetStorageRoot(addr).Bytes()...)
+		roots = append(roots, statedb.GetCodeHash(addr).Bytes()...)
+	}
+	storage := sysConfigStorage(crypto.Keccak256Hash(roots))
+	if cached, ok := bc.sysConfigs.Get(storage); ok {
+		bc.sysConfigs.Add(hash, cached)
+		return cached.(*common.SystemConfig)
+	}
+	call := func(contractAddr common.Address, funcName string, funcParams []interface{}) ([]byte, error) {
+		return innerCallAt(bc, parent, statedb, contractAddr, common.GenCallData(funcName, funcParams))
+	}
+	config := common.NewSystemConfig()
+	loadParamConfig(call, config)
+	loadNodeConfig(call, config)
+
+	bc.sysConfigs.Add(storage, config)
+	bc.sysConfigs.Add(hash, config)
+	return config
+}
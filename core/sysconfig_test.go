@@ -0,0 +1,85 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/common/syscontracts"
+	"github.com/PlatONEnetwork/PlatONE-Go/consensus"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/rawdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/state"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/vm"
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/params"
+)
+
+// sysConfigEngine only tells the authors of the blocks, which is all the
+// system contract calls need.
+type sysConfigEngine struct {
+	consensus.Engine
+}
+
+func (sysConfigEngine) Author(header *types.Header) (common.Address, error) {
+	return header.Coinbase, nil
+}
+
+// Tests that the system configuration of a block is resolved from its parent,
+// shared with the previous block until the storage of a system contract
+// changes, whichever transaction changed it.
+func TestSystemConfigVersions(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	config := &params.ChainConfig{ChainID: big.NewInt(1)}
+	genesis := (&Genesis{Config: config}).MustCommit(db)
+	bc, _, err := NewBlockChain(db, nil, nil, config, sysConfigEngine{}, vm.Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer bc.Stop()
+
+	// Block 2 changes the parameter manager through a CNS invocation, block 1
+	// keeps the genesis state
+	statedb, _ := state.New(genesis.Root(), state.NewDatabase(db))
+	statedb.SetState(syscontracts.ParameterManagementAddress, []byte("txGasLimit"), []byte{0x01})
+	root, err := statedb.Commit(true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := statedb.Database().TrieDB().Commit(root, false); err != nil {
+		t.Fatal(err)
+	}
+	parent := genesis
+	for i, step := range []struct {
+		to   common.Address
+		root common.Hash
+	}{{common.Address{0x01}, genesis.Root()}, {syscontracts.CnsInvokeAddress, root}} {
+		tx := types.NewTransaction(uint64(i), step.to, new(big.Int), 0, new(big.Int), nil, types.NormalTxType)
+		block := types.NewBlock(&types.Header{Number: big.NewInt(int64(i + 1)), ParentHash: parent.Hash(), Root: step.root}, types.Transactions{tx}, nil)
+		rawdb.WriteBlock(db, block)
+		rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
+		parent = block
+	}
+
+	first := bc.SystemConfigByNumber(1)
+	if first == nil || first == common.SysCfg {
+		t.Fatalf("configuration of block 1 not resolved")
+	}
+	if second := bc.SystemConfigByNumber(2); second != first {
+		t.Fatalf("block 2 has a new configuration without system contract changes")
+	}
+	if third := bc.SystemConfigByNumber(3); third == first || third == nil {
+		t.Fatalf("block 3 shares the configuration set before the system contract changed")
+	}
+	if at := bc.SystemConfigAt(parent.Header()); at != bc.SystemConfigByNumber(2) {
+		t.Fatalf("configuration of block 2 by header differs from the one by number")
+	}
+
+	orphan := &types.Header{Number: big.NewInt(2), ParentHash: common.Hash{0xff}}
+	if bc.SystemConfigAt(orphan) != nil {
+		t.Fatalf("configuration resolved without the parent")
+	}
+	if systemConfigAt(bc, orphan) != common.SysCfg {
+		t.Fatalf("configuration without the parent is not the latest one")
+	}
+}
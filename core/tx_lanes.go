@@ -0,0 +1,225 @@
+package core
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/common/syscontracts"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/metrics"
+)
+
+// TxLane is a priority class of the transactions packed into a block. Lanes
+// are filled in order, so a lane only gets the room left by the ones before
+// it. The pending transactions of a sender all go to the lane of the first
+// one, so they are packed in order.
+type TxLane int
+
+const (
+	// SystemLane holds calls of the system contracts, firewall and migration
+	// transactions, and all transactions of the administrator accounts.
+	SystemLane TxLane = iota
+	// OrganizationLane holds the transactions of the members of a configured
+	// organization, each organization packing at most its quota per block.
+	OrganizationLane
+	// BestEffortLane holds the remaining transactions.
+	BestEffortLane
+
+	numTxLanes
+)
+
+var txLaneNames = [numTxLanes]string{"system", "organization", "besteffort"}
+
+func (l TxLane) String() string {
+	return txLaneNames[l]
+}
+
+// TxOrganization is a group of accounts sharing a packing quota.
+type TxOrganization struct {
+	Name    string
+	Members []common.Address
+	Quota   uint64 // Maximum number of transactions of the members per block, 0 for no limit
+}
+
+var (
+	// Metrics of the transactions waiting in and packed from each lane
+	lanePendingGauges    [numTxLanes]metrics.Gauge
+	lanePackedCounters   [numTxLanes]metrics.Counter
+	laneDeferredCounters [numTxLanes]metrics.Counter
+)
+
+func init() {
+	for l, name := range txLaneNames {
+		lanePendingGauges[l] = metrics.NewRegisteredGauge("txpool/lane/"+name+"/pending", nil)
+		lanePackedCounters[l] = metrics.NewRegisteredCounter("txpool/lane/"+name+"/packed", nil)
+		laneDeferredCounters[l] = metrics.NewRegisteredCounter("txpool/lane/"+name+"/deferred", nil)
+	}
+}
+
+// txLanes sorts the pending transactions into lanes and picks the ones to
+// pack. Within a lane the senders take turns, one transaction each, so an
+// account loading a large batch cannot starve the others.
+type txLanes struct {
+	admins    map[common.Address]struct{}
+	orgs      map[common.Address]int // organization index of the members
+	quotas    []uint64
+	senderMax uint64 // Maximum number of transactions of one sender per block outside the system lane
+	round     int    // Rotates the sender taking the first turn
+}
+
+func newTxLanes(config *TxPoolConfig) *txLanes {
+	l := &txLanes{
+		admins:    make(map[common.Address]struct{}),
+		orgs:      make(map[common.Address]int),
+		senderMax: config.AccountTxCount,
+	}
+	for _, addr := range config.Admins {
+		l.admins[addr] = struct{}{}
+	}
+	for i, org := range config.Organizations {
+		for _, addr := range org.Members {
+			l.orgs[addr] = i
+		}
+		l.quotas = append(l.quotas, org.Quota)
+	}
+	return l
+}
+
+// senderLane returns the lane of the pending transactions txs of from, the lane
+// of the first one. Splitting them across lanes would let a later transaction
+// be packed before an earlier one.
+func (l *txLanes) senderLane(from common.Address, txs types.Transactions) TxLane {
+	return l.lane(from, txs[0])
+}
+
+// lane returns the lane of a transaction sent by from.
+func (l *txLanes) lane(from common.Address, tx *types.Transaction) TxLane {
+	if _, ok := l.admins[from]; ok {
+		return SystemLane
+	}
+	if tx.Type() == types.FwTxType || tx.Type() == types.MigTxType || isSystemContract(tx.To()) {
+		return SystemLane
+	}
+	if _, ok := l.orgs[from]; ok {
+		return OrganizationLane
+	}
+	return BestEffortLane
+}
+
+// isSystemContract reports whether to is the address of a system contract.
+func isSystemContract(to *common.Address) bool {
+	if to == nil {
+		return false
+	}
+	switch *to {
+	case syscontracts.UserManagementAddress, syscontracts.NodeManagementAddress,
+		syscontracts.CnsManagementAddress, syscontracts.ParameterManagementAddress,
+		syscontracts.FirewallManagementAddress, syscontracts.GroupManagementAddress,
+		syscontracts.ContractDataProcessorAddress, syscontracts.FeeSponsorManagementAddress:
+		return true
+	}
+	return false
+}
+
+// laneQueue is the transactions of one sender in a lane.
+type laneQueue struct {
+	from common.Address
+	txs  types.Transactions
+}
+
+// share returns how many of the first pending transactions of each sender
+// outside the system lane, given the number each has, pick may select when
+// limit are selected in turns. One more than the turns all senders get covers
+// those served first. The senders of the system lane are not capped, pick may
+// select up to limit of their transactions.
+func (l *txLanes) share(lengths map[common.Address]int, limit int) int {
+	sizes := make([]int, 0, len(lengths))
+	for _, n := range lengths {
+		if l.senderMax > 0 && uint64(n) > l.senderMax {
+			n = int(l.senderMax)
+		}
+		sizes = append(sizes, n)
+	}
+	sort.Ints(sizes)
+
+	left := limit
+	for i, n := range sizes {
+		senders := len(sizes) - i
+		if n*senders >= left {
+			return left/senders + 1
+		}
+		left -= n
+	}
+	return limit
+}
+
+// pick selects at most limit of the pending transactions, grouped by lane and
+// sender in the order of the pending lists.
+func (l *txLanes) pick(pending map[common.Address]types.Transactions, limit int) [numTxLanes]map[common.Address]types.Transactions {
+	senders := make([]common.Address, 0, len(pending))
+	for addr := range pending {
+		senders = append(senders, addr)
+	}
+	sort.Slice(senders, func(i, j int) bool { return bytes.Compare(senders[i][:], senders[j][:]) < 0 })
+	if len(senders) > 0 {
+		start := l.round % len(senders)
+		senders = append(senders[start:], senders[:start]...)
+	}
+	l.round++
+
+	var queues [numTxLanes][]*laneQueue
+	for _, addr := range senders {
+		if txs := pending[addr]; len(txs) > 0 {
+			lane := l.senderLane(addr, txs)
+			queues[lane] = append(queues[lane], &laneQueue{from: addr, txs: txs})
+		}
+	}
+
+	var (
+		picked   [numTxLanes]map[common.Address]types.Transactions
+		sent     = make(map[common.Address]uint64)
+		orgCount = make([]uint64, len(l.quotas))
+		count    int
+	)
+	for lane := SystemLane; lane < numTxLanes; lane++ {
+		picked[lane] = make(map[common.Address]types.Transactions)
+		waiting := 0
+		for _, q := range queues[lane] {
+			waiting += len(q.txs)
+		}
+		lanePendingGauges[lane].Update(int64(waiting))
+
+		packed := 0
+		for active := queues[lane]; len(active) > 0 && count < limit; {
+			next := active[:0]
+			for _, q := range active {
+				if count >= limit {
+					break
+				}
+				if lane != SystemLane && l.senderMax > 0 && sent[q.from] >= l.senderMax {
+					continue
+				}
+				org, member := l.orgs[q.from]
+				if lane == OrganizationLane && member && l.quotas[org] > 0 && orgCount[org] >= l.quotas[org] {
+					continue
+				}
+				picked[lane][q.from] = append(picked[lane][q.from], q.txs[0])
+				q.txs = q.txs[1:]
+				sent[q.from]++
+				if lane == OrganizationLane && member {
+					orgCount[org]++
+				}
+				count++
+				packed++
+				if len(q.txs) > 0 {
+					next = append(next, q)
+				}
+			}
+			active = next
+		}
+		lanePackedCounters[lane].Inc(int64(packed))
+		laneDeferredCounters[lane].Inc(int64(waiting - packed))
+	}
+	return picked
+}
@@ -0,0 +1,165 @@
+package core
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/common/syscontracts"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+)
+
+func laneTxs(n int, to common.Address) types.Transactions {
+	txs := make(types.Transactions, n)
+	for i := range txs {
+		txs[i] = types.NewTransaction(uint64(i), to, big.NewInt(0), 100000, big.NewInt(0), nil, types.NormalTxType)
+	}
+	return txs
+}
+
+func laneCount(lane map[common.Address]types.Transactions, addr common.Address) int {
+	return len(lane[addr])
+}
+
+// Tests that a sender loading a batch only gets the room the other senders of
+// its lane leave.
+func TestTxLanesFairness(t *testing.T) {
+	batch, alice, bob := common.Address{0x01}, common.Address{0x02}, common.Address{0x03}
+	to := common.Address{0xff}
+	pending := map[common.Address]types.Transactions{
+		batch: laneTxs(100, to),
+		alice: laneTxs(2, to),
+		bob:   laneTxs(3, to),
+	}
+	lanes := newTxLanes(&TxPoolConfig{}).pick(pending, 10)
+	best := lanes[BestEffortLane]
+	if laneCount(best, alice) != 2 || laneCount(best, bob) != 3 || laneCount(best, batch) != 5 {
+		t.Fatalf("picked batch %d, alice %d, bob %d", laneCount(best, batch), laneCount(best, alice), laneCount(best, bob))
+	}
+	// the transactions of a sender keep their order
+	for i, tx := range best[batch] {
+		if tx.Nonce() != uint64(i) {
+			t.Fatalf("transaction %d has nonce %d", i, tx.Nonce())
+		}
+	}
+
+	// the sender cap holds even with room left in the block
+	lanes = newTxLanes(&TxPoolConfig{AccountTxCount: 2}).pick(pending, 10)
+	if n := laneCount(lanes[BestEffortLane], batch); n != 2 {
+		t.Fatalf("picked %d transactions of the batch sender, want 2", n)
+	}
+}
+
+// Tests that the share of the pending transactions copied for picking holds
+// all the ones picked, without copying the whole backlog of a sender.
+func TestTxLanesShare(t *testing.T) {
+	batch, alice, bob := common.Address{0x01}, common.Address{0x02}, common.Address{0x03}
+	lengths := map[common.Address]int{batch: 100000, alice: 2, bob: 3}
+
+	l := newTxLanes(&TxPoolConfig{})
+	if share := l.share(lengths, 10); share < 5 || share > 10 {
+		t.Fatalf("share %d, want between 5 and 10", share)
+	}
+	// everything fits
+	if share := l.share(map[common.Address]int{alice: 2, bob: 3}, 10); share < 3 {
+		t.Fatalf("share %d, want at least 3", share)
+	}
+	// the picked transactions are the same as from the whole backlog
+	to := common.Address{0xff}
+	pending := map[common.Address]types.Transactions{
+		batch: laneTxs(1000, to)[:l.share(lengths, 10)],
+		alice: laneTxs(2, to),
+		bob:   laneTxs(3, to),
+	}
+	if n := laneCount(l.pick(pending, 10)[BestEffortLane], batch); n != 5 {
+		t.Fatalf("picked %d transactions of the batch sender, want 5", n)
+	}
+}
+
+// Tests that the lanes are filled by priority and that organizations stay
+// within their quota.
+func TestTxLanesPriority(t *testing.T) {
+	admin, member, other, user := common.Address{0x01}, common.Address{0x02}, common.Address{0x03}, common.Address{0x04}
+	to := common.Address{0xff}
+	config := &TxPoolConfig{
+		Admins:        []common.Address{admin},
+		Organizations: []TxOrganization{{Name: "org", Members: []common.Address{member, other}, Quota: 3}},
+	}
+	pending := map[common.Address]types.Transactions{
+		admin:  laneTxs(2, to),
+		member: laneTxs(5, to),
+		other:  laneTxs(5, to),
+		user:   append(laneTxs(4, to), laneTxs(1, syscontracts.ParameterManagementAddress)...),
+	}
+	lanes := newTxLanes(config).pick(pending, 7)
+
+	system := lanes[SystemLane]
+	if laneCount(system, admin) != 2 || laneCount(system, user) != 0 {
+		t.Fatalf("system lane picked admin %d, user %d", laneCount(system, admin), laneCount(system, user))
+	}
+	org := lanes[OrganizationLane]
+	if laneCount(org, member)+laneCount(org, other) != 3 || laneCount(org, member) == 0 || laneCount(org, other) == 0 {
+		t.Fatalf("organization lane picked member %d, other %d", laneCount(org, member), laneCount(org, other))
+	}
+	// the best effort lane gets what is left of the limit
+	if n := laneCount(lanes[BestEffortLane], user); n != 2 {
+		t.Fatalf("best effort lane picked %d transactions, want 2", n)
+	}
+}
+
+// Tests that the transactions of a sender stay in the lane of its first one,
+// in order, and that the system lane is not capped per sender.
+func TestTxLanesSenderOrder(t *testing.T) {
+	caller, user := common.Address{0x01}, common.Address{0x02}
+	to := common.Address{0xff}
+	pending := map[common.Address]types.Transactions{
+		caller: append(laneTxs(1, syscontracts.ParameterManagementAddress), laneTxs(4, to)...),
+		user:   append(laneTxs(2, to), laneTxs(1, syscontracts.ParameterManagementAddress)...),
+	}
+	l := newTxLanes(&TxPoolConfig{AccountTxCount: 2})
+	lanes := l.pick(pending, 10)
+
+	if n := laneCount(lanes[SystemLane], caller); n != 5 {
+		t.Fatalf("system lane picked %d transactions of the caller, want 5", n)
+	}
+	if laneCount(lanes[SystemLane], user) != 0 || laneCount(lanes[BestEffortLane], user) != 2 {
+		t.Fatalf("user transactions split across lanes")
+	}
+	for _, lane := range lanes {
+		for addr, txs := range lane {
+			if !reflect.DeepEqual(txs, pending[addr][:len(txs)]) {
+				t.Fatalf("transactions of %x out of order", addr)
+			}
+		}
+	}
+}
+
+// Tests that the pool copies the transactions of the system lane senders for
+// picking regardless of the share of the others.
+func TestTxLanesPendingSystem(t *testing.T) {
+	caller := common.Address{0x01}
+	to := common.Address{0xff}
+	config := TxPoolConfig{AccountTxCount: 2, GlobalTxCount: 6}
+	pool := &TxPool{config: config, lanes: newTxLanes(&config), pending: make(map[common.Address]*txQueuedMap)}
+
+	senders := map[common.Address]types.Transactions{
+		caller:               append(laneTxs(1, syscontracts.ParameterManagementAddress), laneTxs(4, to)...),
+		common.Address{0x02}: laneTxs(5, to),
+		common.Address{0x03}: laneTxs(5, to),
+		common.Address{0x04}: laneTxs(5, to),
+	}
+	for addr, txs := range senders {
+		pool.pending[addr] = newTxQueuedMap()
+		for _, tx := range txs {
+			pool.pending[addr].Put(tx.Hash(), tx)
+		}
+	}
+	lanes, err := pool.PendingLanes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := laneCount(lanes[SystemLane], caller); n != 5 {
+		t.Fatalf("system lane picked %d transactions of the caller, want 5", n)
+	}
+}
@@ -0,0 +1,200 @@
+package core
+
+import (
+	"container/list"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/common/hexutil"
+	"github.com/PlatONEnetwork/PlatONE-Go/event"
+	"github.com/PlatONEnetwork/PlatONE-Go/log"
+)
+
+// txTrackerQueueLimit is the maximum number of events waiting for slow
+// subscribers, the oldest ones are dropped beyond it.
+const txTrackerQueueLimit = 16384
+
+// TxStage is a step in the lifecycle of a transaction.
+type TxStage uint
+
+const (
+	TxStageReceived  TxStage = iota // Submitted to or received by the pool
+	TxStageValidated                // Accepted into the pool
+	TxStageRejected                 // Refused by the pool
+	TxStageDropped                  // Removed from the pool without being packed
+	TxStagePacked                   // Included in a block of the chain
+	TxStageExecuted                 // Executed in that block
+	TxStageFailed                   // Left out of a block being packed for failing
+)
+
+var txStageNames = []string{"received", "validated", "rejected", "dropped", "packed", "executed", "failed"}
+
+func (s TxStage) String() string {
+	if int(s) < len(txStageNames) {
+		return txStageNames[s]
+	}
+	return fmt.Sprintf("TxStage(%d)", uint(s))
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s TxStage) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *TxStage) UnmarshalText(input []byte) error {
+	for i, name := range txStageNames {
+		if name == string(input) {
+			*s = TxStage(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown transaction stage %q", input)
+}
+
+// TxEvent is a step a transaction went through, with its outcome.
+type TxEvent struct {
+	Hash        common.Hash     `json:"hash"`
+	Stage       TxStage         `json:"stage"`
+	Time        hexutil.Uint64  `json:"time"`                  // Milliseconds since the epoch
+	Reason      string          `json:"reason,omitempty"`      // Why the transaction was rejected, dropped or failed
+	BlockNumber *hexutil.Uint64 `json:"blockNumber,omitempty"` // Block the transaction was packed in
+	Status      *hexutil.Uint64 `json:"status,omitempty"`      // Receipt status of the execution
+}
+
+// txTracker keeps the lifecycle of the transactions seen by the pool until
+// they are left alone for longer than a window.
+type txTracker struct {
+	window  time.Duration
+	records map[common.Hash]*list.Element // Elements of order, by transaction
+	order   *list.List                    // Lifecycles from the least to the most recently updated
+	feed    event.Feed
+	mu      sync.Mutex
+
+	queue   []TxEvent     // Events recorded but not sent yet, in order
+	queueMu sync.Mutex    // Protects queue, separately from the records
+	wake    chan struct{} // Signals the sending loop of queued events
+	quit    chan struct{}
+	wg      sync.WaitGroup
+}
+
+type txRecord struct {
+	updated time.Time
+	events  []TxEvent
+}
+
+// newTxTracker creates a tracker keeping lifecycles for window, sending their
+// events until stop.
+func newTxTracker(window time.Duration) *txTracker {
+	t := &txTracker{
+		window:  window,
+		records: make(map[common.Hash]*list.Element),
+		order:   list.New(),
+		wake:    make(chan struct{}, 1),
+		quit:    make(chan struct{}),
+	}
+	t.wg.Add(1)
+	go t.loop()
+	return t
+}
+
+// stop stops sending events to the subscribers.
+func (t *txTracker) stop() {
+	close(t.quit)
+	t.wg.Wait()
+}
+
+// loop sends the queued events to the subscribers in the order they were
+// recorded, a slow subscriber delaying the events after them.
+func (t *txTracker) loop() {
+	defer t.wg.Done()
+	for {
+		select {
+		case <-t.wake:
+		case <-t.quit:
+			return
+		}
+		t.queueMu.Lock()
+		events := t.queue
+		t.queue = nil
+		t.queueMu.Unlock()
+
+		if len(events) > 0 {
+			t.feed.Send(TxLifecycleEvent{events})
+		}
+	}
+}
+
+// record appends events to the lifecycles of their transactions and notifies
+// the subscribers.
+func (t *txTracker) record(events ...TxEvent) {
+	if len(events) == 0 {
+		return
+	}
+	now := time.Now()
+	t.mu.Lock()
+	for i := range events {
+		events[i].Time = hexutil.Uint64(now.UnixNano() / 1e6)
+
+		elem, ok := t.records[events[i].Hash]
+		if !ok {
+			elem = t.order.PushBack(&txRecord{})
+			t.records[events[i].Hash] = elem
+		} else {
+			t.order.MoveToBack(elem)
+		}
+		rec := elem.Value.(*txRecord)
+		rec.updated = now
+		rec.events = append(rec.events, events[i])
+	}
+	for elem := t.order.Front(); elem != nil && now.Sub(elem.Value.(*txRecord).updated) > t.window; elem = t.order.Front() {
+		delete(t.records, elem.Value.(*txRecord).events[0].Hash)
+		t.order.Remove(elem)
+	}
+	// Queue while holding the records, for the events to be sent in order
+	t.queueMu.Lock()
+	t.queue = append(t.queue, events...)
+	if dropped := len(t.queue) - txTrackerQueueLimit; dropped > 0 {
+		log.Warn("Dropping transaction lifecycle events of slow subscribers", "count", dropped)
+		t.queue = append([]TxEvent(nil), t.queue[dropped:]...)
+	}
+	t.queueMu.Unlock()
+	t.mu.Unlock()
+
+	select {
+	case t.wake <- struct{}{}:
+	default:
+	}
+}
+
+// lifecycle returns the events of the transaction with the given hash, nil if
+// it is unknown or was forgotten.
+func (t *txTracker) lifecycle(hash common.Hash) []TxEvent {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	elem, ok := t.records[hash]
+	if !ok {
+		return nil
+	}
+	events := elem.Value.(*txRecord).events
+	return append(make([]TxEvent, 0, len(events)), events...)
+}
+
+// rejectedEvent returns the event of a transaction refused with err.
+func rejectedEvent(hash common.Hash, err error) TxEvent {
+	return TxEvent{Hash: hash, Stage: TxStageRejected, Reason: err.Error()}
+}
+
+// failedEvent returns the event of a transaction left out of a block being
+// packed for failing with err.
+func failedEvent(hash common.Hash, err error) TxEvent {
+	return TxEvent{Hash: hash, Stage: TxStageFailed, Reason: err.Error()}
+}
+
+// droppedEvent returns the event of a transaction removed for reason.
+func droppedEvent(hash common.Hash, reason string) TxEvent {
+	return TxEvent{Hash: hash, Stage: TxStageDropped, Reason: reason}
+}
@@ -0,0 +1,64 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+)
+
+// Tests that the tracker keeps the steps of transactions in order, notifies
+// them and forgets the transactions left alone for longer than its window.
+func TestTxTracker(t *testing.T) {
+	tracker := newTxTracker(50 * time.Millisecond)
+	defer tracker.stop()
+	events := make(chan TxLifecycleEvent, 4)
+	sub := tracker.feed.Subscribe(events)
+	defer sub.Unsubscribe()
+
+	a, b := common.Hash{0x01}, common.Hash{0x02}
+	tracker.record(TxEvent{Hash: a, Stage: TxStageReceived}, TxEvent{Hash: b, Stage: TxStageReceived})
+	tracker.record(rejectedEvent(b, errors.New("invalid sender")))
+	var sent []TxEvent
+	for len(sent) < 3 {
+		select {
+		case ev := <-events:
+			sent = append(sent, ev.Events...)
+		case <-time.After(time.Second):
+			t.Fatalf("notification %d not sent", len(sent))
+		}
+	}
+	// the events are sent in the order they were recorded
+	if sent[0].Hash != a || sent[1].Hash != b || sent[2].Stage != TxStageRejected {
+		t.Fatalf("events sent out of order: %+v", sent)
+	}
+	if lifecycle := tracker.lifecycle(b); len(lifecycle) != 2 || lifecycle[1].Stage != TxStageRejected || lifecycle[1].Reason != "invalid sender" {
+		t.Fatalf("lifecycle of b = %+v", lifecycle)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	tracker.record(TxEvent{Hash: a, Stage: TxStageValidated})
+	time.Sleep(30 * time.Millisecond)
+	tracker.record(TxEvent{Hash: common.Hash{0x03}, Stage: TxStageReceived})
+	if lifecycle := tracker.lifecycle(b); lifecycle != nil {
+		t.Fatalf("lifecycle of b kept after the window: %+v", lifecycle)
+	}
+	lifecycle := tracker.lifecycle(a)
+	if len(lifecycle) != 2 || lifecycle[0].Stage != TxStageReceived || lifecycle[1].Stage != TxStageValidated {
+		t.Fatalf("lifecycle of a = %+v", lifecycle)
+	}
+
+	enc, err := json.Marshal(lifecycle[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dec TxEvent
+	if err := json.Unmarshal(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if dec != lifecycle[1] {
+		t.Fatalf("decoded %s as %+v", enc, dec)
+	}
+}
@@ -86,7 +86,11 @@ func (m *txQueuedMap) GetByCount(max int) (types.Transactions, int) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 	count := 0
-	txs := make(types.Transactions, 0, m.size+1)
+	size := m.size
+	if max < size {
+		size = max
+	}
+	txs := make(types.Transactions, 0, size+1)
 
 	for e := m.data.Front(); e != nil && count < max; e = e.Next() {
 		if tx, ok := e.Value.(*types.Transaction); ok {
@@ -17,21 +17,22 @@
 package core
 
 import (
-	"crypto/ecdsa"
 	"errors"
 	"fmt"
 	"math"
 	"math/big"
-	"strconv"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/common/hexutil"
 	"github.com/PlatONEnetwork/PlatONE-Go/common/prque"
+	"github.com/PlatONEnetwork/PlatONE-Go/common/syscontracts"
 	"github.com/PlatONEnetwork/PlatONE-Go/core/rawdb"
 	"github.com/PlatONEnetwork/PlatONE-Go/core/state"
 	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/vm"
 	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
 	"github.com/PlatONEnetwork/PlatONE-Go/event"
 	"github.com/PlatONEnetwork/PlatONE-Go/log"
@@ -144,6 +145,7 @@ type TxPoolConfig struct {
 	NoLocals  bool             // Whether local transaction handling should be disabled
 	Journal   string           // Journal of local transactions to survive node restarts
 	Rejournal time.Duration    // Time interval to regenerate the local transaction journal
+	Persist   bool             // Whether to persist all pending transactions in the node database
 
 	PriceLimit uint64 // Minimum gas price to enforce for acceptance into the pool
 	PriceBump  uint64 // Minimum price bump percentage to replace an already existing transaction (nonce)
@@ -154,7 +156,12 @@ type TxPoolConfig struct {
 	GlobalQueue   uint64 // Maximum number of non-executable transaction slots for all accounts
 	GlobalTxCount uint64 // Maximum number of transactions for package
 
-	Lifetime time.Duration // Maximum amount of time non-executable transaction are queued
+	Admins         []common.Address // Senders packed in the system lane, ahead of all other transactions
+	Organizations  []TxOrganization // Accounts packed in the organization lane, within the quota of their organization
+	AccountTxCount uint64           // Maximum number of transactions of one sender for package outside the system lane, 0 for no limit
+
+	Lifetime  time.Duration // Maximum amount of time non-executable transaction are queued
+	Lifecycle time.Duration // Time to keep the lifecycle of transactions after their last event, 0 to not track them
 }
 
 // DefaultTxPoolConfig contains the default configurations for the transaction
@@ -220,10 +227,14 @@ type TxPool struct {
 	currentState  *state.StateDB      // Current state in the blockchain head
 	pendingState  *state.ManagedState // Pending state tracking virtual nonces
 	db            ethdb.Database
-	currentMaxGas uint64 // Current gas limit for transaction caps
+	currentMaxGas uint64               // Current gas limit for transaction caps
+	sysConfig     *common.SystemConfig // System parameters in force for the pending block
 
 	locals  *accountSet // Set of local transaction to exempt from eviction rules
 	journal *txJournal  // Journal of local transaction to back up to disk
+	store   *txStore    // Store of all pending transactions in the node database
+	tracker *txTracker  // Lifecycle of the transactions seen by the pool
+	lanes   *txLanes    // Priority lanes of the transactions to package
 
 	pending map[common.Address]*txQueuedMap // All currently processable transactions
 	//queue   map[common.Address]*txQueuedMap    // Queued but non-processable transactions
@@ -238,7 +249,6 @@ type TxPool struct {
 	resetHead   *types.Block
 	txch        chan struct{}
 	completeCnt int32
-	pk          *ecdsa.PrivateKey
 }
 
 type txExt struct {
@@ -250,7 +260,7 @@ type txExt struct {
 // NewTxPool creates a new transaction pool to gather, sort and filter inbound
 // transactions from the network.
 //func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain blockChain) *TxPool {
-func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain txPoolBlockChain, db ethdb.Database, extDb ethdb.Database, key *ecdsa.PrivateKey) *TxPool {
+func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain txPoolBlockChain, db ethdb.Database, extDb ethdb.Database) *TxPool {
 	// Sanitize the input to ensure no vulnerable gas prices are set
 	config = (&config).sanitize()
 
@@ -273,13 +283,17 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain txPoo
 		txExtBuffer:      make(chan *txExt, txExtBufferSize),
 		txch:             make(chan struct{}, config.GlobalSlots),
 		completeCnt:      0,
-		pk:               key,
+		sysConfig:        common.SysCfg,
 	}
 	pool.locals = newAccountSet(pool.signer)
 	for _, addr := range config.Locals {
 		log.Info("Setting new local account", "address", addr)
 		pool.locals.add(addr)
 	}
+	pool.lanes = newTxLanes(&config)
+	if config.Lifecycle > 0 {
+		pool.tracker = newTxTracker(config.Lifecycle)
+	}
 	//pool.priced = newTxPricedList(pool.all)
 	pool.reset(nil, chain.CurrentBlock())
 
@@ -296,6 +310,11 @@ func NewTxPool(config TxPoolConfig, chainconfig *params.ChainConfig, chain txPoo
 			log.Warn("Failed to rotate transaction journal", "err", err)
 		}
 	}
+	// If persistence is enabled, restore the transactions pending before the restart
+	if config.Persist {
+		pool.store = newTxStore(db)
+		pool.store.load(pool.AddRemotes)
+	}
 	// Subscribe events from blockchain
 	// modified by PlatONE
 	if pool.chainconfig.Istanbul != nil {
@@ -546,6 +565,7 @@ func (pool *TxPool) reset(oldBlock, newBlock *types.Block) {
 	pool.currentState = statedb
 	pool.pendingState = state.ManageState(statedb)
 	pool.currentMaxGas = newHead.GasLimit
+	pool.sysConfig = systemConfigAt(pool.chain, &types.Header{Number: new(big.Int).Add(newHead.Number, common.Big1), ParentHash: newHead.Hash()})
 
 	if len(reinject) != 0 {
 		// Inject any transactions discarded due to reorgs
@@ -559,6 +579,13 @@ func (pool *TxPool) reset(oldBlock, newBlock *types.Block) {
 	// higher gas price)
 	txs := newBlock.Transactions()
 	pool.demoteUnexecutables(txs)
+	pool.removeExpired(newHead.Number.Uint64()+1, uint64(time.Now().UnixNano()/1e6))
+	if pool.tracker != nil {
+		pool.trackBlocks(oldHead, newBlock)
+	}
+	if pool.store != nil {
+		pool.store.prune(pool.all)
+	}
 
 	// Check the queue and move transactions over to the pending if possible
 	// or remove those that have become invalid
@@ -576,6 +603,9 @@ func (pool *TxPool) Stop() {
 
 	pool.wg.Wait()
 
+	if pool.tracker != nil {
+		pool.tracker.stop()
+	}
 	if pool.journal != nil {
 		pool.journal.close()
 	}
@@ -588,6 +618,74 @@ func (pool *TxPool) SubscribeNewTxsEvent(ch chan<- NewTxsEvent) event.Subscripti
 	return pool.scope.Track(pool.txFeed.Subscribe(ch))
 }
 
+// SubscribeTxLifecycleEvent registers a subscription of TxLifecycleEvent and
+// starts sending event to the given channel. Nothing is sent if lifecycle
+// tracking is disabled.
+func (pool *TxPool) SubscribeTxLifecycleEvent(ch chan<- TxLifecycleEvent) event.Subscription {
+	if pool.tracker == nil {
+		return pool.scope.Track(new(event.Feed).Subscribe(ch))
+	}
+	return pool.scope.Track(pool.tracker.feed.Subscribe(ch))
+}
+
+// Lifecycle returns the events of the transaction with the given hash, from
+// its arrival in the pool to its execution, nil if it is not tracked.
+func (pool *TxPool) Lifecycle(hash common.Hash) []TxEvent {
+	if pool.tracker == nil {
+		return nil
+	}
+	return pool.tracker.lifecycle(hash)
+}
+
+// track records lifecycle events if tracking is enabled.
+func (pool *TxPool) track(events ...TxEvent) {
+	if pool.tracker != nil {
+		pool.tracker.record(events...)
+	}
+}
+
+// TrackFailed records that the transaction with the given hash failed with err
+// while packed in a block, which it was left out of.
+func (pool *TxPool) TrackFailed(hash common.Hash, err error) {
+	pool.track(failedEvent(hash, err))
+}
+
+// trackBlocks records the packing and the execution of the transactions of the
+// blocks of the chain after oldHead up to newBlock, at most 64 of them.
+func (pool *TxPool) trackBlocks(oldHead *types.Header, newBlock *types.Block) {
+	blocks := []*types.Block{newBlock}
+	if oldHead != nil {
+		for block := newBlock; block.NumberU64() > oldHead.Number.Uint64()+1 && len(blocks) < 64; {
+			if block = pool.chain.GetBlock(block.ParentHash(), block.NumberU64()-1); block == nil {
+				break
+			}
+			blocks = append(blocks, block)
+		}
+	}
+	for i := len(blocks) - 1; i >= 0; i-- {
+		pool.trackBlock(blocks[i])
+	}
+}
+
+// trackBlock records the packing and the execution of the transactions of a
+// block added to the chain.
+func (pool *TxPool) trackBlock(block *types.Block) {
+	var (
+		txs      = block.Transactions()
+		receipts = rawdb.ReadReceipts(pool.db, block.Hash(), block.NumberU64())
+		number   = hexutil.Uint64(block.NumberU64())
+		events   = make([]TxEvent, 0, 2*len(txs))
+	)
+	for i, tx := range txs {
+		events = append(events, TxEvent{Hash: tx.Hash(), Stage: TxStagePacked, BlockNumber: &number})
+		if i < len(receipts) {
+			status := hexutil.Uint64(receipts[i].Status)
+			events = append(events, TxEvent{Hash: tx.Hash(), Stage: TxStageExecuted, BlockNumber: &number, Status: &status, Reason: receipts[i].RevertReason})
+		}
+	}
+	pool.track(events...)
+}
+
 // GasPrice returns the current gas price enforced by the transaction pool.
 func (pool *TxPool) GasPrice() *big.Int {
 	pool.mu.RLock()
@@ -679,27 +777,70 @@ func (pool *TxPool) Pending() (map[common.Address]types.Transactions, error) {
 // grouped by origin account and stored by nonce. The returned transaction set
 // is a copy and can be freely modified by calling code.
 func (pool *TxPool) PendingLimited() (map[common.Address]types.Transactions, error) {
+	lanes, err := pool.PendingLanes()
+	if err != nil {
+		return nil, err
+	}
+	pending := make(map[common.Address]types.Transactions)
+	for _, lane := range lanes {
+		for addr, txs := range lane {
+			pending[addr] = append(pending[addr], txs...)
+		}
+	}
+	return pending, nil
+}
+
+// PendingLanes retrieves `pool.config.GlobalTxCount` processable transactions
+// like PendingLimited, grouped by lane in the order they should be packaged.
+// Senders take turns within a lane, so the transactions of a single account
+// only fill the room the others leave.
+func (pool *TxPool) PendingLanes() ([]map[common.Address]types.Transactions, error) {
 	now := time.Now()
 	pool.mu.Lock()
 	defer pool.mu.Unlock()
 
-	//log.Info("Pending txs before get", "txCnt", len(pool.pending))
-	txCount := 0
-	var length int
-	pending := make(map[common.Address]types.Transactions)
+	// Only the transactions the senders can get by taking turns are copied,
+	// the senders of the system lane not being capped
+	var (
+		limit   = int(pool.config.GlobalTxCount)
+		lengths = make(map[common.Address]int, len(pool.pending))
+		system  []common.Address
+	)
 	for addr, list := range pool.pending {
-		if list != nil {
-			if list.Len() > 0 {
-				pending[addr], length = list.GetByCount(int(pool.config.GlobalTxCount) - txCount)
-				txCount += length
-				if txCount >= int(pool.config.GlobalTxCount) {
-					break
-				}
-			}
+		if list == nil || list.Len() == 0 {
+			continue
+		}
+		if first, _ := list.GetByCount(1); pool.lanes.senderLane(addr, first) == SystemLane {
+			system = append(system, addr)
+			continue
+		}
+		lengths[addr] = list.Len()
+	}
+	share := pool.lanes.share(lengths, limit)
+
+	pending := make(map[common.Address]types.Transactions, len(lengths)+len(system))
+	for addr := range lengths {
+		if txs, _ := pool.pending[addr].GetByCount(share); len(txs) > 0 {
+			pending[addr] = txs
+		}
+	}
+	for _, addr := range system {
+		if txs, _ := pool.pending[addr].GetByCount(limit); len(txs) > 0 {
+			pending[addr] = txs
+		}
+	}
+	picked := pool.lanes.pick(pending, limit)
+
+	txCount := 0
+	lanes := make([]map[common.Address]types.Transactions, len(picked))
+	for i, lane := range picked {
+		lanes[i] = lane
+		for _, txs := range lane {
+			txCount += len(txs)
 		}
 	}
 	log.Info("Get pending txs", "duration", time.Since(now), "txCnt", txCount)
-	return pending, nil
+	return lanes, nil
 }
 
 // Locals retrieves the accounts currently considered local by the pool.
@@ -729,6 +870,13 @@ func (pool *TxPool) local() map[common.Address]types.Transactions {
 // validateTx checks whether a transaction is valid according to the consensus
 // rules and adheres to some heuristic limits of the local node (price and size).
 func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
+	// An expired transaction can never be included again, so the replay check
+	// below only consults the transaction index while the window is open.
+	if w := tx.ValidityWindow(); w != nil {
+		if w.Expired(pool.pendingPosition()) {
+			return ErrTxExpired
+		}
+	}
 	if ok, _ := rawdb.HasTransaction(pool.db, tx.Hash()); ok {
 		log.Error("Transaction Repeat", "hash", tx.Hash().String())
 		return ErrTransactionRepeat
@@ -744,12 +892,26 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	if tx.Value().Sign() < 0 {
 		return ErrNegativeValue
 	}
+	// Batch transactions must carry their calls to the batch address
+	if tx.Type() == types.BatchTxType {
+		if tx.Value().Sign() != 0 || tx.To() == nil || *tx.To() != syscontracts.BatchInvokeAddress {
+			return ErrInvalidBatch
+		}
+		if _, err := types.DecodeBatchCalls(tx.Data()); err != nil {
+			return ErrInvalidBatch
+		}
+	}
 	// Make sure the transaction is signed properly
 
 	_, err := types.Sender(pool.signer, tx)
 	if err != nil {
 		return ErrInvalidSender
 	}
+	// A sponsor must have signed the transaction it pays for
+	payer, err := types.FeePayerSender(pool.signer, tx)
+	if err != nil {
+		return types.ErrInvalidFeePayer
+	}
 
 	// Drop non-local transactions under our own minimal accepted gas price
 	//local = local || pool.locals.contains(from) // account may be local even if the transaction arrived from the network
@@ -757,7 +919,7 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 	// Transactor should have enough funds to cover the costs
 	// cost == V + GP * GL
 
-	if !isCallParamManager(tx.To()) && common.SysCfg.GetIsTxUseGas() && common.SysCfg.GetGasContractName() != "" {
+	if !isCallParamManager(tx.To()) && pool.sysConfig.GetIsTxUseGas() && pool.sysConfig.GetGasContractName() != "" {
 		contractCreation := tx.To() == nil
 		gas, err := IntrinsicGas(tx.Data(), contractCreation)
 		log.Debug("IntrinsicGas amount", "IntrinsicGas:", gas)
@@ -768,6 +930,12 @@ func (pool *TxPool) validateTx(tx *types.Transaction, local bool) error {
 			log.Error("GasLimitTooLow", "err:", ErrIntrinsicGas)
 			return ErrIntrinsicGas
 		}
+		if payer != nil {
+			_, timestamp := pool.pendingPosition()
+			if err := vm.CheckFeeSponsorship(pool.currentState, *payer, feeTargets(tx.Type(), tx.To(), tx.Data()), tx.Gas(), timestamp); err != nil {
+				return err
+			}
+		}
 
 	}
 
@@ -809,6 +977,7 @@ func (pool *TxPool) add(tx *types.Transaction, local bool) (bool, error) {
 		}
 	}
 	pool.journalTx(from, tx)
+	pool.persistTx(tx)
 
 	//log.Trace("Pooled new future transaction", "hash", hash, "from", from, "to", tx.To())
 	return false, nil
@@ -849,6 +1018,14 @@ func (pool *TxPool) journalTx(from common.Address, tx *types.Transaction) {
 	}
 }
 
+// persistTx adds the specified transaction to the pool store if persistence
+// is enabled.
+func (pool *TxPool) persistTx(tx *types.Transaction) {
+	if pool.store != nil {
+		pool.store.insert(tx)
+	}
+}
+
 // promoteTx adds a transaction to the pending (processable) list of transactions
 // and returns whether it was inserted or an older was better.
 //
@@ -974,6 +1151,7 @@ func (pool *TxPool) recoverTx(tx *types.Transaction, from common.Address, local
 		}
 	}
 	pool.journalTx(from, tx)
+	pool.persistTx(tx)
 	pool.promoteTx(from, hash, tx)
 
 	return true
@@ -1011,9 +1189,15 @@ func (pool *TxPool) addTxExt(txExt *txExt) interface{} {
 	defer pool.mu.Unlock()
 
 	errs := make([]error, len(txExt.txs))
+	events := make([]TxEvent, len(txExt.txs))
 	for i, tx := range txExt.txs {
-		_, errs[i] = pool.add(tx, txExt.local)
+		if _, errs[i] = pool.add(tx, txExt.local); errs[i] != nil {
+			events[i] = rejectedEvent(tx.Hash(), errs[i])
+		} else {
+			events[i] = TxEvent{Hash: tx.Hash(), Stage: TxStageValidated}
+		}
 	}
+	pool.track(events...)
 	return errs
 }
 
@@ -1022,13 +1206,15 @@ func (pool *TxPool) addTxs(txs []*types.Transaction, local bool) []error {
 
 	// Filter out known ones without obtaining the pool lock or recovering signatures
 	var (
-		errs = make([]error, len(txs))
-		news = make([]*types.Transaction, 0, len(txs))
+		errs   = make([]error, len(txs))
+		news   = make([]*types.Transaction, 0, len(txs))
+		events = make([]TxEvent, 0, len(txs))
 	)
 	//atomic.AddInt32(&pool.processCnt,1)
 	for i, tx := range txs {
 		if uint64(pool.all.Count()) >= pool.config.GlobalSlots {
 			errs[i] = errors.New("txpool is full")
+			events = append(events, TxEvent{Hash: tx.Hash(), Stage: TxStageReceived}, rejectedEvent(tx.Hash(), errs[i]))
 			continue
 		}
 		// If the transaction is known, pre-set the error slot
@@ -1041,11 +1227,17 @@ func (pool *TxPool) addTxs(txs []*types.Transaction, local bool) []error {
 		// obtaining lock
 		if err := pool.validateTx(tx, local); err != nil {
 			errs[i] = err
+			// Transactions already in the chain keep their lifecycle
+			if err != ErrTransactionRepeat {
+				events = append(events, TxEvent{Hash: tx.Hash(), Stage: TxStageReceived}, rejectedEvent(tx.Hash(), err))
+			}
 			continue
 		}
 		// Accumulate all unknown transactions for deeper processing
 		news = append(news, tx)
+		events = append(events, TxEvent{Hash: tx.Hash(), Stage: TxStageReceived})
 	}
+	pool.track(events...)
 	if len(news) == 0 {
 		return errs
 	}
@@ -1193,6 +1385,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 							hash := tx.Hash()
 							pool.all.Remove(hash)
 							list.Remove(hash)
+							pool.track(droppedEvent(hash, "evicted by pool limits"))
 
 							log.Trace("Removed fairness-exceeding pending transaction", "hash", hash)
 							pending--
@@ -1216,6 +1409,7 @@ func (pool *TxPool) promoteExecutables(accounts []common.Address) {
 						hash := tx.Hash()
 						pool.all.Remove(hash)
 						list.Remove(hash)
+						pool.track(droppedEvent(hash, "evicted by pool limits"))
 						pending--
 					}
 				}
@@ -1248,6 +1442,7 @@ func (pool *TxPool) demoteUnexecutables(txs types.Transactions) {
 				log.Trace("Removed unpayable queued transaction", "hash", hash)
 				list.Remove(hash)
 				pool.all.Remove(hash)
+				pool.track(droppedEvent(hash, ErrInsufficientFunds.Error()))
 			}
 		}
 
@@ -1258,6 +1453,26 @@ func (pool *TxPool) demoteUnexecutables(txs types.Transactions) {
 	//log.Info("remove pending  -----------------------------", "duration", time.Since(now))
 }
 
+// pendingPosition returns the number and an estimate of the timestamp of the
+// next block.
+func (pool *TxPool) pendingPosition() (number, timestamp uint64) {
+	return pool.chain.CurrentBlock().NumberU64() + 1, uint64(time.Now().UnixNano() / 1e6)
+}
+
+// removeExpired drops the transactions whose validity window closed before a
+// block with the given number and timestamp.
+func (pool *TxPool) removeExpired(number, timestamp uint64) {
+	for _, list := range pool.pending {
+		for _, tx := range list.Get() {
+			if w := tx.ValidityWindow(); w != nil && w.Expired(number, timestamp) {
+				log.Trace("Removed expired transaction", "hash", tx.Hash())
+				pool.removeTx(tx.Hash(), false)
+				pool.track(droppedEvent(tx.Hash(), ErrTxExpired.Error()))
+			}
+		}
+	}
+}
+
 func (pool *TxPool) GetResetNumber() *big.Int {
 	pool.mu.RLock()
 	defer pool.mu.RUnlock()
@@ -1416,117 +1631,3 @@ func (t *txLookup) RemoveTxs(txs types.Transactions) {
 		delete(t.all, hash)
 	}
 }
-
-func (pool *TxPool) GenerateTxs(benchmark *types.Benchmark) {
-	pool.generateTxs(benchmark.Count, common.HexToAddress(benchmark.To), benchmark.PreGenerate, benchmark.ProducerCnt)
-}
-
-func (pool *TxPool) generateTxs(cnt string, addr common.Address, preProducer bool, pThreadCnt string) {
-	count, _ := strconv.Atoi(cnt)
-	var producerThread = 5
-	if len(pThreadCnt) != 0 {
-		producerThread, _ = strconv.Atoi(pThreadCnt)
-	}
-	if count < 1000 {
-		producerThread = 1
-	}
-	var perCnt = count / producerThread
-	txsCh := make(chan *types.Transaction, 500)
-	if preProducer {
-		txsCh = make(chan *types.Transaction, count)
-	}
-	var producerCnt int32 = 0
-	producer := func(threadNum, cnt int) {
-		var gasLimit = 1 + threadNum
-		for i := 0; i < cnt; i++ {
-			nonce := time.Now().UnixNano()
-			tx := types.NewTransaction(uint64(nonce), addr, big.NewInt(1), uint64(gasLimit), big.NewInt(1), nil, 0)
-			signedTx, _ := types.SignTx(tx, types.HomesteadSigner{}, pool.pk)
-			types.Sender(pool.signer, signedTx) // already validated
-			tx.Hash()
-			txsCh <- signedTx
-			atomic.AddInt32(&producerCnt, 1)
-		}
-	}
-	for i := 0; i < producerThread; i++ {
-		go producer(i, perCnt)
-	}
-	tt := time.NewTicker(500 * time.Millisecond)
-	stopWait := make(chan struct{}, 1)
-	var batch types.Transactions
-	var insertCnt = 0
-	go func() {
-		var pre int32 = 0
-		var preInsert int
-		for {
-			select {
-			case <-tt.C:
-				tmp := producerCnt - pre
-				if tmp == 0 {
-					stopWait <- struct{}{}
-					break
-				}
-				tmpInsert := insertCnt
-				log.Info("producer transaction count : ", "count", tmp, "addCount", tmpInsert-preInsert)
-				preInsert = tmpInsert
-				pre = producerCnt
-			}
-		}
-	}()
-	var errCnt = 0
-	addtx := func(txs types.Transactions) {
-		pool.mu.Lock()
-		defer pool.mu.Unlock()
-		for _, tx := range batch {
-			hash := tx.Hash()
-
-			from, _ := types.Sender(pool.signer, tx) // already validated
-
-			if !pool.promoteTx(from, hash, tx) {
-				errCnt++
-			}
-			insertCnt++
-		}
-	}
-
-	checkTxPool := func() {
-		if pool.GetTxCount() > int(pool.config.GlobalTxCount)*2 {
-			time.Sleep(50 * time.Millisecond)
-		}
-	}
-
-	if preProducer {
-		var sec = count / 5000
-		wait := time.NewTimer(time.Second * time.Duration(sec))
-		select {
-		case <-wait.C:
-		case <-stopWait:
-			time.Sleep(1 * time.Second)
-		}
-	}
-	receive := 0
-	consumer := func() {
-		consumerTicker := time.NewTicker(500 * time.Millisecond)
-		for {
-			select {
-			case tx := <-txsCh:
-				receive++
-				if batch = append(batch, tx); batch.Len() > 500 {
-					checkTxPool()
-					addtx(batch)
-					batch = batch[:0]
-				}
-			case <-consumerTicker.C:
-				if int(atomic.LoadInt32(&producerCnt)) == count {
-					if insertCnt == count {
-						log.Info("************************************", "errCnt", errCnt)
-						return
-					}
-					addtx(batch)
-				}
-			}
-		}
-	}
-
-	go consumer()
-}
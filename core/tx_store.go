@@ -0,0 +1,113 @@
+package core
+
+import (
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/rawdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/log"
+)
+
+// txStore persists every transaction accepted into the pool in the node
+// database, numbered by arrival, so that pending transactions received from
+// the network survive node restarts too.
+type txStore struct {
+	db          ethdb.Database
+	first, next uint64                 // Sequence numbers of the oldest and of the next transaction
+	seqs        map[common.Hash]uint64 // Sequence numbers of the persisted transactions
+}
+
+// newTxStore creates a transaction store over the persisted transactions of db.
+func newTxStore(db ethdb.Database) *txStore {
+	first, next := rawdb.ReadPoolTxRange(db)
+	return &txStore{
+		db:    db,
+		first: first,
+		next:  next,
+		seqs:  make(map[common.Hash]uint64),
+	}
+}
+
+// load injects the persisted transactions into the pool in their arrival
+// order, dropping the ones already included in the chain or rejected by add.
+func (store *txStore) load(add func([]*types.Transaction) []error) {
+	var (
+		txs             types.Transactions
+		seqs            []uint64
+		included, total int
+	)
+	for seq := store.first; seq < store.next; seq++ {
+		tx := rawdb.ReadPoolTransaction(store.db, seq)
+		if tx == nil {
+			continue
+		}
+		total++
+		if ok, _ := rawdb.HasTransaction(store.db, tx.Hash()); ok {
+			rawdb.DeletePoolTransaction(store.db, seq)
+			included++
+			continue
+		}
+		// Registered before adding, so the pool doesn't persist it twice
+		store.seqs[tx.Hash()] = seq
+		txs = append(txs, tx)
+		seqs = append(seqs, seq)
+	}
+	dropped := 0
+	for i, err := range add(txs) {
+		if err != nil {
+			log.Debug("Failed to add persisted transaction", "hash", txs[i].Hash(), "err", err)
+			store.remove(txs[i].Hash())
+			dropped++
+		}
+	}
+	store.compact()
+	log.Info("Loaded persisted pool transactions", "transactions", total, "included", included, "dropped", dropped)
+}
+
+// insert persists tx after all the transactions that arrived before it.
+func (store *txStore) insert(tx *types.Transaction) {
+	hash := tx.Hash()
+	if _, ok := store.seqs[hash]; ok {
+		return
+	}
+	store.seqs[hash] = store.next
+	rawdb.WritePoolTransaction(store.db, store.next, tx)
+	store.next++
+	rawdb.WritePoolTxRange(store.db, store.first, store.next)
+}
+
+// remove deletes the persisted transaction with the given hash, if any.
+func (store *txStore) remove(hash common.Hash) {
+	seq, ok := store.seqs[hash]
+	if !ok {
+		return
+	}
+	delete(store.seqs, hash)
+	rawdb.DeletePoolTransaction(store.db, seq)
+}
+
+// prune deletes the persisted transactions that left the pool, either included
+// in a block or dropped.
+func (store *txStore) prune(all *txLookup) {
+	for hash := range store.seqs {
+		if all.Get(hash) == nil {
+			store.remove(hash)
+		}
+	}
+	store.compact()
+}
+
+// compact moves the start of the persisted range to the oldest transaction
+// still in the store, so loading doesn't scan deleted ones.
+func (store *txStore) compact() {
+	first := store.next
+	for _, seq := range store.seqs {
+		if seq < first {
+			first = seq
+		}
+	}
+	if first != store.first {
+		store.first = first
+		rawdb.WritePoolTxRange(store.db, store.first, store.next)
+	}
+}
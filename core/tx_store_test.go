@@ -0,0 +1,75 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/rawdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
+)
+
+// Tests that persisted transactions are restored in their arrival order,
+// without the ones included in the chain or removed from the pool.
+func TestTxStorePersistence(t *testing.T) {
+	db := ethdb.NewMemDatabase()
+	txs := make(types.Transactions, 6)
+	for i := range txs {
+		txs[i] = types.NewTransaction(uint64(i), common.Address{byte(i)}, big.NewInt(0), 21000, big.NewInt(1), nil, types.NormalTxType)
+	}
+	store := newTxStore(db)
+	for _, tx := range txs {
+		store.insert(tx)
+	}
+	store.insert(txs[0]) // known transactions keep their position
+
+	// Drop the first transaction from the pool and include the last two in a block
+	all := newTxLookup()
+	for _, tx := range txs[1:] {
+		all.Add(tx)
+	}
+	store.prune(all)
+	block := types.NewBlock(&types.Header{Number: big.NewInt(1)}, txs[4:], nil)
+	rawdb.WriteTxLookupEntries(db, block)
+
+	var loaded types.Transactions
+	check := func(want ...*types.Transaction) {
+		if len(loaded) != len(want) {
+			t.Fatalf("loaded %d transactions, want %d", len(loaded), len(want))
+		}
+		for i := range want {
+			if loaded[i].Hash() != want[i].Hash() {
+				t.Fatalf("transaction %d is %x, want %x", i, loaded[i].Hash(), want[i].Hash())
+			}
+		}
+	}
+	reject := txs[2].Hash()
+	store = newTxStore(db)
+	store.load(func(txs []*types.Transaction) []error {
+		errs := make([]error, len(txs))
+		for i, tx := range txs {
+			if tx.Hash() == reject {
+				errs[i] = errors.New("rejected")
+				continue
+			}
+			loaded = append(loaded, tx)
+		}
+		return errs
+	})
+	check(txs[1], txs[3])
+	if first, next := rawdb.ReadPoolTxRange(db); first != 1 || next != 6 {
+		t.Fatalf("range [%d, %d), want [1, 6)", first, next)
+	}
+
+	// Only the accepted transactions are left for the next restart
+	store.insert(txs[0])
+	store = newTxStore(db)
+	loaded = loaded[:0]
+	store.load(func(txs []*types.Transaction) []error {
+		loaded = append(loaded, txs...)
+		return make([]error, len(txs))
+	})
+	check(txs[1], txs[3], txs[0])
+}
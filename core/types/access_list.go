@@ -0,0 +1,48 @@
+package types
+
+import (
+	"bytes"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/common/hexutil"
+)
+
+// AccessTuple is an account a transaction declares it accesses, with the
+// prefixes of the storage keys it reads or writes in it. A tuple without
+// prefixes covers all the storage of the account.
+type AccessTuple struct {
+	Address         common.Address  `json:"address"`
+	StoragePrefixes []hexutil.Bytes `json:"storagePrefixes"`
+}
+
+// AccessList declares the accounts and storage a transaction accesses. The
+// block producer schedules transactions with disjoint lists in parallel, and
+// the state transition fails a transaction accessing anything else.
+type AccessList []AccessTuple
+
+// Addresses returns the declared accounts.
+func (al AccessList) Addresses() []common.Address {
+	addrs := make([]common.Address, len(al))
+	for i, tuple := range al {
+		addrs[i] = tuple.Address
+	}
+	return addrs
+}
+
+// Covers reports whether the list declares the storage key of addr.
+func (al AccessList) Covers(addr common.Address, key []byte) bool {
+	for _, tuple := range al {
+		if tuple.Address != addr {
+			continue
+		}
+		if len(tuple.StoragePrefixes) == 0 {
+			return true
+		}
+		for _, prefix := range tuple.StoragePrefixes {
+			if bytes.HasPrefix(key, prefix) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,63 @@
+package types
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/common/syscontracts"
+	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
+)
+
+var ErrEmptyBatch = errors.New("batch without calls")
+
+// BatchCall is one of the calls of a batch transaction.
+type BatchCall struct {
+	To    *common.Address `rlp:"nil"` // nil deploys Data as a contract
+	Value *big.Int
+	Data  []byte
+}
+
+// NewBatchTransaction creates a transaction executing calls in order, all of
+// them or none.
+func NewBatchTransaction(nonce uint64, calls []BatchCall, gasLimit uint64, gasPrice *big.Int) (*Transaction, error) {
+	payload, err := EncodeBatchCalls(calls)
+	if err != nil {
+		return nil, err
+	}
+	return NewTransaction(nonce, syscontracts.BatchInvokeAddress, new(big.Int), gasLimit, gasPrice, payload, BatchTxType), nil
+}
+
+// EncodeBatchCalls encodes calls as the payload of a batch transaction.
+func EncodeBatchCalls(calls []BatchCall) ([]byte, error) {
+	if len(calls) == 0 {
+		return nil, ErrEmptyBatch
+	}
+	return rlp.EncodeToBytes(calls)
+}
+
+// DecodeBatchCalls decodes the calls of a batch transaction from its payload.
+func DecodeBatchCalls(payload []byte) ([]BatchCall, error) {
+	var calls []BatchCall
+	if err := rlp.DecodeBytes(payload, &calls); err != nil {
+		return nil, err
+	}
+	if len(calls) == 0 {
+		return nil, ErrEmptyBatch
+	}
+	for i := range calls {
+		if calls[i].Value == nil {
+			calls[i].Value = new(big.Int)
+		}
+	}
+	return calls, nil
+}
+
+// CallResult is the outcome of one call of a batch transaction. The logs of
+// the calls follow each other in the logs of the receipt.
+type CallResult struct {
+	Status          uint64         `json:"status"`
+	ContractAddress common.Address `json:"contractAddress"` // The contract deployed by the call, if any
+	RevertReason    string         `json:"revertReason,omitempty"`
+	Logs            uint64         `json:"logs"` // Number of logs of the call
+}
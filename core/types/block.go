@@ -214,15 +214,22 @@ type storageblock struct {
 //
 // The values of TxHash, ReceiptHash and Bloom in header
 // are ignored and set to values derived from the given txs
-// and receipts.
+// and receipts, the way the system configuration in force after
+// the head of the chain derives them.
 func NewBlock(header *Header, txs []*Transaction, receipts []*Receipt) *Block {
+	return NewBlockWith(header, txs, receipts, common.SysCfg.IsBlockUseTrieHash())
+}
+
+// NewBlockWith creates a new block like NewBlock, deriving TxHash and
+// ReceiptHash as tries or as flat hashes if useTrieHash is false.
+func NewBlockWith(header *Header, txs []*Transaction, receipts []*Receipt, useTrieHash bool) *Block {
 	b := &Block{header: CopyHeader(header)}
 
 	// TODO: panic if len(txs) != len(receipts)
 	if len(txs) == 0 {
 		b.header.TxHash = EmptyRootHash
 	} else {
-		b.header.TxHash = DeriveSha(Transactions(txs))
+		b.header.TxHash = DeriveShaWith(Transactions(txs), useTrieHash)
 		b.transactions = make(Transactions, len(txs))
 		copy(b.transactions, txs)
 	}
@@ -230,7 +237,7 @@ func NewBlock(header *Header, txs []*Transaction, receipts []*Receipt) *Block {
 	if len(receipts) == 0 {
 		b.header.ReceiptHash = EmptyRootHash
 	} else {
-		b.header.ReceiptHash = DeriveSha(Receipts(receipts))
+		b.header.ReceiptHash = DeriveShaWith(Receipts(receipts), useTrieHash)
 		b.header.Bloom = CreateBloom(receipts)
 	}
 
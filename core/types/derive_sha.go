@@ -29,11 +29,19 @@ type DerivableList interface {
 	GetHash() common.Hash
 }
 
+// DeriveSha computes the root of list the way the system configuration in
+// force after the head of the chain does.
 func DeriveSha(list DerivableList) common.Hash {
+	return DeriveShaWith(list, common.SysCfg.IsBlockUseTrieHash())
+}
+
+// DeriveShaWith computes the root of list as a trie, or as a flat hash of its
+// items if useTrieHash is false.
+func DeriveShaWith(list DerivableList, useTrieHash bool) common.Hash {
 	if list.Len() == 0 {
 		return new(trie.Trie).Hash()
 	}
-	if common.SysCfg.IsBlockUseTrieHash() {
+	if useTrieHash {
 		keybuf := new(bytes.Buffer)
 		trie := new(trie.Trie)
 
@@ -0,0 +1,118 @@
+package types
+
+import (
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"math/big"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/common/hexutil"
+	"github.com/PlatONEnetwork/PlatONE-Go/crypto"
+)
+
+var ErrInvalidFeePayer = errors.New("invalid fee payer signature")
+
+// feePayerDomain separates the hash signed by fee payers from the hashes
+// signed by senders.
+const feePayerDomain = "feePayer"
+
+// FeePayer is the sponsor paying the fees of a transaction instead of its
+// sender. The sender signs the address of the sponsor, the sponsor signs the
+// transaction signed by the sender.
+type FeePayer struct {
+	Address common.Address
+	V, R, S *big.Int
+}
+
+type feePayerJSON struct {
+	Address common.Address `json:"address"`
+	V       *hexutil.Big   `json:"v"`
+	R       *hexutil.Big   `json:"r"`
+	S       *hexutil.Big   `json:"s"`
+}
+
+// MarshalJSON marshals as JSON.
+func (p FeePayer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&feePayerJSON{p.Address, (*hexutil.Big)(p.V), (*hexutil.Big)(p.R), (*hexutil.Big)(p.S)})
+}
+
+// UnmarshalJSON unmarshals from JSON.
+func (p *FeePayer) UnmarshalJSON(input []byte) error {
+	var dec feePayerJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	p.Address, p.V, p.R, p.S = dec.Address, (*big.Int)(dec.V), (*big.Int)(dec.R), (*big.Int)(dec.S)
+	return nil
+}
+
+// FeePayerAddress returns the sponsor the sender declared to pay the fees of
+// the transaction, nil if the sender pays them.
+func (tx *Transaction) FeePayerAddress() *common.Address {
+	if tx.data.FeePayer == nil {
+		return nil
+	}
+	addr := tx.data.FeePayer.Address
+	return &addr
+}
+
+// WithFeePayer returns a copy of the unsigned transaction whose fees are paid
+// by the given sponsor, once it signed it with SignFeePayer.
+func (tx *Transaction) WithFeePayer(addr common.Address) *Transaction {
+	cpy := &Transaction{data: tx.data}
+	cpy.data.FeePayer = &FeePayer{Address: addr, V: new(big.Int), R: new(big.Int), S: new(big.Int)}
+	return cpy
+}
+
+// FeePayerHash returns the hash to be signed by the fee payer of tx.
+func FeePayerHash(s Signer, tx *Transaction) common.Hash {
+	return rlpHash([]interface{}{feePayerDomain, s.Hash(tx), tx.data.V, tx.data.R, tx.data.S})
+}
+
+// SignFeePayer returns a copy of tx signed by prv as its fee payer, which
+// must be the sponsor declared by the sender.
+func SignFeePayer(tx *Transaction, s Signer, prv *ecdsa.PrivateKey) (*Transaction, error) {
+	if tx.data.FeePayer == nil || tx.data.FeePayer.Address != crypto.PubkeyToAddress(prv.PublicKey) {
+		return nil, ErrInvalidFeePayer
+	}
+	h := FeePayerHash(s, tx)
+	sig, err := crypto.Sign(h[:], prv)
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithFeePayerSignature(sig)
+}
+
+// WithFeePayerSignature returns a copy of tx with the signature of its fee
+// payer, in the [R || S || V] format with V 0 or 1.
+func (tx *Transaction) WithFeePayerSignature(sig []byte) (*Transaction, error) {
+	if tx.data.FeePayer == nil || len(sig) != 65 {
+		return nil, ErrInvalidFeePayer
+	}
+	cpy := &Transaction{data: tx.data}
+	cpy.data.FeePayer = &FeePayer{
+		Address: tx.data.FeePayer.Address,
+		R:       new(big.Int).SetBytes(sig[:32]),
+		S:       new(big.Int).SetBytes(sig[32:64]),
+		V:       new(big.Int).SetUint64(uint64(sig[64]) + 27),
+	}
+	return cpy, nil
+}
+
+// FeePayerSender returns the sponsor paying the fees of tx after checking its
+// signature, nil if the sender pays them.
+func FeePayerSender(s Signer, tx *Transaction) (*common.Address, error) {
+	p := tx.data.FeePayer
+	if p == nil {
+		return nil, nil
+	}
+	if p.V == nil || p.R == nil || p.S == nil {
+		return nil, ErrInvalidFeePayer
+	}
+	addr, err := recoverPlain(FeePayerHash(s, tx), p.R, p.S, p.V, true)
+	if err != nil || addr != p.Address {
+		return nil, ErrInvalidFeePayer
+	}
+	return &addr, nil
+}
@@ -27,6 +27,9 @@ func (t txdata) MarshalJSON() ([]byte, error) {
 		R            *hexutil.Big    `json:"r" gencodec:"required"`
 		S            *hexutil.Big    `json:"s" gencodec:"required"`
 		Hash         *common.Hash    `json:"hash" rlp:"-"`
+		Window       *ValidityWindow `json:"validityWindow,omitempty"`
+		AccessList   AccessList      `json:"accessList,omitempty"`
+		FeePayer     *FeePayer       `json:"feePayer,omitempty"`
 	}
 	var enc txdata
 	enc.AccountNonce = hexutil.Uint64(t.AccountNonce)
@@ -40,6 +43,9 @@ func (t txdata) MarshalJSON() ([]byte, error) {
 	enc.R = (*hexutil.Big)(t.R)
 	enc.S = (*hexutil.Big)(t.S)
 	enc.Hash = t.Hash
+	enc.Window = t.Window
+	enc.AccessList = t.AccessList
+	enc.FeePayer = t.FeePayer
 	return json.Marshal(&enc)
 }
 
@@ -57,6 +63,9 @@ func (t *txdata) UnmarshalJSON(input []byte) error {
 		R            *hexutil.Big    `json:"r" gencodec:"required"`
 		S            *hexutil.Big    `json:"s" gencodec:"required"`
 		Hash         *common.Hash    `json:"hash" rlp:"-"`
+		Window       *ValidityWindow `json:"validityWindow,omitempty"`
+		AccessList   AccessList      `json:"accessList,omitempty"`
+		FeePayer     *FeePayer       `json:"feePayer,omitempty"`
 	}
 	var dec txdata
 	if err := json.Unmarshal(input, &dec); err != nil {
@@ -104,5 +113,14 @@ func (t *txdata) UnmarshalJSON(input []byte) error {
 	if dec.Hash != nil {
 		t.Hash = dec.Hash
 	}
+	if dec.Window != nil {
+		t.Window = dec.Window
+	}
+	if dec.AccessList != nil {
+		t.AccessList = dec.AccessList
+	}
+	if dec.FeePayer != nil {
+		t.FeePayer = dec.FeePayer
+	}
 	return nil
 }
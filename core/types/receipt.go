@@ -56,6 +56,12 @@ type Receipt struct {
 	TxHash          common.Hash    `json:"transactionHash" gencodec:"required"`
 	ContractAddress common.Address `json:"contractAddress"`
 	GasUsed         uint64         `json:"gasUsed" gencodec:"required"`
+	// RevertReason is why the transaction failed. It may be set for a
+	// successful transaction whose call to a system contract function failed.
+	RevertReason string `json:"revertReason,omitempty"`
+	// Calls are the outcomes of the calls of a batch transaction, up to the
+	// first failed one.
+	Calls []CallResult `json:"calls,omitempty"`
 }
 
 type receiptMarshaling struct {
@@ -81,6 +87,10 @@ type receiptStorageRLP struct {
 	ContractAddress   common.Address
 	Logs              []*LogForStorage
 	GasUsed           uint64
+	// Optional holds the revert reason and the batch call results, in the
+	// order they were introduced. Trailing empty ones are left out, so that
+	// the receipts stored before them decode.
+	Optional []rlp.RawValue `rlp:"tail"`
 }
 
 // NewReceipt creates a barebone transaction receipt, copying the init fields.
@@ -150,6 +160,20 @@ func (r *Receipt) Size() common.StorageSize {
 	return size
 }
 
+// CallLogs returns the logs of the call of a batch transaction with the given
+// index.
+func (r *Receipt) CallLogs(index int) []*Log {
+	var first uint64
+	for i := 0; i < index; i++ {
+		first += r.Calls[i].Logs
+	}
+	end := first + r.Calls[index].Logs
+	if end > uint64(len(r.Logs)) {
+		return nil
+	}
+	return r.Logs[first:end]
+}
+
 // ReceiptForStorage is a wrapper around a Receipt that flattens and parses the
 // entire content of a receipt, as opposed to only the consensus fields originally.
 type ReceiptForStorage Receipt
@@ -169,6 +193,20 @@ func (r *ReceiptForStorage) EncodeRLP(w io.Writer) error {
 	for i, log := range r.Logs {
 		enc.Logs[i] = (*LogForStorage)(log)
 	}
+	var optional []interface{}
+	if r.RevertReason != "" || len(r.Calls) > 0 {
+		optional = append(optional, r.RevertReason)
+	}
+	if len(r.Calls) > 0 {
+		optional = append(optional, r.Calls)
+	}
+	for _, field := range optional {
+		raw, err := rlp.EncodeToBytes(field)
+		if err != nil {
+			return err
+		}
+		enc.Optional = append(enc.Optional, raw)
+	}
 	return rlp.Encode(w, enc)
 }
 
@@ -190,6 +228,16 @@ func (r *ReceiptForStorage) DecodeRLP(s *rlp.Stream) error {
 	}
 	// Assign the implementation fields
 	r.TxHash, r.ContractAddress, r.GasUsed = dec.TxHash, dec.ContractAddress, dec.GasUsed
+	if len(dec.Optional) > 0 {
+		if err := rlp.DecodeBytes(dec.Optional[0], &r.RevertReason); err != nil {
+			return err
+		}
+	}
+	if len(dec.Optional) > 1 {
+		if err := rlp.DecodeBytes(dec.Optional[1], &r.Calls); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
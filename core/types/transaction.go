@@ -17,6 +17,7 @@
 package types
 
 import (
+	"bytes"
 	"container/heap"
 	"errors"
 	"github.com/PlatONEnetwork/PlatONE-Go/common"
@@ -48,6 +49,7 @@ const (
 	MigTxType uint64 = 0x13 //Used for update system contract.
 	MigDpType uint64 = 0x14 //Used for update system contract.
 
+	BatchTxType uint64 = 0x15 // Used for executing several calls atomically
 )
 
 type Transaction struct {
@@ -76,6 +78,116 @@ type txdata struct {
 
 	// This is only used when marshaling to JSON.
 	Hash *common.Hash `json:"hash" rlp:"-"`
+
+	// Optional fields, appended to the encoding when set, see txdataRLP.
+	Window     *ValidityWindow `json:"validityWindow,omitempty"`
+	AccessList AccessList      `json:"accessList,omitempty"`
+	FeePayer   *FeePayer       `json:"feePayer,omitempty"`
+}
+
+// txdataRLP is the encoding of txdata. The optional fields follow the
+// signature values in the order they were introduced, an absent one encoded
+// as an empty string. Trailing absent fields are left out, so transactions
+// without them encode as they did before the fields existed.
+type txdataRLP struct {
+	AccountNonce uint64
+	Price        *big.Int
+	GasLimit     uint64
+	Recipient    *common.Address `rlp:"nil"`
+	Amount       *big.Int
+	Payload      []byte
+	TxType       uint64
+	V, R, S      *big.Int
+	Optional     []rlp.RawValue `rlp:"tail"`
+}
+
+var errTxTooManyFields = errors.New("rlp: too many transaction fields")
+
+// optional returns the optional fields to encode. The fields signed by the
+// sender leave out the signature of the fee payer.
+func (t *txdata) optional(signed bool) []interface{} {
+	fields := []interface{}{[]byte{}, []byte{}, []byte{}}
+	n := 0
+	if t.Window != nil {
+		fields[0], n = t.Window, 1
+	}
+	if t.AccessList != nil {
+		fields[1], n = t.AccessList, 2
+	}
+	if t.FeePayer != nil {
+		fields[2], n = t.FeePayer, 3
+		if signed {
+			fields[2] = t.FeePayer.Address
+		}
+	}
+	return fields[:n]
+}
+
+// EncodeRLP implements rlp.Encoder
+func (t *txdata) EncodeRLP(w io.Writer) error {
+	enc := txdataRLP{
+		AccountNonce: t.AccountNonce,
+		Price:        t.Price,
+		GasLimit:     t.GasLimit,
+		Recipient:    t.Recipient,
+		Amount:       t.Amount,
+		Payload:      t.Payload,
+		TxType:       t.TxType,
+		V:            t.V,
+		R:            t.R,
+		S:            t.S,
+	}
+	for _, field := range t.optional(false) {
+		raw, err := rlp.EncodeToBytes(field)
+		if err != nil {
+			return err
+		}
+		enc.Optional = append(enc.Optional, raw)
+	}
+	return rlp.Encode(w, &enc)
+}
+
+// DecodeRLP implements rlp.Decoder
+func (t *txdata) DecodeRLP(s *rlp.Stream) error {
+	var dec txdataRLP
+	if err := s.Decode(&dec); err != nil {
+		return err
+	}
+	*t = txdata{
+		AccountNonce: dec.AccountNonce,
+		Price:        dec.Price,
+		GasLimit:     dec.GasLimit,
+		Recipient:    dec.Recipient,
+		Amount:       dec.Amount,
+		Payload:      dec.Payload,
+		TxType:       dec.TxType,
+		V:            dec.V,
+		R:            dec.R,
+		S:            dec.S,
+	}
+	if len(dec.Optional) > 3 {
+		return errTxTooManyFields
+	}
+	absent := func(raw rlp.RawValue) bool { return bytes.Equal(raw, []byte{0x80}) }
+	if len(dec.Optional) > 0 && !absent(dec.Optional[0]) {
+		t.Window = new(ValidityWindow)
+		if err := rlp.DecodeBytes(dec.Optional[0], t.Window); err != nil {
+			return err
+		}
+	}
+	if len(dec.Optional) > 1 && !absent(dec.Optional[1]) {
+		t.AccessList = AccessList{}
+		if err := rlp.DecodeBytes(dec.Optional[1], &t.AccessList); err != nil {
+			return err
+		}
+	}
+	if len(dec.Optional) > 2 && !absent(dec.Optional[2]) {
+		t.FeePayer = new(FeePayer)
+		if err := rlp.DecodeBytes(dec.Optional[2], t.FeePayer); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 type txdataMarshaling struct {
@@ -198,6 +310,39 @@ func (tx *Transaction) Value() *big.Int    { return new(big.Int).Set(tx.data.Amo
 func (tx *Transaction) Nonce() uint64      { return tx.data.AccountNonce }
 func (tx *Transaction) CheckNonce() bool   { return true }
 
+// ValidityWindow returns the blocks the transaction can be included in, nil
+// if it is valid in any block.
+func (tx *Transaction) ValidityWindow() *ValidityWindow {
+	if tx.data.Window == nil {
+		return nil
+	}
+	w := *tx.data.Window
+	return &w
+}
+
+// WithValidityWindow returns a copy of the unsigned transaction restricted
+// to the given window.
+func (tx *Transaction) WithValidityWindow(w ValidityWindow) *Transaction {
+	cpy := &Transaction{data: tx.data}
+	cpy.data.Window = &w
+	return cpy
+}
+
+// AccessList returns the accounts and storage the transaction declared it
+// accesses, nil if it declared nothing. An empty list limits it to the
+// accounts every transaction accesses.
+func (tx *Transaction) AccessList() AccessList {
+	return tx.data.AccessList
+}
+
+// WithAccessList returns a copy of the unsigned transaction limited to the
+// given accesses.
+func (tx *Transaction) WithAccessList(al AccessList) *Transaction {
+	cpy := &Transaction{data: tx.data}
+	cpy.data.AccessList = al
+	return cpy
+}
+
 // To returns the recipient address of the transaction.
 // It returns nil if the transaction is a contract creation.
 func (tx *Transaction) To() *common.Address {
@@ -246,10 +391,16 @@ func (tx *Transaction) AsMessage(s Signer) (*Message, error) {
 		data:       tx.data.Payload,
 		checkNonce: true,
 		txType:     tx.data.TxType,
+		window:     tx.ValidityWindow(),
+		accessList: tx.AccessList(),
 	}
 
 	var err error
 	msg.from, err = Sender(s, tx)
+	if err != nil {
+		return &msg, err
+	}
+	msg.feePayer, err = FeePayerSender(s, tx)
 	return &msg, err
 }
 
@@ -413,6 +564,17 @@ func (t *TransactionsByPriceAndNonce) Peek() *Transaction {
 	return t.heads[0]
 }
 
+// Heads returns at most n of the next transactions of the accounts, the best
+// one first.
+func (t *TransactionsByPriceAndNonce) Heads(n int) Transactions {
+	if n > len(t.heads) {
+		n = len(t.heads)
+	}
+	heads := make(Transactions, n)
+	copy(heads, t.heads[:n])
+	return heads
+}
+
 // Shift replaces the current best head with the next one from the same account.
 func (t *TransactionsByPriceAndNonce) Shift() {
 	acc, _ := Sender(t.signer, t.heads[0])
@@ -444,6 +606,9 @@ type Message struct {
 	data       []byte
 	checkNonce bool
 	txType     uint64
+	window     *ValidityWindow
+	accessList AccessList
+	feePayer   *common.Address
 }
 
 func NewMessage(from common.Address, to *common.Address, nonce uint64, amount *big.Int, gasLimit uint64, gasPrice *big.Int, data []byte, checkNonce bool, txType uint64) *Message {
@@ -472,6 +637,10 @@ func (m *Message) Data() []byte         { return m.data }
 func (m *Message) CheckNonce() bool     { return m.checkNonce }
 func (m *Message) TxType() uint64       { return m.txType }
 
+func (m *Message) ValidityWindow() *ValidityWindow { return m.window }
+func (m *Message) AccessList() AccessList          { return m.accessList }
+func (m *Message) FeePayer() *common.Address       { return m.feePayer }
+
 func (m *Message) SetTo(to common.Address) { m.to = &to }
 func (m *Message) SetData(b []byte)        { m.data = b }
 func (m *Message) SetTxType(src uint64)    { m.txType = src }
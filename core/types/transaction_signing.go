@@ -148,7 +148,7 @@ func (s EIP155Signer) SignatureValues(tx *Transaction, sig []byte) (R, S, V *big
 // Hash returns the hash to be signed by the sender.
 // It does not uniquely identify the transaction.
 func (s EIP155Signer) Hash(tx *Transaction) common.Hash {
-	return rlpHash([]interface{}{
+	return rlpHash(withOptional(tx, []interface{}{
 		tx.data.AccountNonce,
 		tx.data.Price,
 		tx.data.GasLimit,
@@ -157,7 +157,7 @@ func (s EIP155Signer) Hash(tx *Transaction) common.Hash {
 		tx.data.Payload,
 		tx.data.TxType,
 		s.chainId, uint(0), uint(0),
-	})
+	}))
 }
 
 func (s EIP155Signer) SignatureAndSender(tx *Transaction) (common.Address, []byte, error) {
@@ -217,7 +217,7 @@ func (fs FrontierSigner) SignatureValues(tx *Transaction, sig []byte) (r, s, v *
 // Hash returns the hash to be signed by the sender.
 // It does not uniquely identify the transaction.
 func (fs FrontierSigner) Hash(tx *Transaction) common.Hash {
-	return rlpHash([]interface{}{
+	return rlpHash(withOptional(tx, []interface{}{
 		tx.data.AccountNonce,
 		tx.data.Price,
 		tx.data.GasLimit,
@@ -225,7 +225,7 @@ func (fs FrontierSigner) Hash(tx *Transaction) common.Hash {
 		tx.data.Amount,
 		tx.data.Payload,
 		tx.data.TxType,
-	})
+	}))
 }
 
 func (fs FrontierSigner) Sender(tx *Transaction) (common.Address, error) {
@@ -303,3 +303,9 @@ func deriveChainId(v *big.Int) *big.Int {
 	v = new(big.Int).Sub(v, big.NewInt(35))
 	return v.Div(v, big.NewInt(2))
 }
+
+// withOptional appends the optional fields of tx that are set to the signed
+// fields, so that they cannot be changed without invalidating the signature.
+func withOptional(tx *Transaction, fields []interface{}) []interface{} {
+	return append(fields, tx.data.optional(true)...)
+}
@@ -24,6 +24,7 @@ import (
 	"testing"
 
 	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/common/hexutil"
 	"github.com/PlatONEnetwork/PlatONE-Go/crypto"
 	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
 )
@@ -220,3 +221,166 @@ func TestTransactionJSON(t *testing.T) {
 		}
 	}
 }
+
+func TestTransactionValidityWindow(t *testing.T) {
+	key, addr := defaultTestKey()
+	signer := NewEIP155Signer(common.Big1)
+	window := ValidityWindow{ValidFrom: 10, ValidUntil: 20}
+	tx, err := SignTx(emptyTx.WithValidityWindow(window), signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enc, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec, err := decodeTx(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if w := dec.ValidityWindow(); w == nil || *w != window {
+		t.Fatalf("decoded window = %v, want %v", w, window)
+	}
+	if from, err := Sender(signer, dec); err != nil || from != addr {
+		t.Fatalf("sender = %x, %v, want %x", from, err, addr)
+	}
+
+	// the window is signed
+	forged := &Transaction{data: dec.data}
+	forged.data.Window = &ValidityWindow{ValidUntil: 1000}
+	if from, _ := Sender(signer, forged); from == addr {
+		t.Fatal("window changed without invalidating the signature")
+	}
+
+	for _, tt := range []struct {
+		number               uint64
+		notYetValid, expired bool
+	}{
+		{9, true, false},
+		{10, false, false},
+		{20, false, false},
+		{21, false, true},
+	} {
+		if got := window.NotYetValid(tt.number, 0); got != tt.notYetValid {
+			t.Errorf("NotYetValid(%d) = %v", tt.number, got)
+		}
+		if got := window.Expired(tt.number, 0); got != tt.expired {
+			t.Errorf("Expired(%d) = %v", tt.number, got)
+		}
+	}
+}
+
+func TestTransactionAccessList(t *testing.T) {
+	key, addr := defaultTestKey()
+	signer := NewEIP155Signer(common.Big1)
+	list := AccessList{
+		{Address: common.HexToAddress("0x01"), StoragePrefixes: []hexutil.Bytes{{0xaa}}},
+		{Address: common.HexToAddress("0x02")},
+	}
+	tx, err := SignTx(emptyTx.WithAccessList(list), signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	enc, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec, err := decodeTx(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dec.ValidityWindow() != nil {
+		t.Fatalf("decoded window = %v, want none", dec.ValidityWindow())
+	}
+	if got := dec.AccessList(); len(got) != 2 || got[0].Address != list[0].Address || !bytes.Equal(got[0].StoragePrefixes[0], list[0].StoragePrefixes[0]) || got[1].Address != list[1].Address {
+		t.Fatalf("decoded access list = %v, want %v", got, list)
+	}
+	if from, err := Sender(signer, dec); err != nil || from != addr {
+		t.Fatalf("sender = %x, %v, want %x", from, err, addr)
+	}
+	if !list.Covers(list[0].Address, []byte{0xaa, 0x01}) || list.Covers(list[0].Address, []byte{0xab}) || !list.Covers(list[1].Address, []byte{0xff}) {
+		t.Fatal("wrong storage covered")
+	}
+
+	// the access list is signed
+	forged := &Transaction{data: dec.data}
+	forged.data.AccessList = list[:1]
+	if from, _ := Sender(signer, forged); from == addr {
+		t.Fatal("access list changed without invalidating the signature")
+	}
+
+	// an empty list is kept apart from no list
+	enc, _ = rlp.EncodeToBytes(emptyTx.WithAccessList(AccessList{}))
+	if dec, err = decodeTx(enc); err != nil {
+		t.Fatal(err)
+	}
+	if dec.AccessList() == nil {
+		t.Fatal("decoded no access list, want an empty one")
+	}
+
+	// transactions without optional fields encode as before
+	plain, err := rlp.EncodeToBytes(emptyTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy, err := rlp.EncodeToBytes([]interface{}{
+		emptyTx.data.AccountNonce, emptyTx.data.Price, emptyTx.data.GasLimit, emptyTx.data.Recipient,
+		emptyTx.data.Amount, emptyTx.data.Payload, emptyTx.data.TxType, emptyTx.data.V, emptyTx.data.R, emptyTx.data.S,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(plain, legacy) {
+		t.Fatalf("encoding = %x, want %x", plain, legacy)
+	}
+}
+
+func TestTransactionFeePayer(t *testing.T) {
+	key, addr := defaultTestKey()
+	payerKey, _ := crypto.GenerateKey()
+	payer := crypto.PubkeyToAddress(payerKey.PublicKey)
+	signer := NewEIP155Signer(common.Big1)
+
+	tx, err := SignTx(emptyTx.WithFeePayer(payer), signer, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FeePayerSender(signer, tx); err != ErrInvalidFeePayer {
+		t.Fatalf("unsigned fee payer: %v, want %v", err, ErrInvalidFeePayer)
+	}
+	if _, err := SignFeePayer(tx, signer, key); err != ErrInvalidFeePayer {
+		t.Fatalf("fee payer signed by the sender: %v, want %v", err, ErrInvalidFeePayer)
+	}
+	if tx, err = SignFeePayer(tx, signer, payerKey); err != nil {
+		t.Fatal(err)
+	}
+
+	enc, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec, err := decodeTx(enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from, err := Sender(signer, dec); err != nil || from != addr {
+		t.Fatalf("sender = %x, %v, want %x", from, err, addr)
+	}
+	if got, err := FeePayerSender(signer, dec); err != nil || got == nil || *got != payer {
+		t.Fatalf("fee payer = %v, %v, want %x", got, err, payer)
+	}
+
+	// the sender signs the sponsor, the sponsor signs the transaction
+	forged := &Transaction{data: dec.data}
+	forged.data.FeePayer = &FeePayer{Address: common.Address{0x01}, V: dec.data.FeePayer.V, R: dec.data.FeePayer.R, S: dec.data.FeePayer.S}
+	if from, _ := Sender(signer, forged); from == addr {
+		t.Fatal("fee payer changed without invalidating the signature of the sender")
+	}
+	forged = &Transaction{data: dec.data}
+	forged.data.Payload = []byte{0x01}
+	if _, err := FeePayerSender(signer, forged); err != ErrInvalidFeePayer {
+		t.Fatalf("payload changed: %v, want %v", err, ErrInvalidFeePayer)
+	}
+}
@@ -0,0 +1,65 @@
+package types
+
+import (
+	"encoding/json"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common/hexutil"
+)
+
+// ValidityWindow limits the blocks a transaction can be included in. The
+// bounds are block numbers, or block timestamps in milliseconds when ByTime
+// is set, and are both inclusive. A zero bound leaves that side open.
+//
+// A transaction with a closed window can never be replayed once it expired,
+// so the lookup of its hash in the transaction index is only needed while
+// the window is open.
+type ValidityWindow struct {
+	ByTime     bool
+	ValidFrom  uint64
+	ValidUntil uint64
+}
+
+type validityWindowJSON struct {
+	ByTime     bool           `json:"byTime"`
+	ValidFrom  hexutil.Uint64 `json:"validFrom"`
+	ValidUntil hexutil.Uint64 `json:"validUntil"`
+}
+
+// MarshalJSON encodes the bounds as hex numbers.
+func (w ValidityWindow) MarshalJSON() ([]byte, error) {
+	return json.Marshal(validityWindowJSON{w.ByTime, hexutil.Uint64(w.ValidFrom), hexutil.Uint64(w.ValidUntil)})
+}
+
+// UnmarshalJSON decodes the bounds from hex numbers.
+func (w *ValidityWindow) UnmarshalJSON(input []byte) error {
+	var dec validityWindowJSON
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	*w = ValidityWindow{dec.ByTime, uint64(dec.ValidFrom), uint64(dec.ValidUntil)}
+	return nil
+}
+
+// Bounded reports whether the window closes.
+func (w *ValidityWindow) Bounded() bool {
+	return w.ValidUntil != 0
+}
+
+func (w *ValidityWindow) position(number, time uint64) uint64 {
+	if w.ByTime {
+		return time
+	}
+	return number
+}
+
+// NotYetValid reports whether a block with the given number and timestamp
+// comes before the window.
+func (w *ValidityWindow) NotYetValid(number, time uint64) bool {
+	return w.position(number, time) < w.ValidFrom
+}
+
+// Expired reports whether a block with the given number and timestamp comes
+// after the window.
+func (w *ValidityWindow) Expired(number, time uint64) bool {
+	return w.Bounded() && w.position(number, time) > w.ValidUntil
+}
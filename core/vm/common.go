@@ -23,6 +23,7 @@ import (
 	"github.com/PlatONEnetwork/PlatONE-Go/accounts/abi"
 	"github.com/PlatONEnetwork/PlatONE-Go/common"
 	"github.com/PlatONEnetwork/PlatONE-Go/common/math"
+	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
 )
 
 // calculates the memory size required for a step
@@ -88,7 +89,7 @@ func parseSolCallWasmInput(evm *EVM, address common.Address, input []byte) ([]by
 	}
 
 	code := evm.StateDB.GetCode(address)
-	if ok, _, _, _ := common.IsWasmContractCode(code); !ok {
+	if ok, _, _, _ := common.IsWasmContractCode(code); !ok || !isJsonInput(input) {
 		return input, nil
 	}
 
@@ -102,3 +103,16 @@ func parseSolCallWasmInput(evm *EVM, address common.Address, input []byte) ([]by
 	}
 	return wasmInput, err
 }
+
+// isJsonInput reports whether input is a json call description, see
+// abi.WasmInput.
+func isJsonInput(input []byte) bool {
+	return len(input) > 0 && input[0] == '{'
+}
+
+// isRlpList reports whether input is an rlp list, as the calls of wasm
+// contracts are.
+func isRlpList(input []byte) bool {
+	kind, _, _, err := rlp.Split(input)
+	return err == nil && kind == rlp.List
+}
@@ -23,6 +23,8 @@ import (
 	"time"
 
 	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/common/syscontracts"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
 	"github.com/PlatONEnetwork/PlatONE-Go/crypto"
 	"github.com/PlatONEnetwork/PlatONE-Go/params"
 )
@@ -93,6 +95,9 @@ type Context struct {
 	GasLimit    uint64         // Provides information for GASLIMIT
 	BlockNumber *big.Int       // Provides information for NUMBER
 	Time        *big.Int       // Provides information for TIME
+
+	// System parameters in force for the block
+	SysConfig *common.SystemConfig
 }
 
 // EVM is the Ethereum Virtual Machine base object and provides
@@ -132,6 +137,10 @@ type EVM struct {
 	// available gas is calculated in gasCall* according to the 63/64 rule and later
 	// applied in opCall*.
 	callGasTemp uint64
+	// failure is why the transaction failed, see Failure.
+	failure string
+	// calls are the outcomes of the calls of a batch transaction, see Calls.
+	calls []types.CallResult
 }
 
 // NewEVM returns a new EVM. The returned EVM is not thread safe and should
@@ -194,7 +203,9 @@ func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, gas
 		snapshot = evm.StateDB.Snapshot() // - snapshot.
 	)
 	if !evm.StateDB.Exist(addr) {
-		if PrecompiledContracts[addr] == nil && value.Sign() == 0 {
+		// The fee sponsor contract came after the genesis of running chains,
+		// it runs before its account exists
+		if PrecompiledContracts[addr] == nil && addr != syscontracts.FeeSponsorManagementAddress && value.Sign() == 0 {
 			// Calling a non existing account, don't do anything, but ping the tracer
 			if evm.vmConfig.Debug && evm.depth == 0 {
 				evm.vmConfig.Tracer.CaptureStart(caller.Address(), addr, false, input, gas, value)
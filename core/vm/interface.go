@@ -70,6 +70,7 @@ type StateDB interface {
 	Snapshot() int
 
 	AddLog(*types.Log)
+	LogSize() uint
 	AddPreimage(common.Hash, []byte)
 
 	ForEachStorage(common.Address, func(common.Hash, common.Hash) bool)
@@ -91,6 +92,10 @@ type StateDB interface {
 	FwImport(contractAddr common.Address, data []byte) error
 	//clone storage data from the `src` to `dest`
 	CloneAccount(src common.Address, dest common.Address) error
+
+	// limit the accesses of a transaction to its access list
+	SetAccessList(list types.AccessList, implicit []common.Address)
+	AccessViolation() error
 }
 
 // CallContext provides a basic interface for the EVM calling conventions. The EVM
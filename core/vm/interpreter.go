@@ -266,7 +266,11 @@ func (in *EVMInterpreter) CanRun(code []byte, input []byte, contract *Contract)
 	if ok, _, _, _ := common.IsWasmContractCode(callerCode); !ok {
 		return true, input
 	}
-	// Handling wasm contract call solidity
+	// Handling wasm contract call solidity, input other than the rlp call
+	// format is taken as solidity abi encoded already.
+	if !isRlpList(input) {
+		return true, input
+	}
 	var (
 		solInput []byte
 		err      error
@@ -13,6 +13,7 @@ import (
 	"github.com/PlatONEnetwork/PlatONE-Go/common"
 	"github.com/PlatONEnetwork/PlatONE-Go/common/math"
 	"github.com/PlatONEnetwork/PlatONE-Go/core/lru"
+	"github.com/PlatONEnetwork/PlatONE-Go/life/codec"
 	"github.com/PlatONEnetwork/PlatONE-Go/life/utils"
 	"github.com/PlatONEnetwork/PlatONE-Go/log"
 	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
@@ -43,6 +44,9 @@ type WASMInterpreter struct {
 	WasmLogger  log.Logger
 	resolver    exec.ImportResolver
 	returnData  []byte
+
+	// binaryResolver serves contracts using the binary abi
+	binaryResolver exec.ImportResolver
 }
 
 // NewWASMInterpreter returns a new instance of the Interpreter
@@ -54,11 +58,12 @@ func NewWASMInterpreter(evm *EVM, cfg Config) *WASMInterpreter {
 		cfg:     &cfg,
 	}
 	return &WASMInterpreter{
-		evm:         evm,
-		cfg:         cfg,
-		WasmLogger:  NewWasmLogger(cfg, log.WasmRoot()),
-		wasmStateDB: wasmStateDB,
-		resolver:    resolver.NewResolver(0x01),
+		evm:            evm,
+		cfg:            cfg,
+		WasmLogger:     NewWasmLogger(cfg, log.WasmRoot()),
+		wasmStateDB:    wasmStateDB,
+		resolver:       resolver.NewResolver(0x01),
+		binaryResolver: resolver.NewResolver(0x03),
 	}
 }
 
@@ -154,6 +159,34 @@ func (in *WASMInterpreter) Run(contract *Contract, input []byte, readOnly bool)
 			}
 		}
 	}
+
+	// contracts declaring the binary abi in their abi file take a separate path,
+	// everything else keeps the C++ BCWasm calling convention.
+	wasmabi := new(utils.WasmAbi)
+	abiErr := wasmabi.FromJson(abi)
+
+	// solidity abi encoded calls, made by solidity contracts and tools, are
+	// translated to the calling convention of the contract and back, when
+	// both interpreters are active.
+	if abiErr == nil && strings.EqualFold("all", in.evm.chainConfig.VMInterpreter) {
+		if call, ok := wasmabi.SolidityCall(input); ok {
+			if input, err = call.WasmInput(input); err != nil {
+				return nil, fmt.Errorf("invalid solidity input: %v", err)
+			}
+			defer func() {
+				switch err {
+				case nil:
+					ret, err = call.SolidityOutput(ret)
+				case errExecutionReverted:
+					ret = solidityRevert(ret)
+				}
+			}()
+		}
+	}
+	if abiErr == nil && wasmabi.IsBinary() {
+		return in.runBinary(contract, input, code, wasmabi)
+	}
+
 	context := &exec.VMContext{
 		Config:   DEFAULT_VM_CONFIG,
 		Addr:     contract.Address(),
@@ -163,16 +196,9 @@ func (in *WASMInterpreter) Run(contract *Contract, input []byte, readOnly bool)
 	}
 
 	var lvm *exec.VirtualMachine
-	var module *lru.WasmModule
-	module, ok := lru.WasmCache().Get(contract.Address())
-
-	if !ok {
-		module = &lru.WasmModule{}
-		module.Module, module.FunctionCode, err = exec.ParseModuleAndFunc(code, nil)
-		if err != nil {
-			return nil, err
-		}
-		lru.WasmCache().Add(contract.Address(), module)
+	module, err := loadWasmModule(contract, code)
+	if err != nil {
+		return nil, err
 	}
 
 	lvm, err = exec.NewVirtualMachineWithModule(module.Module, module.FunctionCode, context, in.resolver, nil)
@@ -219,6 +245,9 @@ func (in *WASMInterpreter) Run(contract *Contract, input []byte, readOnly bool)
 	lvm.InitEntryID = in.evm.InitEntryID
 
 	res, err := lvm.RunWithGasLimit(entryID, int(context.GasLimit), params...)
+	if revert, ok := err.(*exec.RevertError); ok {
+		return revertWasm(contract, context, revert)
+	}
 	if err != nil {
 		log.Error("RunWithGasLimit error", "err", err.Error())
 		return nil, err
@@ -232,6 +261,10 @@ func (in *WASMInterpreter) Run(contract *Contract, input []byte, readOnly bool)
 	if input == nil {
 		return contract.Code, nil
 	}
+	// set by bcwasmReturn, used to return structs and raw bytes
+	if context.ReturnData != nil {
+		return context.ReturnData, nil
+	}
 	// todo: more type need to be completed
 	switch returnType {
 	case "void", "int8", "int", "int32", "int64":
@@ -297,6 +330,118 @@ func (in *WASMInterpreter) Run(contract *Contract, input []byte, readOnly bool)
 	return nil, nil
 }
 
+// runBinary executes a contract using the binary abi (see package codec). The
+// exported function takes no parameters, the arguments and the result are
+// exchanged through the platone_get_input and platone_return host imports.
+func (in *WASMInterpreter) runBinary(contract *Contract, input, code []byte, wasmabi *utils.WasmAbi) ([]byte, error) {
+	var (
+		funcName = "init"
+		args     []byte
+	)
+	if input != nil {
+		txType, name, data, err := codec.DecodeInput(input)
+		if err != nil {
+			return nil, err
+		}
+		if txType == 0 { // transfer to contract address.
+			return nil, nil
+		}
+		fn, ok := wasmabi.Function(name)
+		if !ok {
+			return nil, errFuncNameNotInTheAbis
+		}
+		types, err := wasmabi.InputTypes(fn)
+		if err != nil {
+			return nil, errReturnInvalidAbi
+		}
+		// reject malformed arguments before they reach the contract
+		if _, err := codec.DecodeValues(types, data); err != nil {
+			return nil, fmt.Errorf("invalid input: %v", err)
+		}
+		funcName, args = name, data
+	}
+
+	context := &exec.VMContext{
+		Config:   DEFAULT_VM_CONFIG,
+		Addr:     contract.Address(),
+		GasLimit: contract.Gas,
+		StateDB:  NewWasmStateDB(in.wasmStateDB, contract),
+		Log:      in.WasmLogger,
+		Input:    args,
+	}
+
+	module, err := loadWasmModule(contract, code)
+	if err != nil {
+		return nil, err
+	}
+	lvm, err := exec.NewVirtualMachineWithModule(module.Module, module.FunctionCode, context, in.binaryResolver, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		lvm.Stop()
+	}()
+
+	contract.Input = input
+	entryID, ok := lvm.GetFunctionExport(funcName)
+	if !ok {
+		// the constructor is optional for binary abi contracts
+		if input == nil {
+			return contract.Code, nil
+		}
+		return nil, fmt.Errorf("entryId not found.")
+	}
+	if funcName == "init" {
+		in.evm.InitEntryID = entryID
+	}
+	lvm.InitEntryID = in.evm.InitEntryID
+
+	if _, err := lvm.RunWithGasLimit(entryID, int(context.GasLimit)); err != nil {
+		if revert, ok := err.(*exec.RevertError); ok {
+			return revertWasm(contract, context, revert)
+		}
+		log.Error("RunWithGasLimit error", "err", err.Error())
+		return nil, err
+	}
+	if contract.Gas >= context.GasUsed {
+		contract.Gas = contract.Gas - context.GasUsed
+	} else {
+		return nil, fmt.Errorf("out of gas.")
+	}
+
+	if input == nil {
+		return contract.Code, nil
+	}
+	return context.ReturnData, nil
+}
+
+// revertWasm charges the gas used until the contract reverted, the gas left
+// is returned to the caller together with the revert reason.
+func revertWasm(contract *Contract, context *exec.VMContext, revert *exec.RevertError) ([]byte, error) {
+	if !contract.UseGas(context.GasUsed) {
+		contract.Gas = 0
+	}
+	return revert.Reason, errExecutionReverted
+}
+
+// loadWasmModule returns the compiled module of the contract, compiling and
+// caching it on first use.
+func loadWasmModule(contract *Contract, code []byte) (*lru.WasmModule, error) {
+	module, ok := lru.WasmCache().Get(contract.Address())
+	if !ok {
+		var err error
+		module = &lru.WasmModule{}
+		module.Module, module.FunctionCode, err = exec.ParseModuleAndFunc(code, nil)
+		if err != nil {
+			return nil, err
+		}
+		lru.WasmCache().Add(contract.Address(), module)
+	}
+	// A virtual machine sets up the imported memory and table of its module,
+	// the transactions executing at the same time each get their own copy
+	return module.Copy(), nil
+}
+
 // CanRun tells if the contract, passed as an argument, can be run
 // by the current interpreter
 func (in *WASMInterpreter) CanRun(code, input []byte, contract *Contract) (bool, []byte) {
@@ -330,7 +475,11 @@ func (in *WASMInterpreter) CanRun(code, input []byte, contract *Contract) (bool,
 	if ok, _, _, _ := common.IsWasmContractCode(callerCode); ok {
 		return true, input
 	}
-	// Handling the sol contract call wasm contract
+	// Handling the sol contract call wasm contract, solidity abi encoded
+	// input is translated by Run.
+	if !isJsonInput(input) {
+		return true, input
+	}
 	var (
 		wasmInput []byte
 		err       error
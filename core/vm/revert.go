@@ -0,0 +1,65 @@
+package vm
+
+import (
+	"bytes"
+	"math/big"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/crypto"
+)
+
+// revertSelector is the selector of Error(string), which solidity contracts
+// revert with.
+var revertSelector = crypto.Keccak256([]byte("Error(string)"))[:4]
+
+// solidityRevert encodes a revert reason as Error(string), the way solidity
+// callers expect it.
+func solidityRevert(reason []byte) []byte {
+	return append(common.CopyBytes(revertSelector), MakeReturnBytes(reason)...)
+}
+
+// RevertReason returns a readable reason of a failed execution from the data
+// and the error it returned: the reason given by a reverting contract, or the
+// error otherwise.
+func RevertReason(ret []byte, err error) string {
+	if err == nil {
+		return ""
+	}
+	if err != errExecutionReverted || len(ret) == 0 {
+		return err.Error()
+	}
+	if len(ret) >= 68 && bytes.Equal(ret[:4], revertSelector) {
+		size := new(big.Int).SetBytes(ret[36:68])
+		if size.IsUint64() && size.Uint64() <= uint64(len(ret)-68) {
+			return string(ret[68 : 68+size.Uint64()])
+		}
+	}
+	// wasm contracts revert with the reason itself
+	return string(ret)
+}
+
+// RecordFailure records why the top level call of the transaction failed. A
+// failed system contract function recorded during a successful call is kept.
+func (evm *EVM) RecordFailure(ret []byte, err error) {
+	if err != nil {
+		evm.failure = RevertReason(ret, err)
+	}
+}
+
+// Failure returns why the transaction executed by the EVM failed, or why a
+// system contract function it called failed. Empty if nothing failed.
+func (evm *EVM) Failure() string {
+	return evm.failure
+}
+
+// RecordCalls records the outcomes of the calls of a batch transaction.
+func (evm *EVM) RecordCalls(calls []types.CallResult) {
+	evm.calls = calls
+}
+
+// Calls returns the outcomes of the calls of the batch transaction executed
+// by the EVM, nil for other transactions.
+func (evm *EVM) Calls() []types.CallResult {
+	return evm.calls
+}
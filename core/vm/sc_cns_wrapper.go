@@ -45,7 +45,7 @@ func (cns *CnsWrapper) Run(input []byte) ([]byte, error) {
 		}
 	}()
 
-	fnName, ret, err := execSC(input, cns.AllExportFns())
+	fnName, ret, fnErr, err := execSC(input, cns.AllExportFns())
 	if err != nil {
 		if fnName == "" {
 			fnName = "Notify"
@@ -59,9 +59,10 @@ func (cns *CnsWrapper) Run(input []byte) ([]byte, error) {
 		if strings.ContainsAny(fnName, "ifRegistered") {
 			return common.Int32ToBytes(int32(cnsInvalidArgument)), err
 		}
+		return ret, newSCError(fnName, int64(operateFail), err)
 	}
 
-	return ret, nil
+	return scResult(ret, fnErr)
 }
 
 // for access control
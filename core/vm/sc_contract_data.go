@@ -28,15 +28,16 @@ func (d *ContractDataProcessor) RequiredGas(input []byte) uint64 {
 
 // Run runs the precompiled contract
 func (d *ContractDataProcessor) Run(input []byte) ([]byte, error) {
-	fnName, ret, err := execSC(input, d.AllExportFns())
+	fnName, ret, fnErr, err := execSC(input, d.AllExportFns())
 	if err != nil {
 		if fnName == "" {
 			fnName = "Notify"
 		}
 		d.emitEvent(fnName, operateFail, err.Error())
+		return ret, newSCError(fnName, int64(operateFail), err)
 	}
 
-	return ret, nil
+	return scResult(ret, fnErr)
 }
 
 func (d *ContractDataProcessor) setState(key, value []byte) {
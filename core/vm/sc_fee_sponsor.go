@@ -0,0 +1,204 @@
+package vm
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/common/syscontracts"
+	"github.com/PlatONEnetwork/PlatONE-Go/params"
+)
+
+var (
+	ErrNoSponsorPolicy         = errors.New("fee payer has no sponsor policy")
+	ErrSponsorTargetNotAllowed = errors.New("target not allowed by the sponsor policy")
+	ErrSponsorCapExceeded      = errors.New("daily cap of the sponsor exceeded")
+)
+
+const (
+	sponsorPolicyKey = "sponsorPolicy:"
+
+	// millisecondsPerDay is the length of a day of the sponsor cap in block
+	// time, which is in milliseconds.
+	millisecondsPerDay = 24 * 60 * 60 * 1000
+)
+
+// SponsorPolicy limits the fees a sponsor pays for the transactions of others.
+type SponsorPolicy struct {
+	Targets  []common.Address `json:"targets"`  // Contracts the sponsor pays for, any if empty
+	DailyCap uint64           `json:"dailyCap"` // Gas paid per day at most, no limit if 0
+	Day      uint64           `json:"day"`      // Day the spent gas was counted on
+	Spent    uint64           `json:"spent"`    // Gas paid on that day
+}
+
+// allows reports whether the policy covers a call of target, nil for a
+// contract creation.
+func (p *SponsorPolicy) allows(target *common.Address) bool {
+	if len(p.Targets) == 0 {
+		return true
+	}
+	if target == nil {
+		return false
+	}
+	for _, addr := range p.Targets {
+		if addr == *target {
+			return true
+		}
+	}
+	return false
+}
+
+// spent returns the gas paid on the given day.
+func (p *SponsorPolicy) spent(day uint64) uint64 {
+	if p.Day != day {
+		return 0
+	}
+	return p.Spent
+}
+
+// FeeSponsorManagement keeps the policies of the accounts paying the fees of
+// the transactions of others. An account sets its own policy.
+type FeeSponsorManagement struct {
+	stateDB      StateDB
+	caller       common.Address
+	contractAddr common.Address
+	blockNumber  *big.Int
+}
+
+func (f *FeeSponsorManagement) RequiredGas(input []byte) uint64 {
+	if common.IsBytesEmpty(input) {
+		return 0
+	}
+	return params.FeeSponsorGas
+}
+
+// Run runs the precompiled contract
+func (f *FeeSponsorManagement) Run(input []byte) ([]byte, error) {
+	fnName, ret, fnErr, err := execSC(input, f.AllExportFns())
+	if err != nil {
+		if fnName == "" {
+			fnName = "Notify"
+		}
+		f.emitEvent(fnName, operateFail, err.Error())
+		return ret, newSCError(fnName, int64(operateFail), err)
+	}
+
+	return scResult(ret, fnErr)
+}
+
+func (f *FeeSponsorManagement) emitEvent(topic string, code CodeType, msg string) {
+	emitEvent(f.contractAddr, f.stateDB, f.blockNumber.Uint64(), topic, code, msg)
+}
+
+// for access control
+func (f *FeeSponsorManagement) AllExportFns() SCExportFns {
+	return SCExportFns{
+		"setSponsorPolicy":    f.setSponsorPolicy,
+		"removeSponsorPolicy": f.removeSponsorPolicy,
+		"getSponsorPolicy":    f.getSponsorPolicy,
+	}
+}
+
+// export functions
+func (f *FeeSponsorManagement) setSponsorPolicy(targets string, dailyCap uint64) (int32, error) {
+	var addrs []common.Address
+	if err := json.Unmarshal([]byte(targets), &addrs); err != nil {
+		return int32(operateFail), errParamInvalid
+	}
+	policy, err := getSponsorPolicy(f.stateDB, f.caller)
+	if err != nil {
+		return int32(operateFail), err
+	}
+	if policy == nil {
+		policy = new(SponsorPolicy)
+	}
+	policy.Targets, policy.DailyCap = addrs, dailyCap
+	if err := setSponsorPolicy(f.stateDB, f.caller, policy); err != nil {
+		return int32(operateFail), err
+	}
+	return int32(operateSuccess), nil
+}
+
+func (f *FeeSponsorManagement) removeSponsorPolicy() (int32, error) {
+	f.stateDB.SetState(f.contractAddr, sponsorPolicyStateKey(f.caller), nil)
+	return int32(operateSuccess), nil
+}
+
+func (f *FeeSponsorManagement) getSponsorPolicy(sponsor string) (string, error) {
+	policy, err := getSponsorPolicy(f.stateDB, common.HexToAddress(sponsor))
+	if err != nil {
+		return "", err
+	}
+	if policy == nil {
+		return "", ErrNoSponsorPolicy
+	}
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// internal functions
+func sponsorPolicyStateKey(sponsor common.Address) []byte {
+	return append([]byte(sponsorPolicyKey), sponsor.Bytes()...)
+}
+
+func getSponsorPolicy(db StateDB, sponsor common.Address) (*SponsorPolicy, error) {
+	data := db.GetState(syscontracts.FeeSponsorManagementAddress, sponsorPolicyStateKey(sponsor))
+	if len(data) == 0 {
+		return nil, nil
+	}
+	policy := new(SponsorPolicy)
+	if err := json.Unmarshal(data, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+func setSponsorPolicy(db StateDB, sponsor common.Address, policy *SponsorPolicy) error {
+	data, err := json.Marshal(policy)
+	if err != nil {
+		return err
+	}
+	db.SetState(syscontracts.FeeSponsorManagementAddress, sponsorPolicyStateKey(sponsor), data)
+	return nil
+}
+
+// CheckFeeSponsorship checks that the policy of sponsor covers paying gas
+// for calls of targets, nil standing for a contract creation, at the given
+// block time.
+func CheckFeeSponsorship(db StateDB, sponsor common.Address, targets []*common.Address, gas uint64, time uint64) error {
+	policy, err := getSponsorPolicy(db, sponsor)
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		return ErrNoSponsorPolicy
+	}
+	for _, target := range targets {
+		if !policy.allows(target) {
+			return ErrSponsorTargetNotAllowed
+		}
+	}
+	if policy.DailyCap > 0 {
+		spent := policy.spent(time / millisecondsPerDay)
+		if spent > policy.DailyCap || gas > policy.DailyCap-spent {
+			return ErrSponsorCapExceeded
+		}
+	}
+	return nil
+}
+
+// ChargeFeeSponsorship counts gas paid by sponsor at the given block time
+// against its daily cap.
+func ChargeFeeSponsorship(db StateDB, sponsor common.Address, gas uint64, time uint64) error {
+	policy, err := getSponsorPolicy(db, sponsor)
+	if err != nil || policy == nil {
+		return err
+	}
+	day := time / millisecondsPerDay
+	policy.Spent, policy.Day = policy.spent(day)+gas, day
+	return setSponsorPolicy(db, sponsor, policy)
+}
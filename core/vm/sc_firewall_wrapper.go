@@ -27,15 +27,16 @@ func (u *FwWrapper) RequiredGas(input []byte) uint64 {
 
 // Run runs the precompiled contract
 func (u *FwWrapper) Run(input []byte) ([]byte, error) {
-	fnName, ret, err := execSC(input, u.AllExportFns())
+	fnName, ret, fnErr, err := execSC(input, u.AllExportFns())
 	if err != nil {
 		if fnName == "" {
 			fnName = "Notify"
 		}
 		u.base.emitEvent(fnName, operateFail, err.Error())
+		return ret, newSCError(fnName, int64(operateFail), err)
 	}
 
-	return ret, nil
+	return scResult(ret, fnErr)
 }
 
 // for access control
@@ -49,14 +49,15 @@ func (g *GroupManagement) RequiredGas(input []byte) uint64 {
 
 // Run runs the precompiled contract
 func (g *GroupManagement) Run(input []byte) ([]byte, error) {
-	fnName, ret, err := execSC(input, g.AllExportFns())
+	fnName, ret, fnErr, err := execSC(input, g.AllExportFns())
 	if err != nil {
 		if fnName == "" {
 			fnName = "Notify"
 		}
 		g.emitEvent(fnName, operateFail, err.Error())
+		return ret, newSCError(fnName, int64(operateFail), err)
 	}
-	return ret, nil
+	return scResult(ret, fnErr)
 }
 
 func (g *GroupManagement) setState(key, value []byte) {
@@ -29,7 +29,7 @@ func (n *scNodeWrapper) RequiredGas(input []byte) uint64 {
 }
 
 func (n *scNodeWrapper) Run(input []byte) ([]byte, error) {
-	fnName, ret, err := execSC(input, n.allExportFns())
+	fnName, ret, fnErr, err := execSC(input, n.allExportFns())
 	if err != nil {
 		if fnName == "" {
 			fnName = "Notify"
@@ -39,8 +39,9 @@ func (n *scNodeWrapper) Run(input []byte) ([]byte, error) {
 		if strings.Contains(fnName, "get") {
 			return MakeReturnBytes([]byte(newInternalErrorResult(err).String())), err
 		}
+		return ret, newSCError(fnName, int64(operateFail), err)
 	}
-	return ret, nil
+	return scResult(ret, fnErr)
 }
 
 func (n *scNodeWrapper) add(node *syscontracts.NodeInfo) (int, error) {
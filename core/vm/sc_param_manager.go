@@ -74,14 +74,15 @@ func (u *ParamManager) RequiredGas(input []byte) uint64 {
 }
 
 func (u *ParamManager) Run(input []byte) ([]byte, error) {
-	fnName, ret, err := execSC(input, u.AllExportFns())
+	fnName, ret, fnErr, err := execSC(input, u.AllExportFns())
 	if err != nil {
 		if fnName == "" {
 			fnName = "Notify"
 		}
 		u.emitNotifyEventInParam(fnName, operateFail, err.Error())
+		return ret, newSCError(fnName, int64(operateFail), err)
 	}
-	return ret, nil
+	return scResult(ret, fnErr)
 }
 
 func (u *ParamManager) setState(key, value []byte) {
@@ -146,6 +146,10 @@ func (m *mockStateDB) AddLog(log *types.Log) {
 	m.eLogs[log.Topics[0].String()] = log
 }
 
+func (m *mockStateDB) LogSize() uint {
+	return uint(len(m.eLogs))
+}
+
 func (m *mockStateDB) AddPreimage(common.Hash, []byte) {
 	panic("implement me")
 }
@@ -197,3 +201,11 @@ func (m *mockStateDB) IsFwOpened(contractAddr common.Address) bool {
 func (m *mockStateDB) FwImport(contractAddr common.Address, data []byte) error {
 	panic("implement me")
 }
+
+func (m *mockStateDB) SetAccessList(list types.AccessList, implicit []common.Address) {
+	panic("implement me")
+}
+
+func (m *mockStateDB) AccessViolation() error {
+	panic("implement me")
+}
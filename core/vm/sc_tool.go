@@ -59,11 +59,14 @@ var (
 
 var fwErrNotOwner = errors.New("FW : error, only contract owner can set firewall setting")
 
-func execSC(input []byte, fns SCExportFns) (string, []byte, error) {
+// execSC runs the system contract function called by input. A failure of
+// the function itself does not fail the call, its result code is returned
+// and the failure is reported as fnErr.
+func execSC(input []byte, fns SCExportFns) (fnName string, ret []byte, fnErr *SCError, err error) {
 	txType, fnName, fn, params, err := retrieveFnAndParams(input, fns)
 	if nil != err {
 		log.Error("failed to retrieve func name and params.", "error", err, "function", fnName)
-		return fnName, nil, err
+		return fnName, nil, nil, err
 	}
 
 	//execute system contract method
@@ -72,10 +75,23 @@ func execSC(input []byte, fns SCExportFns) (string, []byte, error) {
 	result := reflect.ValueOf(fn).Call(params)
 	if err, ok := result[1].Interface().(error); ok {
 		log.Error("execute system contract failed.", "error", err)
+		fnErr = newSCError(fnName, resultCode(result[0]), err)
 	}
 
 	//vm run successfully, so return nil
-	return fnName, toContractReturnValueType(txType, result[0]), nil
+	return fnName, toContractReturnValueType(txType, result[0]), fnErr, nil
+}
+
+// resultCode returns the result code of a system contract function, or
+// operateFail if the function does not return an integer.
+func resultCode(val reflect.Value) int64 {
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return val.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(val.Uint())
+	}
+	return int64(operateFail)
 }
 
 func toContractReturnValueType(txType int, val reflect.Value) []byte {
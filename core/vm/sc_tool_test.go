@@ -47,7 +47,7 @@ func Test_execSC(t *testing.T) {
 	var age int64 = 3
 	var input = MakeInput(fnNameInput, name, age)
 
-	_, ret, err := execSC(input, (&fakeClass{}).allExportFns())
+	_, ret, _, err := execSC(input, (&fakeClass{}).allExportFns())
 	if nil != err {
 		t.Error(err)
 		return
@@ -58,7 +58,7 @@ func Test_execSC(t *testing.T) {
 	assert.Equal(t, toContractReturnValueStringType(E_INVOKE_CONTRACT, []byte(ret2)), ret)
 
 	input = MakeInput(fnNameInput, "bbb")
-	_, _, err = execSC(input, (&fakeClass{}).allExportFns())
+	_, _, _, err = execSC(input, (&fakeClass{}).allExportFns())
 	assert.Error(t, err, "The params number invalid")
 }
 
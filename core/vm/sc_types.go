@@ -17,6 +17,33 @@ const (
 	operateFail    CodeType = 1
 )
 
+// SCError is the failure of a system contract function. The call of a
+// failing function still succeeds and returns the result code of the
+// function, so SCError never reaches the EVM: it is only recorded as the
+// failure reason of the transaction, see EVM.Failure.
+type SCError struct {
+	Function string
+	Code     int64 // result code returned by the function
+	Err      error
+}
+
+func newSCError(fnName string, code int64, err error) *SCError {
+	return &SCError{Function: fnName, Code: code, Err: err}
+}
+
+func (e *SCError) Error() string {
+	return fmt.Sprintf("%s failed with code %d: %v", e.Function, e.Code, e.Err)
+}
+
+// scResult returns the result of a system contract call and the failure of
+// its function, if any, to RunPlatONEPrecompiledSC.
+func scResult(ret []byte, fnErr *SCError) ([]byte, error) {
+	if fnErr == nil {
+		return ret, nil
+	}
+	return ret, fnErr
+}
+
 type result struct {
 	Code int         `json:"code"`
 	Msg  string      `json:"msg"`
@@ -23,14 +23,15 @@ func (u *UserManagement) RequiredGas(input []byte) uint64 {
 
 // Run runs the precompiled contract
 func (u *UserManagement) Run(input []byte) ([]byte, error) {
-	fnName, ret, err := execSC(input, u.AllExportFns())
+	fnName, ret, fnErr, err := execSC(input, u.AllExportFns())
 	if err != nil {
 		if fnName == "" {
 			fnName = "Notify"
 		}
 		u.emitEvent(fnName, operateFail, err.Error())
+		return ret, newSCError(fnName, int64(operateFail), err)
 	}
-	return ret, nil
+	return scResult(ret, fnErr)
 }
 
 func (u *UserManagement) setState(key, value []byte) {
@@ -22,6 +22,7 @@ var PlatONEPrecompiledContracts = map[common.Address]PrecompiledContract{
 	syscontracts.FirewallManagementAddress:    &FwWrapper{},
 	syscontracts.GroupManagementAddress:       &GroupManagement{},
 	syscontracts.ContractDataProcessorAddress: &ContractDataProcessor{},
+	syscontracts.FeeSponsorManagementAddress:  &FeeSponsorManagement{},
 	syscontracts.GroupManagementAddress:       &GroupManagement{},
 	syscontracts.CnsInvokeAddress:             &CnsInvoke{},
 }
@@ -37,74 +38,95 @@ func RunPlatONEPrecompiledSC(p PrecompiledContract, input []byte, contract *Cont
 	gas := p.RequiredGas(input)
 
 	if contract.UseGas(gas) {
-		switch p.(type) {
-		case *UserManagement:
-			um := &UserManagement{
-				stateDB:      evm.StateDB,
-				caller:       contract.Caller(),
-				contractAddr: syscontracts.UserManagementAddress,
-				blockNumber:  evm.BlockNumber,
+		ret, err = runPlatONEPrecompiledSC(p, input, contract, evm)
+		if fnErr, ok := err.(*SCError); ok {
+			// the call succeeds, only keep why the function failed
+			if evm.depth == 0 {
+				evm.failure = fnErr.Error()
 			}
-			return um.Run(input)
-		case *scNodeWrapper:
-			node := newSCNodeWrapper(evm.StateDB)
-			node.base.caller = evm.Origin
-			node.base.blockNumber = evm.BlockNumber
-			node.base.contractAddr = *contract.CodeAddr
+			return ret, nil
+		}
+		return ret, err
+	}
 
-			return node.Run(input)
-		case *CnsWrapper:
-			cns := newCnsManager(evm.StateDB)
-			cns.caller = contract.CallerAddress
-			cns.origin = evm.Origin
-			cns.isInit = evm.InitEntryID
-			cns.blockNumber = evm.BlockNumber
+	return nil, ErrOutOfGas
+}
 
-			cnsWrap := new(CnsWrapper)
-			cnsWrap.base = cns
+// runPlatONEPrecompiledSC sets up the system contract p for the call and runs it.
+func runPlatONEPrecompiledSC(p PrecompiledContract, input []byte, contract *Contract, evm *EVM) ([]byte, error) {
+	switch p.(type) {
+	case *UserManagement:
+		um := &UserManagement{
+			stateDB:      evm.StateDB,
+			caller:       contract.Caller(),
+			contractAddr: syscontracts.UserManagementAddress,
+			blockNumber:  evm.BlockNumber,
+		}
+		return um.Run(input)
+	case *scNodeWrapper:
+		node := newSCNodeWrapper(evm.StateDB)
+		node.base.caller = evm.Origin
+		node.base.blockNumber = evm.BlockNumber
+		node.base.contractAddr = *contract.CodeAddr
 
-			return cnsWrap.Run(input)
-		case *ParamManager:
-			p := &ParamManager{
-				stateDB:      evm.StateDB,
-				contractAddr: contract.CodeAddr,
-				caller:       evm.Context.Origin,
-				blockNumber:  evm.BlockNumber,
-			}
-			return p.Run(input)
-		case *FwWrapper:
-			fw := new(FwWrapper)
-			fw.base = NewFireWall(evm, contract)
+		return node.Run(input)
+	case *CnsWrapper:
+		cns := newCnsManager(evm.StateDB)
+		cns.caller = contract.CallerAddress
+		cns.origin = evm.Origin
+		cns.isInit = evm.InitEntryID
+		cns.blockNumber = evm.BlockNumber
 
-			return fw.Run(input)
-		case *GroupManagement:
-			gm := &GroupManagement{
-				stateDB:      evm.StateDB,
-				contractAddr: contract.self.Address(),
-				caller:       contract.caller.Address(),
-				blockNumber:  evm.BlockNumber,
-			}
-			return gm.Run(input)
-		case *ContractDataProcessor:
-			dp := &ContractDataProcessor{
-				stateDB:      evm.StateDB,
-				contractAddr: contract.self.Address(),
-				caller:       contract.caller.Address(),
-				blockNumber:  evm.BlockNumber,
-			}
-			return dp.Run(input)
-		case *CnsInvoke:
-			ci := &CnsInvoke{
-				evm:         evm,
-				caller:      evm.Context.Origin,
-				contract:    contract,
-				blockNumber: evm.BlockNumber,
-			}
-			return ci.Run(input)
-		default:
-			panic("system contract handler not found")
+		cnsWrap := new(CnsWrapper)
+		cnsWrap.base = cns
+
+		return cnsWrap.Run(input)
+	case *ParamManager:
+		p := &ParamManager{
+			stateDB:      evm.StateDB,
+			contractAddr: contract.CodeAddr,
+			caller:       evm.Context.Origin,
+			blockNumber:  evm.BlockNumber,
 		}
-	}
+		return p.Run(input)
+	case *FwWrapper:
+		fw := new(FwWrapper)
+		fw.base = NewFireWall(evm, contract)
 
-	return nil, ErrOutOfGas
+		return fw.Run(input)
+	case *GroupManagement:
+		gm := &GroupManagement{
+			stateDB:      evm.StateDB,
+			contractAddr: contract.self.Address(),
+			caller:       contract.caller.Address(),
+			blockNumber:  evm.BlockNumber,
+		}
+		return gm.Run(input)
+	case *ContractDataProcessor:
+		dp := &ContractDataProcessor{
+			stateDB:      evm.StateDB,
+			contractAddr: contract.self.Address(),
+			caller:       contract.caller.Address(),
+			blockNumber:  evm.BlockNumber,
+		}
+		return dp.Run(input)
+	case *FeeSponsorManagement:
+		fs := &FeeSponsorManagement{
+			stateDB:      evm.StateDB,
+			contractAddr: contract.self.Address(),
+			caller:       contract.caller.Address(),
+			blockNumber:  evm.BlockNumber,
+		}
+		return fs.Run(input)
+	case *CnsInvoke:
+		ci := &CnsInvoke{
+			evm:         evm,
+			caller:      evm.Context.Origin,
+			contract:    contract,
+			blockNumber: evm.BlockNumber,
+		}
+		return ci.Run(input)
+	default:
+		panic("system contract handler not found")
+	}
 }
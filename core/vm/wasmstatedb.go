@@ -6,6 +6,7 @@ import (
 
 	"github.com/PlatONEnetwork/PlatONE-Go/common"
 	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/life/exec"
 	"github.com/PlatONEnetwork/PlatONE-Go/params"
 )
 
@@ -130,6 +131,24 @@ func (self *WasmStateDB) DelegateCall(addr, param []byte) ([]byte, error) {
 	return ret, err
 }
 
+func (self *WasmStateDB) CallContract(addr common.Address, param []byte, gas uint64, value *big.Int) ([]byte, uint64, error) {
+	ret, leftOverGas, err := self.evm.Call(self.contract, addr, param, gas, value)
+	return ret, gas - leftOverGas, wasmCallError(ret, err)
+}
+
+func (self *WasmStateDB) DelegateCallContract(addr common.Address, param []byte, gas uint64) ([]byte, uint64, error) {
+	ret, leftOverGas, err := self.evm.DelegateCall(self.contract, addr, param, gas)
+	return ret, gas - leftOverGas, wasmCallError(ret, err)
+}
+
+// wasmCallError hands the reason of a reverted call over to the calling contract.
+func wasmCallError(ret []byte, err error) error {
+	if err == errExecutionReverted {
+		return &exec.RevertError{Reason: ret}
+	}
+	return err
+}
+
 func (self *WasmStateDB) GetCode(addr common.Address) []byte {
 	return self.evm.StateDB.GetCode(addr)
 }
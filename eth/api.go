@@ -34,6 +34,7 @@ import (
 	"github.com/PlatONEnetwork/PlatONE-Go/core/rawdb"
 	"github.com/PlatONEnetwork/PlatONE-Go/core/state"
 	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
 	"github.com/PlatONEnetwork/PlatONE-Go/internal/ethapi"
 	"github.com/PlatONEnetwork/PlatONE-Go/params"
 	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
@@ -336,6 +337,23 @@ type storageEntry struct {
 	Value common.Hash  `json:"value"`
 }
 
+// maxStorageRange is the most storage entries returned by a WASM storage range
+// call.
+const maxStorageRange = 1024
+
+// storageRangeSize checks the number of entries requested in a WASM storage
+// range call, capped to maxStorageRange. Empty ranges are rejected, as they
+// would resume from where they started.
+func storageRangeSize(maxResult int) (int, error) {
+	if maxResult <= 0 {
+		return 0, fmt.Errorf("invalid maxResult %d, must be positive", maxResult)
+	}
+	if maxResult > maxStorageRange {
+		maxResult = maxStorageRange
+	}
+	return maxResult, nil
+}
+
 // StorageRangeAt returns the storage at the given block height and transaction index.
 func (api *PrivateDebugAPI) StorageRangeAt(ctx context.Context, blockHash common.Hash, txIndex int, contractAddress common.Address, keyStart hexutil.Bytes, maxResult int) (StorageRangeResult, error) {
 	_, _, statedb, err := api.computeTxEnv(blockHash, txIndex, 0)
@@ -372,6 +390,57 @@ func storageRangeAt(st state.Trie, start []byte, maxResult int) (StorageRangeRes
 	return result, nil
 }
 
+// WasmStorageRangeResult is the result of a debug_wasmStorageRangeAt API call.
+type WasmStorageRangeResult struct {
+	Root    common.Hash        `json:"root"` // Root of the storage trie of the contract
+	Storage []wasmStorageEntry `json:"storage"`
+	Next    hexutil.Bytes      `json:"next"`            // Resume token, nil if Storage includes the last entry
+	Proof   []hexutil.Bytes    `json:"proof,omitempty"` // Storage trie nodes proving the entries
+}
+
+type wasmStorageEntry struct {
+	Hash  common.Hash   `json:"hash"` // Key of the entry in the storage trie
+	Key   hexutil.Bytes `json:"key"`  // Key set by the contract, nil if unknown
+	Value hexutil.Bytes `json:"value"`
+}
+
+// WasmStorageRangeAt returns up to maxResult entries, at most maxStorageRange,
+// of the storage of a WASM contract at the given block height and transaction
+// index, with the keys set by the contract. The entries are returned from the
+// resume token next of the previous call on, and along with the trie nodes
+// proving them if proof is set.
+func (api *PrivateDebugAPI) WasmStorageRangeAt(ctx context.Context, blockHash common.Hash, txIndex int, contractAddress common.Address, next hexutil.Bytes, maxResult int, proof bool) (WasmStorageRangeResult, error) {
+	maxResult, err := storageRangeSize(maxResult)
+	if err != nil {
+		return WasmStorageRangeResult{}, err
+	}
+	_, _, statedb, err := api.computeTxEnv(blockHash, txIndex, 0)
+	if err != nil {
+		return WasmStorageRangeResult{}, err
+	}
+	page, err := statedb.StorageRange(contractAddress, next, maxResult)
+	if err != nil {
+		return WasmStorageRangeResult{}, err
+	}
+	result := WasmStorageRangeResult{Root: page.Root, Storage: []wasmStorageEntry{}, Next: page.Next}
+	hashes := make([]common.Hash, 0, len(page.Entries))
+	for _, entry := range page.Entries {
+		result.Storage = append(result.Storage, wasmStorageEntry{Hash: entry.Hash, Key: entry.Key, Value: entry.Value})
+		hashes = append(hashes, entry.Hash)
+	}
+	if proof {
+		nodes := ethdb.NewMemDatabase()
+		if err := statedb.ProveStorage(contractAddress, hashes, nodes); err != nil {
+			return WasmStorageRangeResult{}, err
+		}
+		for _, key := range nodes.Keys() {
+			node, _ := nodes.Get(key)
+			result.Proof = append(result.Proof, node)
+		}
+	}
+	return result, nil
+}
+
 // GetModifiedAccountsByNumber returns all accounts that have changed between the
 // two blocks specified. A change is defined as a difference in nonce, balance,
 // code hash, or storage hash.
@@ -108,6 +108,9 @@ func (b *EthAPIBackend) GetBlock(ctx context.Context, hash common.Hash) (*types.
 
 func (b *EthAPIBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
 	if number := rawdb.ReadHeaderNumber(b.eth.chainDb, hash); number != nil {
+		if err := core.CheckReceiptsPruned(b.eth.chainDb, *number); err != nil {
+			return nil, err
+		}
 		return rawdb.ReadReceipts(b.eth.chainDb, hash, *number), nil
 	}
 	return nil, nil
@@ -118,6 +121,9 @@ func (b *EthAPIBackend) GetLogs(ctx context.Context, hash common.Hash) ([][]*typ
 	if number == nil {
 		return nil, nil
 	}
+	if err := core.CheckReceiptsPruned(b.eth.chainDb, *number); err != nil {
+		return nil, err
+	}
 	receipts := rawdb.ReadReceipts(b.eth.chainDb, hash, *number)
 	if receipts == nil {
 		return nil, nil
@@ -189,6 +195,14 @@ func (b *EthAPIBackend) SubscribeNewTxsEvent(ch chan<- core.NewTxsEvent) event.S
 	return b.eth.TxPool().SubscribeNewTxsEvent(ch)
 }
 
+func (b *EthAPIBackend) TxLifecycle(hash common.Hash) []core.TxEvent {
+	return b.eth.TxPool().Lifecycle(hash)
+}
+
+func (b *EthAPIBackend) SubscribeTxLifecycleEvent(ch chan<- core.TxLifecycleEvent) event.Subscription {
+	return b.eth.TxPool().SubscribeTxLifecycleEvent(ch)
+}
+
 func (b *EthAPIBackend) Downloader() *downloader.Downloader {
 	return b.eth.Downloader()
 }
@@ -227,7 +241,3 @@ func (b *EthAPIBackend) ServiceFilter(ctx context.Context, session *bloombits.Ma
 		go session.Multiplex(bloomRetrievalBatch, bloomRetrievalWait, b.eth.bloomRequests)
 	}
 }
-
-func (b *EthAPIBackend) GenerateTxs(benchmark *types.Benchmark) {
-	b.eth.txPool.GenerateTxs(benchmark)
-}
@@ -39,6 +39,7 @@ import (
 	"github.com/PlatONEnetwork/PlatONE-Go/eth/downloader"
 	"github.com/PlatONEnetwork/PlatONE-Go/eth/filters"
 	"github.com/PlatONEnetwork/PlatONE-Go/eth/gasprice"
+	"github.com/PlatONEnetwork/PlatONE-Go/eth/loadgen"
 	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
 	"github.com/PlatONEnetwork/PlatONE-Go/event"
 	"github.com/PlatONEnetwork/PlatONE-Go/internal/ethapi"
@@ -85,10 +86,12 @@ type Ethereum struct {
 
 	bloomRequests chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
 	bloomIndexer  *core.ChainIndexer             // Bloom indexer operating during block imports
+	receiptPruner *core.ReceiptPruner            // Pruner of the old receipts, nil if they are kept
 
 	APIBackend *EthAPIBackend
 
 	miner     *miner.Miner
+	loadgen   *loadgen.Generator
 	gasPrice  *big.Int
 	etherbase common.Address
 
@@ -127,6 +130,9 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 	if err != nil {
 		return nil, err
 	}
+	if chainDb, err = OpenAncients(ctx, chainDb, config.AncientThreshold); err != nil {
+		return nil, err
+	}
 	chainConfig, _, genesisErr := core.SetupGenesisBlock(chainDb, config.Genesis)
 	if genesisErr != nil {
 		return nil, genesisErr
@@ -166,7 +172,7 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 		EWASMInterpreter:        config.EWASMInterpreter,
 		EVMInterpreter:          config.EVMInterpreter,
 	}
-	cacheConfig := &core.CacheConfig{Disabled: config.NoPruning, TrieNodeLimit: config.TrieCache, TrieTimeLimit: config.TrieTimeout}
+	cacheConfig := &core.CacheConfig{Disabled: config.NoPruning, TrieNodeLimit: config.TrieCache, TrieTimeLimit: config.TrieTimeout, StateCheckpoint: config.StateCheckpoint}
 	common.SetCurrentInterpreterType(chainConfig.VMInterpreter)
 
 	eth.blockchain, missingStateBlocks, err = core.NewBlockChain(chainDb, extDb, cacheConfig, eth.chainConfig, eth.engine, vmConfig, eth.shouldPreserve)
@@ -175,6 +181,17 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 	}
 	blockChainCache := core.NewBlockChainCache(eth.blockchain)
 
+	if config.ReceiptRetention > 0 {
+		var archiver core.ReceiptArchiver
+		if config.ReceiptArchive != "" {
+			if archiver, err = core.NewFileReceiptArchiver(ctx.ResolvePath(config.ReceiptArchive)); err != nil {
+				return nil, err
+			}
+		}
+		eth.receiptPruner = core.NewReceiptPruner(chainDb, config.ReceiptRetention, archiver)
+		eth.receiptPruner.Start()
+	}
+
 	eth.bloomIndexer.Start(eth.blockchain)
 
 	eth.APIBackend = &EthAPIBackend{eth, nil}
@@ -199,8 +216,9 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 		config.TxPool.Journal = ctx.ResolvePath(config.TxPool.Journal)
 	}
 	//eth.txPool = core.NewTxPool(config.TxPool, eth.chainConfig, eth.blockchain)
-	eth.txPool = core.NewTxPool(config.TxPool, eth.chainConfig, blockChainCache, chainDb, eth.extDb, ctx.NodeKey())
+	eth.txPool = core.NewTxPool(config.TxPool, eth.chainConfig, blockChainCache, chainDb, eth.extDb)
 	log.Debug("Transaction pool info", "pool", eth.txPool)
+	eth.loadgen = loadgen.New(eth.APIBackend, ctx.NodeKey())
 
 	recommit := config.MinerRecommit
 	eth.miner = miner.New(eth, eth.chainConfig, eth.EventMux(), eth.engine, recommit, config.MinerGasFloor, config.MinerGasCeil, eth.isLocalBlock, highestLogicalBlockCh, blockChainCache)
@@ -243,6 +261,21 @@ func CreateDB(ctx *node.ServiceContext, config *Config, name string) (ethdb.Data
 	return db, nil
 }
 
+// AncientDir is the directory of the ancient block store in the node's data
+// directory, next to the chain database.
+const AncientDir = "ancient"
+
+// OpenAncients returns the chain database db reading the old blocks from the
+// ancient store of the node, and moving there the blocks at least threshold
+// blocks old if not 0. Ephemeral nodes have no ancient store.
+func OpenAncients(ctx *node.ServiceContext, db ethdb.Database, threshold uint64) (ethdb.Database, error) {
+	dir := ctx.ResolvePath(AncientDir)
+	if dir == "" {
+		return db, nil
+	}
+	return rawdb.NewDatabaseWithFreezer(db, dir, threshold)
+}
+
 // create extended database
 func CreateExtDB(ctx *node.ServiceContext, config *Config, name string) (ethdb.Database, error) {
 	db, err := ctx.OpenDatabase(name, config.DatabaseCache, config.DatabaseHandles)
@@ -316,6 +349,10 @@ func (s *Ethereum) APIs() []rpc.API {
 			Version:   "1.0",
 			Service:   s.netRPCService,
 			Public:    true,
+		}, {
+			Namespace: "loadgen",
+			Version:   "1.0",
+			Service:   loadgen.NewPrivateLoadGenAPI(s.loadgen),
 		},
 	}...)
 }
@@ -520,12 +557,16 @@ func (s *Ethereum) Start(srvr *p2p.Server) error {
 // Ethereum protocol.
 func (s *Ethereum) Stop() error {
 	s.bloomIndexer.Close()
+	if s.receiptPruner != nil {
+		s.receiptPruner.Stop()
+	}
 	s.blockchain.Stop()
 	s.engine.Close()
 	s.protocolManager.Stop()
 	if s.lesServer != nil {
 		s.lesServer.Stop()
 	}
+	s.loadgen.Stop()
 	s.txPool.Stop()
 	s.miner.Stop()
 	s.eventMux.Stop()
@@ -70,6 +70,10 @@ type Config struct {
 	DatabaseCache      int
 	TrieCache          int
 	TrieTimeout        time.Duration
+	StateCheckpoint    uint64 `toml:",omitempty"` // Interval of the block states kept by state pruning
+	AncientThreshold   uint64 `toml:",omitempty"` // Age in blocks of the blocks moved to the ancient store, 0 to keep them
+	ReceiptRetention   uint64 `toml:",omitempty"` // Number of recent blocks whose receipts and logs are kept, 0 for all
+	ReceiptArchive     string `toml:",omitempty"` // File the pruned receipts are appended to, none if empty
 
 	// Mining-related options
 	Etherbase      common.Address `toml:",omitempty"`
@@ -325,7 +325,11 @@ func (api *PublicFilterAPI) NewFilter(crit FilterCriteria) (rpc.ID, error) {
 // GetLogs returns logs matching the given argument that are stored within the state.
 //
 // https://github.com/ethereum/wiki/wiki/JSON-RPC#eth_getlogs
-func (api *PublicFilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*types.Log, error) {
+//
+// When opts.Decode is set every log emitted by a wasm contract is returned
+// with an additional "event" field holding the event decoded against the
+// contract abi.
+func (api *PublicFilterAPI) GetLogs(ctx context.Context, crit FilterCriteria, opts *LogOptions) (interface{}, error) {
 	var filter *Filter
 	if crit.BlockHash != nil {
 		// Block filter requested, construct a single-shot filter
@@ -348,6 +352,9 @@ func (api *PublicFilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([
 	if err != nil {
 		return nil, err
 	}
+	if opts != nil && opts.Decode {
+		return decodeLogs(ctx, api.backend, logs)
+	}
 	return returnLogs(logs), err
 }
 
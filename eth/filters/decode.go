@@ -0,0 +1,96 @@
+package filters
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/state"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/life/utils"
+	"github.com/PlatONEnetwork/PlatONE-Go/rpc"
+)
+
+// LogOptions are the optional settings of eth_getLogs.
+type LogOptions struct {
+	// Decode adds the event decoded against the abi of the emitting wasm
+	// contract to every log whose contract declares it.
+	Decode bool `json:"decode"`
+}
+
+// stateBackend is implemented by the backends able to read contract code.
+type stateBackend interface {
+	StateAndHeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*state.StateDB, *types.Header, error)
+}
+
+// DecodedLog is a log with its decoded event, if any.
+type DecodedLog struct {
+	*types.Log
+	Event *utils.DecodedEvent
+}
+
+// MarshalJSON adds the event field to the json encoding of the log.
+func (l *DecodedLog) MarshalJSON() ([]byte, error) {
+	enc, err := json.Marshal(l.Log)
+	if err != nil || l.Event == nil {
+		return enc, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(enc, &fields); err != nil {
+		return nil, err
+	}
+	if fields["event"], err = json.Marshal(l.Event); err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+// decodeLogs decodes the logs emitted by wasm contracts with the abi stored
+// in their code at the latest block. Logs that cannot be decoded are
+// returned as they are.
+func decodeLogs(ctx context.Context, backend Backend, logs []*types.Log) ([]*DecodedLog, error) {
+	decoded := make([]*DecodedLog, len(logs))
+	for i, log := range logs {
+		decoded[i] = &DecodedLog{Log: log}
+	}
+	sb, ok := backend.(stateBackend)
+	if !ok || len(logs) == 0 {
+		return decoded, nil
+	}
+	statedb, _, err := sb.StateAndHeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	abis := make(map[common.Address]*utils.WasmAbi)
+	for _, log := range decoded {
+		abi, cached := abis[log.Address]
+		if !cached {
+			abi = contractAbi(statedb.GetCode(log.Address))
+			abis[log.Address] = abi
+		}
+		if abi == nil {
+			continue
+		}
+		if ev, err := abi.DecodeEvent(log.Topics, log.Data); err == nil {
+			log.Event = ev
+		}
+	}
+	return decoded, nil
+}
+
+func contractAbi(code []byte) *utils.WasmAbi {
+	if len(code) == 0 {
+		return nil
+	}
+	// evm contracts fail to parse here or have no json abi below
+	_, abiBytes, _, err := common.ParseWasmCodeRlpData(code)
+	if err != nil {
+		return nil
+	}
+	abi := new(utils.WasmAbi)
+	if err = abi.FromJson(abiBytes); err != nil {
+		return nil
+	}
+	return abi
+}
@@ -127,6 +127,9 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 		if header == nil {
 			return nil, errors.New("unknown block")
 		}
+		if err := core.CheckReceiptsPruned(f.backend.ChainDb(), header.Number.Uint64()); err != nil {
+			return nil, err
+		}
 		return f.blockLogs(ctx, header)
 	}
 	// Figure out the limits of the filter range
@@ -143,6 +146,9 @@ func (f *Filter) Logs(ctx context.Context) ([]*types.Log, error) {
 	if f.end == -1 {
 		end = head
 	}
+	if err := core.CheckReceiptsPruned(f.backend.ChainDb(), uint64(f.begin)); err != nil {
+		return nil, err
+	}
 	// Gather all indexed logs, and finish with non indexed ones
 	var (
 		logs []*types.Log
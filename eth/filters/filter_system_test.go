@@ -371,7 +371,7 @@ func TestInvalidGetLogsRequest(t *testing.T) {
 	}
 
 	for i, test := range testCases {
-		if _, err := api.GetLogs(context.Background(), test); err == nil {
+		if _, err := api.GetLogs(context.Background(), test, nil); err == nil {
 			t.Errorf("Expected Logs for case #%d to fail", i)
 		}
 	}
@@ -29,6 +29,10 @@ func (c Config) MarshalTOML() (interface{}, error) {
 		DatabaseCache           int
 		TrieCache               int
 		TrieTimeout             time.Duration
+		StateCheckpoint         uint64         `toml:",omitempty"`
+		AncientThreshold        uint64         `toml:",omitempty"`
+		ReceiptRetention        uint64         `toml:",omitempty"`
+		ReceiptArchive          string         `toml:",omitempty"`
 		Etherbase               common.Address `toml:",omitempty"`
 		MinerNotify             []string       `toml:",omitempty"`
 		MinerExtraData          hexutil.Bytes  `toml:",omitempty"`
@@ -54,6 +58,10 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.DatabaseCache = c.DatabaseCache
 	enc.TrieCache = c.TrieCache
 	enc.TrieTimeout = c.TrieTimeout
+	enc.StateCheckpoint = c.StateCheckpoint
+	enc.AncientThreshold = c.AncientThreshold
+	enc.ReceiptRetention = c.ReceiptRetention
+	enc.ReceiptArchive = c.ReceiptArchive
 	enc.Etherbase = c.Etherbase
 	enc.MinerNotify = c.MinerNotify
 	enc.MinerExtraData = c.MinerExtraData
@@ -83,6 +91,10 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 		DatabaseCache           *int
 		TrieCache               *int
 		TrieTimeout             *time.Duration
+		StateCheckpoint         *uint64         `toml:",omitempty"`
+		AncientThreshold        *uint64         `toml:",omitempty"`
+		ReceiptRetention        *uint64         `toml:",omitempty"`
+		ReceiptArchive          *string         `toml:",omitempty"`
 		Etherbase               *common.Address `toml:",omitempty"`
 		MinerNotify             []string        `toml:",omitempty"`
 		MinerExtraData          *hexutil.Bytes  `toml:",omitempty"`
@@ -133,6 +145,18 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.TrieTimeout != nil {
 		c.TrieTimeout = *dec.TrieTimeout
 	}
+	if dec.StateCheckpoint != nil {
+		c.StateCheckpoint = *dec.StateCheckpoint
+	}
+	if dec.AncientThreshold != nil {
+		c.AncientThreshold = *dec.AncientThreshold
+	}
+	if dec.ReceiptRetention != nil {
+		c.ReceiptRetention = *dec.ReceiptRetention
+	}
+	if dec.ReceiptArchive != nil {
+		c.ReceiptArchive = *dec.ReceiptArchive
+	}
 	if dec.Etherbase != nil {
 		c.Etherbase = *dec.Etherbase
 	}
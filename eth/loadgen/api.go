@@ -0,0 +1,28 @@
+package loadgen
+
+// PrivateLoadGenAPI controls the load generator of the node. It is private as
+// the generated transactions are signed by the node key.
+type PrivateLoadGenAPI struct {
+	g *Generator
+}
+
+// NewPrivateLoadGenAPI creates the API of the load generator g.
+func NewPrivateLoadGenAPI(g *Generator) *PrivateLoadGenAPI {
+	return &PrivateLoadGenAPI{g}
+}
+
+// Start begins replaying a recorded workload at the target TPS.
+func (api *PrivateLoadGenAPI) Start(config Config) error {
+	return api.g.Start(config)
+}
+
+// Stop interrupts the running load generation.
+func (api *PrivateLoadGenAPI) Stop() error {
+	return api.g.Stop()
+}
+
+// Report returns the progress and latency percentiles of the running load
+// generation, or of the last one.
+func (api *PrivateLoadGenAPI) Report() Report {
+	return api.g.Report()
+}
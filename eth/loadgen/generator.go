@@ -0,0 +1,413 @@
+// Package loadgen replays recorded workloads against the network at a target
+// rate and measures how long their transactions take to be committed.
+package loadgen
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/common/hexutil"
+	"github.com/PlatONEnetwork/PlatONE-Go/core"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/event"
+	"github.com/PlatONEnetwork/PlatONE-Go/log"
+	"github.com/PlatONEnetwork/PlatONE-Go/params"
+	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
+	"github.com/PlatONEnetwork/PlatONE-Go/rpc"
+)
+
+const (
+	defaultWorkers = 8
+	defaultWait    = time.Minute
+	pacingInterval = 10 * time.Millisecond
+	submitTimeout  = 10 * time.Second
+	chainHeadSize  = 64
+)
+
+var (
+	ErrRunning    = errors.New("load generation already running")
+	ErrNotRunning = errors.New("load generation not running")
+	ErrInvalidTPS = errors.New("target TPS must be positive")
+)
+
+// Backend is the node the generator submits through and watches commits on.
+type Backend interface {
+	ChainConfig() *params.ChainConfig
+	SendTx(ctx context.Context, tx *types.Transaction) error
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+}
+
+// Config is a load generation run.
+type Config struct {
+	Workload string         `json:"workload"`          // File of the recorded workload
+	TPS      uint64         `json:"tps"`               // Transactions submitted per second
+	Count    uint64         `json:"count,omitempty"`   // Transactions to submit, the workload is replayed in a loop, defaults to its length
+	Target   string         `json:"target,omitempty"`  // RPC endpoint to submit to, empty to submit to the local pool which gossips over p2p
+	Workers  int            `json:"workers,omitempty"` // Concurrent submissions, defaults to 8
+	Wait     hexutil.Uint64 `json:"wait,omitempty"`    // Seconds to wait for commits after the last submission, defaults to 60
+}
+
+// Latency holds percentiles of the delays from submission to commit, in
+// milliseconds.
+type Latency struct {
+	P50 float64 `json:"p50"`
+	P90 float64 `json:"p90"`
+	P99 float64 `json:"p99"`
+	Max float64 `json:"max"`
+}
+
+// Report is the progress of a run.
+type Report struct {
+	Running   bool    `json:"running"`
+	Submitted uint64  `json:"submitted"`
+	Rejected  uint64  `json:"rejected"`  // Submissions refused by the node
+	Dropped   uint64  `json:"dropped"`   // Transactions not submitted for the workers falling behind
+	Committed uint64  `json:"committed"` // Submitted transactions found in blocks
+	Elapsed   float64 `json:"elapsed"`   // Seconds since the start of the run
+	TPS       float64 `json:"tps"`       // Committed transactions per second
+	Latency   Latency `json:"latency"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Generator submits the transactions of workloads signed by its key, one run
+// at a time.
+type Generator struct {
+	backend Backend
+	key     *ecdsa.PrivateKey
+	signer  types.Signer
+	nonce   uint64 // Nonces only keep the hashes unique, they are not sequential
+
+	mu   sync.Mutex
+	run  *run
+	last Report
+}
+
+// New creates a generator submitting transactions signed by key.
+func New(backend Backend, key *ecdsa.PrivateKey) *Generator {
+	return &Generator{
+		backend: backend,
+		key:     key,
+		signer:  types.NewEIP155Signer(backend.ChainConfig().ChainID),
+		nonce:   uint64(time.Now().UnixNano()),
+	}
+}
+
+// run is the state of a load generation in progress.
+type run struct {
+	config   Config
+	workload Workload
+	submit   func(context.Context, *types.Transaction) error
+	start    time.Time
+	quit     chan struct{}
+	done     chan struct{}
+
+	mu        sync.Mutex
+	pending   map[common.Hash]time.Time // Submission times of the uncommitted transactions
+	latencies []time.Duration
+	submitted uint64
+	rejected  uint64
+	dropped   uint64
+	err       error
+}
+
+// Start begins replaying the workload of config.
+func (g *Generator) Start(config Config) error {
+	if config.TPS == 0 {
+		return ErrInvalidTPS
+	}
+	workload, err := LoadWorkload(config.Workload)
+	if err != nil {
+		return err
+	}
+	if config.Count == 0 {
+		config.Count = uint64(len(workload))
+	}
+	if config.Workers <= 0 {
+		config.Workers = defaultWorkers
+	}
+	if config.Wait == 0 {
+		config.Wait = hexutil.Uint64(defaultWait / time.Second)
+	}
+	submit := g.backend.SendTx
+	var client *rpc.Client
+	if config.Target != "" {
+		if client, err = rpc.Dial(config.Target); err != nil {
+			return err
+		}
+		submit = func(ctx context.Context, tx *types.Transaction) error {
+			data, err := rlp.EncodeToBytes(tx)
+			if err != nil {
+				return err
+			}
+			return client.CallContext(ctx, nil, "eth_sendRawTransaction", hexutil.Bytes(data))
+		}
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.run != nil {
+		if client != nil {
+			client.Close()
+		}
+		return ErrRunning
+	}
+	r := &run{
+		config:   config,
+		workload: workload,
+		submit:   submit,
+		start:    time.Now(),
+		quit:     make(chan struct{}),
+		done:     make(chan struct{}),
+		pending:  make(map[common.Hash]time.Time),
+	}
+	g.run = r
+	go func() {
+		g.loop(r)
+		if client != nil {
+			client.Close()
+		}
+		g.mu.Lock()
+		g.last = r.report(false)
+		g.run = nil
+		g.mu.Unlock()
+		close(r.done)
+	}()
+	log.Info("Started load generation", "workload", config.Workload, "tps", config.TPS, "count", config.Count, "target", config.Target)
+	return nil
+}
+
+// Stop interrupts the run in progress and waits for it to end.
+func (g *Generator) Stop() error {
+	g.mu.Lock()
+	r := g.run
+	g.mu.Unlock()
+	if r == nil {
+		return ErrNotRunning
+	}
+	select {
+	case <-r.quit:
+	default:
+		close(r.quit)
+	}
+	<-r.done
+	return nil
+}
+
+// Report returns the progress of the run in progress, or the outcome of the
+// last one.
+func (g *Generator) Report() Report {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.run != nil {
+		return g.run.report(true)
+	}
+	return g.last
+}
+
+// loop paces the submissions of r and collects their commits until all are
+// committed, the wait after the last submission expires or r is stopped.
+func (g *Generator) loop(r *run) {
+	heads := make(chan core.ChainHeadEvent, chainHeadSize)
+	sub := g.backend.SubscribeChainHeadEvent(heads)
+	defer sub.Unsubscribe()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	txs := make(chan *types.Transaction, 16*r.config.Workers)
+	var workers sync.WaitGroup
+	for i := 0; i < r.config.Workers; i++ {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for tx := range txs {
+				r.send(ctx, tx)
+			}
+		}()
+	}
+	pacing := time.NewTicker(pacingInterval)
+
+	var (
+		queued   uint64
+		finished bool
+		sent     = make(chan struct{}) // Closed once the queued transactions are submitted
+		allSent  <-chan struct{}       // sent once there is nothing left to queue
+		wait     <-chan time.Time      // Fires when it is time to give up on commits
+	)
+	// Stop queueing transactions, the workers exit after submitting the queued ones
+	finish := func() {
+		if !finished {
+			finished = true
+			pacing.Stop()
+			close(txs)
+			go func() {
+				workers.Wait()
+				close(sent)
+			}()
+			allSent = sent
+		}
+	}
+	defer func() {
+		finish()
+		cancel()
+		<-sent
+	}()
+
+	for {
+		select {
+		case <-pacing.C:
+			due := uint64(time.Since(r.start).Seconds() * float64(r.config.TPS))
+			for ; queued < due && queued < r.config.Count; queued++ {
+				tx, err := g.next(r.workload[queued%uint64(len(r.workload))])
+				if err != nil {
+					r.fail(err)
+					return
+				}
+				// Drop the transaction rather than fall behind on the heads
+				select {
+				case txs <- tx:
+				default:
+					r.mu.Lock()
+					r.dropped++
+					r.mu.Unlock()
+				}
+			}
+			if queued == r.config.Count {
+				finish()
+			}
+
+		case <-allSent:
+			if r.uncommitted() == 0 {
+				return
+			}
+			allSent, wait = nil, time.After(time.Duration(r.config.Wait)*time.Second)
+
+		case ev := <-heads:
+			if r.commit(ev.Block) == 0 && wait != nil {
+				return
+			}
+
+		case <-wait:
+			log.Warn("Stopped waiting for load generation commits", "uncommitted", r.uncommitted())
+			return
+
+		case <-r.quit:
+			return
+
+		case err := <-sub.Err():
+			r.fail(err)
+			return
+		}
+	}
+}
+
+// next signs the transaction of step with a fresh nonce.
+func (g *Generator) next(step *Step) (*types.Transaction, error) {
+	tx, err := step.Transaction(g.nonce)
+	if err != nil {
+		return nil, err
+	}
+	g.nonce++
+	return types.SignTx(tx, g.signer, g.key)
+}
+
+// send submits tx and records when.
+func (r *run) send(ctx context.Context, tx *types.Transaction) {
+	if ctx.Err() != nil {
+		return // Stopped with transactions still queued
+	}
+	now := time.Now()
+	r.mu.Lock()
+	r.pending[tx.Hash()] = now
+	r.submitted++
+	r.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(ctx, submitTimeout)
+	defer cancel()
+	if err := r.submit(ctx, tx); err != nil {
+		log.Debug("Load generation transaction rejected", "hash", tx.Hash(), "err", err)
+		r.mu.Lock()
+		delete(r.pending, tx.Hash())
+		r.rejected++
+		r.mu.Unlock()
+	}
+}
+
+// commit records the latencies of the transactions of r in block, and
+// returns the number of submitted transactions left uncommitted.
+func (r *run) commit(block *types.Block) int {
+	if block == nil {
+		return r.uncommitted()
+	}
+	now := time.Now()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, tx := range block.Transactions() {
+		if submitted, ok := r.pending[tx.Hash()]; ok {
+			r.latencies = append(r.latencies, now.Sub(submitted))
+			delete(r.pending, tx.Hash())
+		}
+	}
+	return len(r.pending)
+}
+
+// uncommitted returns the number of submitted transactions not committed yet.
+func (r *run) uncommitted() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return len(r.pending)
+}
+
+// fail ends r with err.
+func (r *run) fail(err error) {
+	log.Warn("Load generation failed", "err", err)
+	r.mu.Lock()
+	r.err = err
+	r.mu.Unlock()
+}
+
+func (r *run) report(running bool) Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	elapsed := time.Since(r.start).Seconds()
+	report := Report{
+		Running:   running,
+		Submitted: r.submitted,
+		Rejected:  r.rejected,
+		Dropped:   r.dropped,
+		Committed: uint64(len(r.latencies)),
+		Elapsed:   elapsed,
+		TPS:       float64(len(r.latencies)) / elapsed,
+		Latency:   percentiles(r.latencies),
+	}
+	if r.err != nil {
+		report.Error = r.err.Error()
+	}
+	return report
+}
+
+// percentiles computes the latency percentiles of the given delays.
+func percentiles(delays []time.Duration) Latency {
+	if len(delays) == 0 {
+		return Latency{}
+	}
+	sorted := make([]time.Duration, len(delays))
+	copy(sorted, delays)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	at := func(p float64) float64 {
+		i := int(p*float64(len(sorted))+0.5) - 1
+		if i < 0 {
+			i = 0
+		}
+		return float64(sorted[i]) / float64(time.Millisecond)
+	}
+	return Latency{P50: at(0.50), P90: at(0.90), P99: at(0.99), Max: at(1)}
+}
@@ -0,0 +1,186 @@
+package loadgen
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/common/syscontracts"
+	"github.com/PlatONEnetwork/PlatONE-Go/core"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/crypto"
+	"github.com/PlatONEnetwork/PlatONE-Go/event"
+	"github.com/PlatONEnetwork/PlatONE-Go/params"
+	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
+)
+
+const testWorkload = `# recorded workload
+{"kind": "deploy", "code": "0x0061736d", "abi": [{"name": "setName"}]}
+{"kind": "invoke", "to": "0x2124e0d7392683a9fac7167e30da82858bd0f514", "method": "setName", "params": [{"type": "string", "value": "a"}, {"type": "int32", "value": "7"}]}
+
+{"kind": "cns", "name": "demo", "method": "getName"}
+{"kind": "transfer", "to": "0x2124e0d7392683a9fac7167e30da82858bd0f514", "value": "0x1"}
+`
+
+// testBackend accepts all transactions and packs the submitted ones in a
+// block whenever seal is called. A stuck backend never answers submissions.
+type testBackend struct {
+	mu    sync.Mutex
+	txs   types.Transactions
+	heads event.Feed
+	stuck bool
+}
+
+func (b *testBackend) ChainConfig() *params.ChainConfig {
+	return &params.ChainConfig{ChainID: big.NewInt(1)}
+}
+
+func (b *testBackend) SendTx(ctx context.Context, tx *types.Transaction) error {
+	if b.stuck {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.txs = append(b.txs, tx)
+	return nil
+}
+
+func (b *testBackend) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return b.heads.Subscribe(ch)
+}
+
+func (b *testBackend) seal() {
+	b.mu.Lock()
+	txs := b.txs
+	b.txs = nil
+	b.mu.Unlock()
+
+	b.heads.Send(core.ChainHeadEvent{Block: types.NewBlock(&types.Header{Number: big.NewInt(1)}, txs, nil)})
+}
+
+func writeWorkload(t *testing.T) (string, func()) {
+	dir, err := ioutil.TempDir("", "loadgen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "workload.jsonl")
+	if err := ioutil.WriteFile(path, []byte(testWorkload), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path, func() { os.RemoveAll(dir) }
+}
+
+// Tests that recorded steps are turned into the payloads of their kind.
+func TestLoadWorkload(t *testing.T) {
+	path, cleanup := writeWorkload(t)
+	defer cleanup()
+
+	workload, err := LoadWorkload(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(workload) != 4 {
+		t.Fatalf("loaded %d steps, want 4", len(workload))
+	}
+	var fields [][]byte
+
+	deploy, _ := workload[0].Transaction(0)
+	if deploy.To() != nil {
+		t.Fatalf("deployment sent to %x", deploy.To())
+	}
+	if err := rlp.DecodeBytes(deploy.Data(), &fields); err != nil || len(fields) != 3 || string(fields[2]) != `[{"name": "setName"}]` {
+		t.Fatalf("deployment fields %q, err %v", fields, err)
+	}
+
+	invoke, _ := workload[1].Transaction(0)
+	if err := rlp.DecodeBytes(invoke.Data(), &fields); err != nil || len(fields) != 4 || string(fields[1]) != "setName" || string(fields[3]) != string(common.Int32ToBytes(7)) {
+		t.Fatalf("invocation fields %q, err %v", fields, err)
+	}
+
+	cns, _ := workload[2].Transaction(0)
+	if *cns.To() != syscontracts.CnsInvokeAddress || cns.Type() != types.CnsTxType {
+		t.Fatalf("CNS invocation sent to %x with type %d", cns.To(), cns.Type())
+	}
+	if err := rlp.DecodeBytes(cns.Data(), &fields); err != nil || len(fields) != 3 || string(fields[1]) != "demo" || string(fields[2]) != "getName" {
+		t.Fatalf("CNS invocation fields %q, err %v", fields, err)
+	}
+
+	transfer, _ := workload[3].Transaction(0)
+	if transfer.Value().Cmp(big.NewInt(1)) != 0 || len(transfer.Data()) != 0 {
+		t.Fatalf("transfer of %v with data %x", transfer.Value(), transfer.Data())
+	}
+}
+
+// Tests that a run submits the requested transactions at the target rate and
+// measures their commits.
+func TestGenerator(t *testing.T) {
+	path, cleanup := writeWorkload(t)
+	defer cleanup()
+
+	key, _ := crypto.GenerateKey()
+	backend := new(testBackend)
+	g := New(backend, key)
+	if err := g.Start(Config{Workload: path, TPS: 200, Count: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.Start(Config{Workload: path, TPS: 200}); err != ErrRunning {
+		t.Fatalf("second start error %v, want %v", err, ErrRunning)
+	}
+	start := time.Now()
+	for g.Report().Running {
+		if time.Since(start) > 5*time.Second {
+			t.Fatal("run did not end")
+		}
+		time.Sleep(20 * time.Millisecond)
+		backend.seal()
+	}
+	report := g.Report()
+	if report.Submitted != 10 || report.Committed != 10 || report.Rejected != 0 {
+		t.Fatalf("report %+v, want 10 submitted and committed transactions", report)
+	}
+	if report.Elapsed < 0.04 {
+		t.Fatalf("10 transactions at 200 TPS submitted in %vs", report.Elapsed)
+	}
+	if report.Latency.P50 <= 0 || report.Latency.P50 > report.Latency.P99 || report.Latency.P99 > report.Latency.Max {
+		t.Fatalf("latency percentiles %+v", report.Latency)
+	}
+	if err := g.Stop(); err != ErrNotRunning {
+		t.Fatalf("stop error %v, want %v", err, ErrNotRunning)
+	}
+}
+
+// Tests that a run keeps pacing and stops promptly when the node doesn't answer
+// its submissions, dropping the transactions the workers can't take.
+func TestGeneratorStuckBackend(t *testing.T) {
+	path, cleanup := writeWorkload(t)
+	defer cleanup()
+
+	key, _ := crypto.GenerateKey()
+	g := New(&testBackend{stuck: true}, key)
+	if err := g.Start(Config{Workload: path, TPS: 10000, Count: 1000, Workers: 1}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	stopped := make(chan error)
+	go func() { stopped <- g.Stop() }()
+	select {
+	case err := <-stopped:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("stop blocked by the stuck submissions")
+	}
+	if report := g.Report(); report.Dropped == 0 {
+		t.Fatalf("report %+v, want dropped transactions", report)
+	}
+}
@@ -0,0 +1,205 @@
+package loadgen
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/common/hexutil"
+	"github.com/PlatONEnetwork/PlatONE-Go/common/syscontracts"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
+)
+
+// Kinds of workload steps.
+const (
+	KindTransfer = "transfer" // Value transfer to an account
+	KindDeploy   = "deploy"   // WASM contract deployment
+	KindInvoke   = "invoke"   // WASM contract invocation by address
+	KindCns      = "cns"      // WASM contract invocation by CNS name
+)
+
+// Transaction types of the WASM payloads.
+const (
+	deployContract int64 = 1
+	invokeContract int64 = 2
+)
+
+const defaultGas uint64 = 10000000
+
+var ErrEmptyWorkload = errors.New("workload without steps")
+
+// Param is a parameter of a contract method, with its ABI type.
+type Param struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// Step is a transaction of a recorded workload.
+type Step struct {
+	Kind   string          `json:"kind"`
+	To     *common.Address `json:"to,omitempty"`    // Recipient of transfers and invocations
+	Name   string          `json:"name,omitempty"`  // CNS name of the invoked contract
+	Value  *hexutil.Big    `json:"value,omitempty"` // Value of transfers
+	Code   hexutil.Bytes   `json:"code,omitempty"`  // Code of deployments
+	ABI    json.RawMessage `json:"abi,omitempty"`   // ABI of deployments
+	Method string          `json:"method,omitempty"`
+	Params []Param         `json:"params,omitempty"`
+	Gas    uint64          `json:"gas,omitempty"` // Defaults to 10M
+}
+
+// Workload is a sequence of transactions replayed by the generator.
+type Workload []*Step
+
+// LoadWorkload reads a workload from a file holding a JSON step per line.
+// Empty lines and lines starting with # are skipped.
+func LoadWorkload(path string) (Workload, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var workload Workload
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, 16*1024*1024) // Deployments carry whole contracts
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		step := new(Step)
+		if err := json.Unmarshal([]byte(text), step); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		if _, _, _, err := step.payload(); err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		workload = append(workload, step)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(workload) == 0 {
+		return nil, ErrEmptyWorkload
+	}
+	return workload, nil
+}
+
+// Transaction creates the unsigned transaction of the step.
+func (s *Step) Transaction(nonce uint64) (*types.Transaction, error) {
+	txType, to, data, err := s.payload()
+	if err != nil {
+		return nil, err
+	}
+	value := new(big.Int)
+	if s.Value != nil {
+		value = s.Value.ToInt()
+	}
+	gas := s.Gas
+	if gas == 0 {
+		gas = defaultGas
+	}
+	if to == nil {
+		return types.NewContractCreation(nonce, value, gas, new(big.Int), data), nil
+	}
+	return types.NewTransaction(nonce, *to, value, gas, new(big.Int), data, txType), nil
+}
+
+// payload returns the type, recipient and data of the transaction of the step.
+func (s *Step) payload() (uint64, *common.Address, []byte, error) {
+	switch s.Kind {
+	case KindTransfer:
+		if s.To == nil {
+			return 0, nil, nil, errors.New("transfer without recipient")
+		}
+		return types.NormalTxType, s.To, nil, nil
+
+	case KindDeploy:
+		if len(s.Code) == 0 {
+			return 0, nil, nil, errors.New("deployment without code")
+		}
+		data, err := rlp.EncodeToBytes([][]byte{common.Int64ToBytes(deployContract), s.Code, s.ABI})
+		return types.CreateTxType, nil, data, err
+
+	case KindInvoke:
+		if s.To == nil {
+			return 0, nil, nil, errors.New("invocation without contract")
+		}
+		fields, err := s.call()
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		data, err := rlp.EncodeToBytes(fields)
+		return types.NormalTxType, s.To, data, err
+
+	case KindCns:
+		if s.Name == "" {
+			return 0, nil, nil, errors.New("CNS invocation without name")
+		}
+		fields, err := s.call()
+		if err != nil {
+			return 0, nil, nil, err
+		}
+		// The CNS name goes between the type and the method
+		fields = append(fields[:1], append([][]byte{[]byte(s.Name)}, fields[1:]...)...)
+		data, err := rlp.EncodeToBytes(fields)
+		to := syscontracts.CnsInvokeAddress
+		return types.CnsTxType, &to, data, err
+	}
+	return 0, nil, nil, fmt.Errorf("unknown step kind %q", s.Kind)
+}
+
+// call returns the fields of the WASM invocation of the step.
+func (s *Step) call() ([][]byte, error) {
+	if s.Method == "" {
+		return nil, errors.New("invocation without method")
+	}
+	fields := [][]byte{common.Int64ToBytes(invokeContract), []byte(s.Method)}
+	for i, param := range s.Params {
+		enc, err := encodeParam(param)
+		if err != nil {
+			return nil, fmt.Errorf("param %d: %v", i, err)
+		}
+		fields = append(fields, enc)
+	}
+	return fields, nil
+}
+
+// encodeParam encodes a parameter the way the WASM contracts decode its type.
+func encodeParam(p Param) ([]byte, error) {
+	switch p.Type {
+	case "int32", "uint32", "int", "uint":
+		n, err := strconv.ParseInt(p.Value, 10, 64)
+		return common.Int32ToBytes(int32(n)), err
+	case "int64", "uint64":
+		n, err := strconv.ParseInt(p.Value, 10, 64)
+		return common.Int64ToBytes(n), err
+	case "int128", "uint128":
+		n, ok := new(big.Int).SetString(p.Value, 10)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s %q", p.Type, p.Value)
+		}
+		enc, ok := common.BigToByte128(n)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s %q", p.Type, p.Value)
+		}
+		return enc, nil
+	case "float32":
+		f, err := strconv.ParseFloat(p.Value, 32)
+		return common.Float32ToBytes(float32(f)), err
+	case "float64":
+		f, err := strconv.ParseFloat(p.Value, 64)
+		return common.Float64ToBytes(f), err
+	case "bool":
+		b, err := strconv.ParseBool(p.Value)
+		return common.BoolToBytes(b), err
+	}
+	return []byte(p.Value), nil
+}
@@ -0,0 +1,104 @@
+package ethdb
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Storage engines a persistent database can be backed by.
+const (
+	EngineLevelDB = "leveldb"
+	EnginePebble  = "pebble"
+)
+
+// DefaultEngine is the engine of the databases created without an explicit one.
+const DefaultEngine = EngineLevelDB
+
+// Compacter is implemented by the databases able to flatten a key range,
+// reclaiming the space of the deleted and overwritten entries.
+type Compacter interface {
+	// Compact flattens the key range [start, limit), the whole database for
+	// nil bounds.
+	Compact(start, limit []byte) error
+}
+
+// IsEngine reports whether name is a supported storage engine.
+func IsEngine(name string) bool {
+	return name == EngineLevelDB || name == EnginePebble
+}
+
+// DetectEngine returns the engine of the database in directory file, or an
+// empty string when there is none yet.
+func DetectEngine(file string) string {
+	if _, err := os.Stat(filepath.Join(file, "CURRENT")); err != nil {
+		return ""
+	}
+	// Unlike LevelDB, Pebble always persists its options next to the manifest
+	entries, err := ioutil.ReadDir(file)
+	if err != nil {
+		return ""
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), "OPTIONS-") {
+			return EnginePebble
+		}
+	}
+	return EngineLevelDB
+}
+
+// Open opens the database in directory file with the given engine, creating
+// it if needed. An empty engine opens an existing database with the engine it
+// was created with, and creates new ones with DefaultEngine. Opening an
+// existing database with another engine fails.
+func Open(engine string, file string, cache int, handles int) (Database, error) {
+	existing := DetectEngine(file)
+	switch {
+	case engine == "" && existing == "":
+		engine = DefaultEngine
+	case engine == "":
+		engine = existing
+	case existing != "" && existing != engine:
+		return nil, fmt.Errorf("database %s is stored with %s, not %s", file, existing, engine)
+	}
+	switch engine {
+	case EngineLevelDB:
+		return NewLDBDatabase(file, cache, handles)
+	case EnginePebble:
+		return NewPebbleDatabase(file, cache, handles)
+	}
+	return nil, fmt.Errorf("unknown database engine %q", engine)
+}
+
+// Copy copies all the entries of src to dst, reporting the number of entries
+// copied after every batch to progress if not nil.
+func Copy(dst Database, src Iteratee, progress func(entries int)) (int, error) {
+	it := src.NewIteratorWithPrefix(nil)
+	defer it.Release()
+
+	var (
+		batch   = dst.NewBatch()
+		entries = 0
+	)
+	for it.Next() {
+		if err := batch.Put(it.Key(), it.Value()); err != nil {
+			return entries, err
+		}
+		entries++
+		if batch.ValueSize() >= IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return entries, err
+			}
+			batch.Reset()
+			if progress != nil {
+				progress(entries)
+			}
+		}
+	}
+	if err := it.Error(); err != nil {
+		return entries, err
+	}
+	return entries, batch.Write()
+}
@@ -0,0 +1,89 @@
+package ethdb_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
+)
+
+func TestPebbleIteration(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ethdb_engine_test_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	db, err := ethdb.Open(ethdb.EnginePebble, dir, 0, 0)
+	if err != nil {
+		t.Fatalf("failed to open pebble database: %v", err)
+	}
+	defer db.Close()
+
+	for _, key := range []string{"b2", "a1", "b1", "c1"} {
+		if err := db.Put([]byte(key), []byte("v"+key)); err != nil {
+			t.Fatalf("put %s failed: %v", key, err)
+		}
+	}
+	if value, err := db.Get([]byte("a1")); err != nil || !bytes.Equal(value, []byte("va1")) {
+		t.Fatalf("get mismatch: have %q, %v", value, err)
+	}
+	if ok, _ := db.Has([]byte("a2")); ok {
+		t.Fatal("missing key reported present")
+	}
+	it := db.(ethdb.Iteratee).NewIteratorWithPrefix([]byte("b"))
+	defer it.Release()
+
+	var keys []string
+	for it.Next() {
+		keys = append(keys, string(it.Key()))
+	}
+	if len(keys) != 2 || keys[0] != "b1" || keys[1] != "b2" {
+		t.Fatalf("prefix iteration mismatch: have %v, want [b1 b2]", keys)
+	}
+	if engine := ethdb.DetectEngine(dir); engine != ethdb.EnginePebble {
+		t.Fatalf("engine mismatch: have %q, want %q", engine, ethdb.EnginePebble)
+	}
+}
+
+func TestCopyAcrossEngines(t *testing.T) {
+	dir, err := ioutil.TempDir("", "ethdb_engine_test_")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	src, err := ethdb.Open(ethdb.EngineLevelDB, filepath.Join(dir, "src"), 0, 0)
+	if err != nil {
+		t.Fatalf("failed to open source database: %v", err)
+	}
+	defer src.Close()
+	for i := byte(0); i < 100; i++ {
+		src.Put([]byte{i}, []byte{i, i})
+	}
+	dst, err := ethdb.Open(ethdb.EnginePebble, filepath.Join(dir, "dst"), 0, 0)
+	if err != nil {
+		t.Fatalf("failed to open destination database: %v", err)
+	}
+	defer dst.Close()
+
+	entries, err := ethdb.Copy(dst, src.(ethdb.Iteratee), nil)
+	if err != nil {
+		t.Fatalf("copy failed: %v", err)
+	}
+	if entries != 100 {
+		t.Fatalf("copied entries mismatch: have %d, want 100", entries)
+	}
+	for i := byte(0); i < 100; i++ {
+		if value, err := dst.Get([]byte{i}); err != nil || !bytes.Equal(value, []byte{i, i}) {
+			t.Fatalf("entry %d mismatch: have %x, %v", i, value, err)
+		}
+	}
+	// Reopening an existing database with another engine must be refused
+	if _, err := ethdb.Open(ethdb.EngineLevelDB, filepath.Join(dir, "dst"), 0, 0); err == nil {
+		t.Fatal("opened a pebble database with leveldb")
+	}
+}
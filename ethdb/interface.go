@@ -40,6 +40,23 @@ type Database interface {
 	NewBatch() Batch
 }
 
+// Iterator iterates over the entries of a database in ascending key order.
+// The key and value returned are only valid until the next call to Next.
+type Iterator interface {
+	Next() bool
+	Key() []byte
+	Value() []byte
+	Error() error
+	Release()
+}
+
+// Iteratee is implemented by the databases able to iterate over their content.
+type Iteratee interface {
+	// NewIteratorWithPrefix returns an iterator over the entries whose key
+	// starts with prefix, all of them for a nil prefix.
+	NewIteratorWithPrefix(prefix []byte) Iterator
+}
+
 // Batch is a write-only database that commits changes to its host database
 // when Write is called. Batch cannot be used concurrently.
 type Batch interface {
@@ -0,0 +1,182 @@
+package ethdb
+
+import (
+	"github.com/PlatONEnetwork/PlatONE-Go/log"
+	"github.com/cockroachdb/pebble"
+	"github.com/cockroachdb/pebble/bloom"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// PebbleDatabase is a Database backed by Pebble.
+type PebbleDatabase struct {
+	fn string     // filename for reporting
+	db *pebble.DB // Pebble instance
+
+	log log.Logger // Contextual logger tracking the database path
+}
+
+// NewPebbleDatabase returns a Pebble wrapped object.
+func NewPebbleDatabase(file string, cache int, handles int) (*PebbleDatabase, error) {
+	logger := log.New("database", file)
+
+	// Ensure we have some minimal caching and file guarantees
+	if cache < 16 {
+		cache = 16
+	}
+	if handles < 16 {
+		handles = 16
+	}
+	logger.Info("Allocated cache and file handles", "engine", EnginePebble, "cache", cache, "handles", handles)
+
+	blockCache := pebble.NewCache(int64(cache/2) * 1024 * 1024)
+	defer blockCache.Unref()
+
+	db, err := pebble.Open(file, &pebble.Options{
+		Cache:        blockCache,
+		MaxOpenFiles: handles,
+		MemTableSize: cache / 4 * 1024 * 1024, // Two of these are used internally
+		Levels: []pebble.LevelOptions{
+			{FilterPolicy: bloom.FilterPolicy(10)},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &PebbleDatabase{
+		fn:  file,
+		db:  db,
+		log: logger,
+	}, nil
+}
+
+// Path returns the path to the database directory.
+func (db *PebbleDatabase) Path() string {
+	return db.fn
+}
+
+// Put puts the given key / value to the queue
+func (db *PebbleDatabase) Put(key []byte, value []byte) error {
+	return db.db.Set(key, value, pebble.NoSync)
+}
+
+func (db *PebbleDatabase) Has(key []byte) (bool, error) {
+	_, closer, err := db.db.Get(key)
+	if err == pebble.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	closer.Close()
+	return true, nil
+}
+
+// Get returns the given key if it's present.
+func (db *PebbleDatabase) Get(key []byte) ([]byte, error) {
+	dat, closer, err := db.db.Get(key)
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	// The value is only valid until the closer is called
+	return append([]byte{}, dat...), nil
+}
+
+// Delete deletes the key from the queue and database
+func (db *PebbleDatabase) Delete(key []byte) error {
+	return db.db.Delete(key, pebble.NoSync)
+}
+
+// NewIteratorWithPrefix returns a iterator to iterate over subset of database content with a particular prefix.
+func (db *PebbleDatabase) NewIteratorWithPrefix(prefix []byte) Iterator {
+	r := util.BytesPrefix(prefix)
+	return &pebbleIterator{iter: db.db.NewIter(&pebble.IterOptions{
+		LowerBound: r.Start,
+		UpperBound: r.Limit,
+	})}
+}
+
+// Compact flattens the key range [start, limit), the whole database for nil
+// bounds.
+func (db *PebbleDatabase) Compact(start, limit []byte) error {
+	if limit == nil {
+		// Pebble needs an upper bound, past any key of the database
+		limit = []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}
+	}
+	return db.db.Compact(start, limit)
+}
+
+func (db *PebbleDatabase) Close() {
+	if err := db.db.Close(); err == nil {
+		db.log.Info("Database closed")
+	} else {
+		db.log.Error("Failed to close database", "err", err)
+	}
+}
+
+func (db *PebbleDatabase) NewBatch() Batch {
+	return &pebbleBatch{db: db.db, b: db.db.NewBatch()}
+}
+
+type pebbleBatch struct {
+	db   *pebble.DB
+	b    *pebble.Batch
+	size int
+}
+
+func (b *pebbleBatch) Put(key, value []byte) error {
+	b.b.Set(key, value, nil)
+	b.size += len(value)
+	return nil
+}
+
+func (b *pebbleBatch) Delete(key []byte) error {
+	b.b.Delete(key, nil)
+	b.size += 1
+	return nil
+}
+
+func (b *pebbleBatch) Write() error {
+	return b.b.Commit(pebble.NoSync)
+}
+
+func (b *pebbleBatch) ValueSize() int {
+	return b.size
+}
+
+func (b *pebbleBatch) Reset() {
+	b.b.Reset()
+	b.size = 0
+}
+
+// pebbleIterator adapts the Pebble iterator, positioned by First instead of
+// the first Next, to Iterator.
+type pebbleIterator struct {
+	iter  *pebble.Iterator
+	moved bool
+}
+
+func (it *pebbleIterator) Next() bool {
+	if !it.moved {
+		it.moved = true
+		return it.iter.First()
+	}
+	return it.iter.Next()
+}
+
+func (it *pebbleIterator) Key() []byte {
+	return it.iter.Key()
+}
+
+func (it *pebbleIterator) Value() []byte {
+	return it.iter.Value()
+}
+
+func (it *pebbleIterator) Error() error {
+	return it.iter.Error()
+}
+
+func (it *pebbleIterator) Release() {
+	it.iter.Close()
+}
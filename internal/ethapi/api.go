@@ -39,6 +39,7 @@ import (
 	"github.com/PlatONEnetwork/PlatONE-Go/log"
 	"github.com/PlatONEnetwork/PlatONE-Go/p2p"
 	"github.com/PlatONEnetwork/PlatONE-Go/params"
+	"github.com/PlatONEnetwork/PlatONE-Go/proof"
 	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
 	"github.com/PlatONEnetwork/PlatONE-Go/rpc"
 	"github.com/davecgh/go-spew/spew"
@@ -182,6 +183,49 @@ func (s *PublicTxPoolAPI) Inspect() map[string]map[string]map[string]string {
 	return content
 }
 
+// Lifecycle returns the steps the transaction with the given hash went through
+// since it reached the pool, nil if the pool doesn't track it.
+func (s *PublicTxPoolAPI) Lifecycle(hash common.Hash) []core.TxEvent {
+	return s.b.TxLifecycle(hash)
+}
+
+// LifecycleEvents notifies the steps transactions go through from now on, of
+// the given transactions only unless hashes is empty.
+func (s *PublicTxPoolAPI) LifecycleEvents(ctx context.Context, hashes []common.Hash) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+	watched := make(map[common.Hash]struct{}, len(hashes))
+	for _, hash := range hashes {
+		watched[hash] = struct{}{}
+	}
+	rpcSub := notifier.CreateSubscription()
+
+	go func() {
+		events := make(chan core.TxLifecycleEvent, 128)
+		sub := s.b.SubscribeTxLifecycleEvent(events)
+		defer sub.Unsubscribe()
+
+		for {
+			select {
+			case ev := <-events:
+				for _, e := range ev.Events {
+					if _, ok := watched[e.Hash]; ok || len(watched) == 0 {
+						notifier.Notify(rpcSub.ID, e)
+					}
+				}
+			case <-rpcSub.Err():
+				return
+			case <-notifier.Closed():
+				return
+			}
+		}
+	}()
+
+	return rpcSub, nil
+}
+
 // PublicAccountAPI provides an API to access accounts managed by this node.
 // It offers only methods that can retrieve accounts.
 type PublicAccountAPI struct {
@@ -630,6 +674,55 @@ func (s *PublicBlockChainAPI) GetStorageAt(ctx context.Context, address common.A
 	return res[:], state.Error()
 }
 
+// GetProof returns the account of address and the values its contract set for
+// storageKeys, along with the trie nodes proving them against the state root of
+// the block. The storage keys are the keys as set by the contract.
+func (s *PublicBlockChainAPI) GetProof(ctx context.Context, address common.Address, storageKeys []hexutil.Bytes, blockNr rpc.BlockNumber) (*proof.AccountResult, error) {
+	state, _, err := s.b.StateAndHeaderByNumber(ctx, blockNr)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	accountProof, err := state.GetProof(address)
+	if err != nil {
+		return nil, err
+	}
+	result := &proof.AccountResult{
+		Address:      address,
+		AccountProof: toHexSlice(accountProof),
+		Balance:      (*hexutil.Big)(state.GetBalance(address)),
+		CodeHash:     state.GetCodeHash(address),
+		Nonce:        hexutil.Uint64(state.GetNonce(address)),
+		StorageHash:  types.EmptyRootHash,
+		StorageProof: make([]proof.StorageResult, len(storageKeys)),
+	}
+	storageTrie := state.StorageTrie(address)
+	if storageTrie != nil {
+		result.StorageHash = storageTrie.Hash()
+	}
+	for i, key := range storageKeys {
+		result.StorageProof[i] = proof.StorageResult{Key: key, Value: hexutil.Bytes{}, Proof: []hexutil.Bytes{}}
+		if storageTrie == nil {
+			continue
+		}
+		storageProof, err := state.GetStorageProof(address, key)
+		if err != nil {
+			return nil, err
+		}
+		result.StorageProof[i].Value = state.GetState(address, key)
+		result.StorageProof[i].Proof = toHexSlice(storageProof)
+	}
+	return result, state.Error()
+}
+
+// toHexSlice converts the nodes of a proof to their JSON representation.
+func toHexSlice(nodes [][]byte) []hexutil.Bytes {
+	result := make([]hexutil.Bytes, len(nodes))
+	for i, node := range nodes {
+		result[i] = node
+	}
+	return result
+}
+
 // CallArgs represents the arguments for a call.
 type CallArgs struct {
 	From     common.Address  `json:"from"`
@@ -700,9 +793,28 @@ func (s *PublicBlockChainAPI) doCall(ctx context.Context, args CallArgs, blockNr
 	if err := vmError(); err != nil {
 		return nil, 0, false, err
 	}
+	if err == nil && failed {
+		err = &revertError{reason: evm.Failure()}
+	}
 	return res, gas, failed, err
 }
 
+// revertError is the error of a call which failed to execute, it carries the
+// reason given by the reverting contract.
+type revertError struct {
+	reason string
+}
+
+// ErrorCode returns the json-rpc error code of a reverted call.
+func (e *revertError) ErrorCode() int { return 3 }
+
+func (e *revertError) Error() string {
+	if e.reason == "" {
+		return "execution failed"
+	}
+	return "execution reverted: " + e.reason
+}
+
 // Call executes the given transaction on the state for the given block number.
 // It doesn't make and changes in the state/blockchain and is useful to execute and retrieve values.
 func (s *PublicBlockChainAPI) Call(ctx context.Context, args CallArgs, blockNr rpc.BlockNumber) (hexutil.Bytes, error) {
@@ -878,21 +990,24 @@ func (s *PublicBlockChainAPI) rpcOutputBlock(b *types.Block, inclTx bool, fullTx
 
 // RPCTransaction represents a transaction that will serialize to the RPC representation of a transaction
 type RPCTransaction struct {
-	BlockHash        common.Hash     `json:"blockHash"`
-	BlockNumber      *hexutil.Big    `json:"blockNumber"`
-	From             common.Address  `json:"from"`
-	Gas              hexutil.Uint64  `json:"gas"`
-	GasPrice         *hexutil.Big    `json:"gasPrice"`
-	Hash             common.Hash     `json:"hash"`
-	Input            hexutil.Bytes   `json:"input"`
-	Nonce            hexutil.Uint64  `json:"nonce"`
-	To               *common.Address `json:"to"`
-	TransactionIndex hexutil.Uint    `json:"transactionIndex"`
-	Value            *hexutil.Big    `json:"value"`
-	V                *hexutil.Big    `json:"v"`
-	R                *hexutil.Big    `json:"r"`
-	S                *hexutil.Big    `json:"s"`
-	TxType           hexutil.Uint64  `json:"txType"`
+	BlockHash        common.Hash           `json:"blockHash"`
+	BlockNumber      *hexutil.Big          `json:"blockNumber"`
+	From             common.Address        `json:"from"`
+	Gas              hexutil.Uint64        `json:"gas"`
+	GasPrice         *hexutil.Big          `json:"gasPrice"`
+	Hash             common.Hash           `json:"hash"`
+	Input            hexutil.Bytes         `json:"input"`
+	Nonce            hexutil.Uint64        `json:"nonce"`
+	To               *common.Address       `json:"to"`
+	TransactionIndex hexutil.Uint          `json:"transactionIndex"`
+	Value            *hexutil.Big          `json:"value"`
+	V                *hexutil.Big          `json:"v"`
+	R                *hexutil.Big          `json:"r"`
+	S                *hexutil.Big          `json:"s"`
+	TxType           hexutil.Uint64        `json:"txType"`
+	ValidityWindow   *types.ValidityWindow `json:"validityWindow,omitempty"`
+	AccessList       types.AccessList      `json:"accessList,omitempty"`
+	FeePayer         *common.Address       `json:"feePayer,omitempty"`
 }
 
 // newRPCTransaction returns a transaction that will serialize to the RPC
@@ -919,6 +1034,9 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 		S:        (*hexutil.Big)(s),
 		TxType:   hexutil.Uint64(tx.Type()),
 	}
+	result.ValidityWindow = tx.ValidityWindow()
+	result.AccessList = tx.AccessList()
+	result.FeePayer = tx.FeePayerAddress()
 	if blockHash != (common.Hash{}) {
 		result.BlockHash = blockHash
 		result.BlockNumber = (*hexutil.Big)(new(big.Int).SetUint64(blockNumber))
@@ -1061,6 +1179,38 @@ func (s *PublicTransactionPoolAPI) GetRawTransactionByHash(ctx context.Context,
 	return rlp.EncodeToBytes(tx)
 }
 
+// GetTransactionProof returns the transaction with the given hash along with
+// the proof of its inclusion in its block.
+func (s *PublicTransactionPoolAPI) GetTransactionProof(ctx context.Context, hash common.Hash) (*proof.InclusionResult, error) {
+	tx, blockHash, _, index := rawdb.ReadTransaction(s.b.ChainDb(), hash)
+	if tx == nil {
+		return nil, nil
+	}
+	block, err := s.b.GetBlock(ctx, blockHash)
+	if block == nil || err != nil {
+		return nil, err
+	}
+	return proof.ProveTransaction(block.Header(), block.Transactions(), int(index))
+}
+
+// GetReceiptProof returns the receipt of the transaction with the given hash
+// along with the proof of its inclusion in its block.
+func (s *PublicTransactionPoolAPI) GetReceiptProof(ctx context.Context, hash common.Hash) (*proof.InclusionResult, error) {
+	tx, blockHash, _, index := rawdb.ReadTransaction(s.b.ChainDb(), hash)
+	if tx == nil {
+		return nil, nil
+	}
+	block, err := s.b.GetBlock(ctx, blockHash)
+	if block == nil || err != nil {
+		return nil, err
+	}
+	receipts, err := s.b.GetReceipts(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	return proof.ProveReceipt(block.Header(), receipts, int(index))
+}
+
 // GetTransactionReceipt returns the transaction receipt for the given transaction hash.
 func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
 	tx, blockHash, blockNumber, index := rawdb.ReadTransaction(s.b.ChainDb(), hash)
@@ -1109,6 +1259,30 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(ctx context.Context, ha
 	if receipt.ContractAddress != (common.Address{}) {
 		fields["contractAddress"] = receipt.ContractAddress
 	}
+	if receipt.RevertReason != "" {
+		fields["revertReason"] = receipt.RevertReason
+	}
+	if len(receipt.Calls) > 0 {
+		calls := make([]map[string]interface{}, len(receipt.Calls))
+		for i, call := range receipt.Calls {
+			logs := receipt.CallLogs(i)
+			if logs == nil {
+				logs = []*types.Log{}
+			}
+			calls[i] = map[string]interface{}{
+				"status":          hexutil.Uint(call.Status),
+				"contractAddress": nil,
+				"logs":            logs,
+			}
+			if call.ContractAddress != (common.Address{}) {
+				calls[i]["contractAddress"] = call.ContractAddress
+			}
+			if call.RevertReason != "" {
+				calls[i]["revertReason"] = call.RevertReason
+			}
+		}
+		fields["calls"] = calls
+	}
 	return fields, nil
 }
 
@@ -1126,15 +1300,6 @@ func (s *PublicTransactionPoolAPI) sign(addr common.Address, tx *types.Transacti
 	return wallet.SignTx(account, tx, chainID)
 }
 
-func (s *PublicTransactionPoolAPI) RunBenchmark(ctx context.Context, encodedTx hexutil.Bytes) {
-	log.Info("start run bench***************")
-	bm := new(types.Benchmark)
-	if err := rlp.DecodeBytes(encodedTx, bm); err != nil {
-		return
-	}
-	s.b.GenerateTxs(bm)
-}
-
 // SendTxArgs represents the arguments to sumbit a new transaction into the transaction pool.
 type SendTxArgs struct {
 	From     common.Address  `json:"from"`
@@ -1148,6 +1313,15 @@ type SendTxArgs struct {
 	Data   *hexutil.Bytes `json:"data"`
 	Input  *hexutil.Bytes `json:"input"`
 	TxType uint64         `json:"txType"`
+	// ValidityWindow optionally restricts the blocks the transaction can be
+	// included in.
+	ValidityWindow *types.ValidityWindow `json:"validityWindow"`
+	// AccessList optionally declares the accounts and storage the
+	// transaction accesses.
+	AccessList types.AccessList `json:"accessList"`
+	// FeePayer optionally declares the sponsor paying the fees, which has to
+	// sign the transaction after the sender, see SignFeePayer.
+	FeePayer *common.Address `json:"feePayer"`
 }
 
 // setDefaults is a helper function that fills in default values for unspecified tx fields.
@@ -1200,10 +1374,22 @@ func (args *SendTxArgs) toTransaction() *types.Transaction {
 	} else if args.Input != nil {
 		input = *args.Input
 	}
+	var tx *types.Transaction
 	if args.To == nil {
-		return types.NewContractCreation(uint64(*args.Nonce), (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input)
+		tx = types.NewContractCreation(uint64(*args.Nonce), (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input)
+	} else {
+		tx = types.NewTransaction(uint64(*args.Nonce), *args.To, (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input, args.TxType)
+	}
+	if args.ValidityWindow != nil {
+		tx = tx.WithValidityWindow(*args.ValidityWindow)
 	}
-	return types.NewTransaction(uint64(*args.Nonce), *args.To, (*big.Int)(args.Value), uint64(*args.Gas), (*big.Int)(args.GasPrice), input, args.TxType)
+	if args.AccessList != nil {
+		tx = tx.WithAccessList(args.AccessList)
+	}
+	if args.FeePayer != nil {
+		tx = tx.WithFeePayer(*args.FeePayer)
+	}
+	return tx
 }
 
 // submitTransaction is a helper function that submits tx to txPool and logs a message.
@@ -1339,6 +1525,38 @@ func (s *PublicTransactionPoolAPI) SignTransaction(ctx context.Context, args Sen
 	return &SignTransactionResult{data, tx}, nil
 }
 
+// SignFeePayer signs an RLP encoded transaction, already signed by its sender,
+// as its fee payer with the key of the declared sponsor, which has to be an
+// account of this node. It returns the transaction ready to be sent.
+func (s *PublicTransactionPoolAPI) SignFeePayer(ctx context.Context, encodedTx hexutil.Bytes) (*SignTransactionResult, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(encodedTx, tx); err != nil {
+		return nil, err
+	}
+	payer := tx.FeePayerAddress()
+	if payer == nil {
+		return nil, errors.New("transaction without fee payer")
+	}
+	account := accounts.Account{Address: *payer}
+	wallet, err := s.b.AccountManager().Find(account)
+	if err != nil {
+		return nil, err
+	}
+	hash := types.FeePayerHash(types.NewEIP155Signer(s.b.ChainConfig().ChainID), tx)
+	sig, err := wallet.SignHash(account, hash[:])
+	if err != nil {
+		return nil, err
+	}
+	if tx, err = tx.WithFeePayerSignature(sig); err != nil {
+		return nil, err
+	}
+	data, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return nil, err
+	}
+	return &SignTransactionResult{data, tx}, nil
+}
+
 // PendingTransactions returns the transactions that are in the transaction pool
 // and have a from address that is one of the accounts this node manages.
 func (s *PublicTransactionPoolAPI) PendingTransactions() ([]*RPCTransaction, error) {
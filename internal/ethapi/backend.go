@@ -65,10 +65,11 @@ type Backend interface {
 	Stats() (pending int, queued int)
 	TxPoolContent() (map[common.Address]types.Transactions, map[common.Address]types.Transactions)
 	SubscribeNewTxsEvent(chan<- core.NewTxsEvent) event.Subscription
+	TxLifecycle(hash common.Hash) []core.TxEvent
+	SubscribeTxLifecycleEvent(chan<- core.TxLifecycleEvent) event.Subscription
 
 	ChainConfig() *params.ChainConfig
 	CurrentBlock() *types.Block
-	GenerateTxs(benchmark *types.Benchmark)
 }
 
 func GetAPIs(apiBackend Backend) []rpc.API {
@@ -31,6 +31,7 @@ var Modules = map[string]string{
 	"swarmfs":    SWARMFS_JS,
 	"txpool":     TxPool_JS,
 	"istanbul":   Istanbul_JS,
+	"loadgen":    LoadGen_JS,
 }
 
 const Chequebook_JS = `
@@ -384,6 +385,11 @@ web3._extend({
 			call: 'debug_storageRangeAt',
 			params: 5,
 		}),
+		new web3._extend.Method({
+			name: 'wasmStorageRangeAt',
+			call: 'debug_wasmStorageRangeAt',
+			params: 6,
+		}),
 		new web3._extend.Method({
 			name: 'getModifiedAccountsByNumber',
 			call: 'debug_getModifiedAccountsByNumber',
@@ -434,6 +440,11 @@ web3._extend({
 			params: 1,
 			inputFormatter: [web3._extend.formatters.inputTransactionFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'signFeePayer',
+			call: 'eth_signFeePayer',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'submitTransaction',
 			call: 'eth_submitTransaction',
@@ -453,6 +464,22 @@ web3._extend({
 			params: 2,
 			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, web3._extend.utils.toHex]
 		}),
+		new web3._extend.Method({
+			name: 'getProof',
+			call: 'eth_getProof',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getTransactionProof',
+			call: 'eth_getTransactionProof',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getReceiptProof',
+			call: 'eth_getReceiptProof',
+			params: 1
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -635,7 +662,13 @@ web3._extend({
 const TxPool_JS = `
 web3._extend({
 	property: 'txpool',
-	methods: [],
+	methods: [
+		new web3._extend.Method({
+			name: 'lifecycle',
+			call: 'txpool_lifecycle',
+			params: 1
+		}),
+	],
 	properties:
 	[
 		new web3._extend.Property({
@@ -659,6 +692,29 @@ web3._extend({
 });
 `
 
+const LoadGen_JS = `
+web3._extend({
+	property: 'loadgen',
+	methods: [
+		new web3._extend.Method({
+			name: 'start',
+			call: 'loadgen_start',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'stop',
+			call: 'loadgen_stop'
+		}),
+	],
+	properties: [
+		new web3._extend.Property({
+			name: 'report',
+			getter: 'loadgen_report'
+		}),
+	]
+});
+`
+
 const Istanbul_JS = `
 web3._extend({
 	property: 'istanbul',
@@ -0,0 +1,193 @@
+package codec
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+)
+
+var testStructs = []StructDef{
+	{Name: "Person", Fields: []FieldDef{{"name", "string"}, {"age", "uint8"}, {"tags", "string[]"}}},
+	{Name: "Team", Fields: []FieldDef{{"lead", "Person"}, {"members", "map<address,Person>"}}},
+}
+
+func mustParse(t *testing.T, s string) *Type {
+	typ, err := ParseType(s, testStructs)
+	if err != nil {
+		t.Fatalf("ParseType(%q): %v", s, err)
+	}
+	return typ
+}
+
+func TestParseType(t *testing.T) {
+	tests := []struct {
+		in   string
+		kind Kind
+		str  string
+	}{
+		{"bool", BoolKind, "bool"},
+		{"int16", IntKind, "int16"},
+		{"uint64", UintKind, "uint64"},
+		{"float32", FloatKind, "float32"},
+		{"bytes", BytesKind, "bytes"},
+		{"uint32[][]", ArrayKind, "uint32[][]"},
+		{"map< string , map<uint8,bool> >", MapKind, "map<string,map<uint8,bool>>"},
+		{"Team", StructKind, "Team"},
+	}
+	for _, tt := range tests {
+		typ := mustParse(t, tt.in)
+		if typ.Kind != tt.kind || typ.String() != tt.str {
+			t.Errorf("ParseType(%q) = %v %q, want %v %q", tt.in, typ.Kind, typ.String(), tt.kind, tt.str)
+		}
+	}
+
+	for _, bad := range []string{"", "uint7", "map<string>", "map<uint8[],bool>", "Unknown[]"} {
+		if _, err := ParseType(bad, testStructs); err == nil {
+			t.Errorf("ParseType(%q) succeeded, want error", bad)
+		}
+	}
+	recursive := []StructDef{{Name: "Node", Fields: []FieldDef{{"next", "Node[]"}}}}
+	if _, err := ParseType("Node", recursive); err == nil {
+		t.Error("recursive struct parsed without error")
+	}
+}
+
+func TestEncodeScalars(t *testing.T) {
+	tests := []struct {
+		typ  string
+		val  interface{}
+		want []byte
+	}{
+		{"bool", true, []byte{1}},
+		{"int8", -1, []byte{0xff}},
+		{"int32", "-2", []byte{0xfe, 0xff, 0xff, 0xff}},
+		{"uint16", 0x0102, []byte{0x02, 0x01}},
+		{"uint64", uint64(1), []byte{1, 0, 0, 0, 0, 0, 0, 0}},
+		{"float32", 1.0, []byte{0, 0, 0x80, 0x3f}},
+		{"string", "hi", []byte{2, 0, 0, 0, 'h', 'i'}},
+		{"bytes", "0x0aff", []byte{2, 0, 0, 0, 0x0a, 0xff}},
+		{"uint8[]", []int{1, 2}, []byte{2, 0, 0, 0, 1, 2}},
+	}
+	for _, tt := range tests {
+		got, err := Encode(mustParse(t, tt.typ), tt.val)
+		if err != nil {
+			t.Errorf("Encode(%s, %v): %v", tt.typ, tt.val, err)
+			continue
+		}
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("Encode(%s, %v) = %x, want %x", tt.typ, tt.val, got, tt.want)
+		}
+	}
+
+	overflows := []struct {
+		typ string
+		val interface{}
+	}{
+		{"int8", 128}, {"int8", -129}, {"uint8", 256}, {"uint32", -1}, {"int64", 1.5},
+	}
+	for _, tt := range overflows {
+		if _, err := Encode(mustParse(t, tt.typ), tt.val); err == nil {
+			t.Errorf("Encode(%s, %v) succeeded, want error", tt.typ, tt.val)
+		}
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	addr := common.HexToAddress("0x1000000000000000000000000000000000000002")
+	team := map[string]interface{}{
+		"lead": map[string]interface{}{"name": "alice", "age": uint64(30), "tags": []interface{}{"admin"}},
+		"members": []MapEntry{
+			{Key: addr, Value: map[string]interface{}{"name": "bob", "age": uint64(25), "tags": []interface{}{}}},
+		},
+	}
+	typ := mustParse(t, "Team")
+	enc, err := Encode(typ, team)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dec, err := Decode(typ, enc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(dec, team) {
+		t.Fatalf("round trip mismatch:\n got  %#v\n want %#v", dec, team)
+	}
+}
+
+func TestMapCanonicalOrder(t *testing.T) {
+	typ := mustParse(t, "map<string,int8>")
+	a, err := Encode(typ, map[string]interface{}{"b": 2, "a": 1, "c": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Encode(typ, []MapEntry{{"c", 3}, {"a", 1}, {"b", 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(a, b) {
+		t.Fatalf("map encoding depends on insertion order: %x != %x", a, b)
+	}
+	if _, err := Encode(typ, []MapEntry{{"a", 1}, {"a", 2}}); err == nil {
+		t.Fatal("duplicate keys encoded without error")
+	}
+
+	// swap the two entries: {1:"a",0:"b"} -> keys out of order
+	unsorted := []byte{2, 0, 0, 0, 1, 0, 0, 0, 'b', 2, 1, 0, 0, 0, 'a', 1}
+	if _, err := Decode(typ, unsorted); err != errUnsortedMap {
+		t.Fatalf("got %v, want %v", err, errUnsortedMap)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		typ  string
+		data []byte
+		err  error
+	}{
+		{"uint32", []byte{1, 2, 3}, errShortBuffer},
+		{"uint8", []byte{1, 2}, errTrailingBytes},
+		{"bool", []byte{2}, errInvalidBool},
+		{"string", []byte{0xff, 0xff, 0xff, 0xff, 'a'}, errShortBuffer},
+		{"uint8[]", []byte{3, 0, 0, 0, 1, 2}, errShortBuffer},
+	}
+	for _, tt := range tests {
+		if _, err := Decode(mustParse(t, tt.typ), tt.data); err != tt.err {
+			t.Errorf("Decode(%s, %x): got %v, want %v", tt.typ, tt.data, err, tt.err)
+		}
+	}
+}
+
+func TestInput(t *testing.T) {
+	types := []*Type{mustParse(t, "string"), mustParse(t, "int64")}
+	args, err := EncodeValues(types, []interface{}{"key", -5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	input := EncodeInput(2, "set", args)
+	if !IsBinaryInput(input) {
+		t.Fatal("encoded input not recognised")
+	}
+	txType, name, data, err := DecodeInput(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txType != 2 || name != "set" || !bytes.Equal(data, args) {
+		t.Fatalf("DecodeInput = %d %q %x", txType, name, data)
+	}
+	vals, err := DecodeValues(types, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(vals, []interface{}{"key", int64(-5)}) {
+		t.Fatalf("DecodeValues = %#v", vals)
+	}
+
+	if IsBinaryInput([]byte{0xc2, 0x01, 0x02}) {
+		t.Fatal("rlp input recognised as binary")
+	}
+	if _, _, _, err := DecodeInput([]byte{0xc0}); err != errNotBinaryInput {
+		t.Fatalf("got %v, want %v", err, errNotBinaryInput)
+	}
+}
@@ -0,0 +1,182 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+)
+
+// Decode decodes data as a single value of type t. The whole input must be
+// consumed.
+//
+// Values decode to bool, int64, uint64, float32, float64, string, []byte,
+// common.Address, []interface{} for arrays, []MapEntry for maps and
+// map[string]interface{} keyed by field name for structs.
+func Decode(t *Type, data []byte) (interface{}, error) {
+	d := &decoder{data: data}
+	v, err := d.decode(t)
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(data) {
+		return nil, errTrailingBytes
+	}
+	return v, nil
+}
+
+// DecodeValues decodes data as a tuple of types.
+func DecodeValues(types []*Type, data []byte) ([]interface{}, error) {
+	d := &decoder{data: data}
+	vals := make([]interface{}, 0, len(types))
+	for _, t := range types {
+		v, err := d.decode(t)
+		if err != nil {
+			return nil, err
+		}
+		vals = append(vals, v)
+	}
+	if d.pos != len(data) {
+		return nil, errTrailingBytes
+	}
+	return vals, nil
+}
+
+type decoder struct {
+	data []byte
+	pos  int
+}
+
+func (d *decoder) next(n int) ([]byte, error) {
+	if n < 0 || len(d.data)-d.pos < n {
+		return nil, errShortBuffer
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+func (d *decoder) uint(size int) (uint64, error) {
+	b, err := d.next(size)
+	if err != nil {
+		return 0, err
+	}
+	var tmp [8]byte
+	copy(tmp[:], b)
+	return binary.LittleEndian.Uint64(tmp[:]), nil
+}
+
+func (d *decoder) length() (int, error) {
+	n, err := d.uint(lengthSize)
+	if err != nil {
+		return 0, err
+	}
+	// every element takes at least one byte, reject lengths that cannot fit
+	if n > uint64(len(d.data)-d.pos) {
+		return 0, errShortBuffer
+	}
+	return int(n), nil
+}
+
+func (d *decoder) decode(t *Type) (interface{}, error) {
+	switch t.Kind {
+	case BoolKind:
+		b, err := d.next(1)
+		if err != nil {
+			return nil, err
+		}
+		if b[0] > 1 {
+			return nil, errInvalidBool
+		}
+		return b[0] == 1, nil
+	case IntKind:
+		n, err := d.uint(t.Size)
+		if err != nil {
+			return nil, err
+		}
+		shift := uint(64 - t.Size*8)
+		return int64(n<<shift) >> shift, nil
+	case UintKind:
+		return d.uint(t.Size)
+	case FloatKind:
+		n, err := d.uint(t.Size)
+		if err != nil {
+			return nil, err
+		}
+		if t.Size == 4 {
+			return math.Float32frombits(uint32(n)), nil
+		}
+		return math.Float64frombits(n), nil
+	case AddressKind:
+		b, err := d.next(addressSize)
+		if err != nil {
+			return nil, err
+		}
+		return common.BytesToAddress(b), nil
+	case StringKind, BytesKind:
+		n, err := d.length()
+		if err != nil {
+			return nil, err
+		}
+		b, err := d.next(n)
+		if err != nil {
+			return nil, err
+		}
+		if t.Kind == StringKind {
+			return string(b), nil
+		}
+		return common.CopyBytes(b), nil
+	case ArrayKind:
+		n, err := d.length()
+		if err != nil {
+			return nil, err
+		}
+		elems := make([]interface{}, 0, n)
+		for i := 0; i < n; i++ {
+			e, err := d.decode(t.Elem)
+			if err != nil {
+				return nil, err
+			}
+			elems = append(elems, e)
+		}
+		return elems, nil
+	case MapKind:
+		n, err := d.length()
+		if err != nil {
+			return nil, err
+		}
+		entries := make([]MapEntry, 0, n)
+		var prev []byte
+		for i := 0; i < n; i++ {
+			start := d.pos
+			k, err := d.decode(t.Key)
+			if err != nil {
+				return nil, err
+			}
+			keyBytes := d.data[start:d.pos]
+			if i > 0 && bytes.Compare(prev, keyBytes) >= 0 {
+				return nil, errUnsortedMap
+			}
+			prev = keyBytes
+			v, err := d.decode(t.Elem)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, MapEntry{Key: k, Value: v})
+		}
+		return entries, nil
+	case StructKind:
+		fields := make(map[string]interface{}, len(t.Fields))
+		for _, f := range t.Fields {
+			v, err := d.decode(f.Type)
+			if err != nil {
+				return nil, err
+			}
+			fields[f.Name] = v
+		}
+		return fields, nil
+	}
+	return nil, fmt.Errorf("codec: unknown kind %d", t.Kind)
+}
@@ -0,0 +1,339 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+)
+
+// MapEntry is a decoded key/value pair of a map. Maps decode to a slice of
+// entries so the canonical order is preserved.
+type MapEntry struct {
+	Key   interface{} `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+// Encode encodes v as a value of type t.
+//
+// Integers accept any Go integer type, float64, json.Number and decimal
+// strings; bytes and addresses accept byte slices and hex strings; structs
+// accept a map keyed by field name or a slice in field order; maps accept a
+// Go map, a map[string]interface{} or a []MapEntry.
+func Encode(t *Type, v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encode(&buf, t, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// EncodeValues encodes vals as a tuple of types, as used for function
+// arguments and return values.
+func EncodeValues(types []*Type, vals []interface{}) ([]byte, error) {
+	if len(types) != len(vals) {
+		return nil, fmt.Errorf("codec: want %d values, got %d", len(types), len(vals))
+	}
+	var buf bytes.Buffer
+	for i, t := range types {
+		if err := encode(&buf, t, vals[i]); err != nil {
+			return nil, fmt.Errorf("codec: value %d: %v", i, err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func encode(buf *bytes.Buffer, t *Type, v interface{}) error {
+	switch t.Kind {
+	case BoolKind:
+		b, err := toBool(v)
+		if err != nil {
+			return err
+		}
+		if b {
+			buf.WriteByte(1)
+		} else {
+			buf.WriteByte(0)
+		}
+	case IntKind:
+		n, err := toInt64(v)
+		if err != nil {
+			return err
+		}
+		bits := uint(t.Size * 8)
+		if bits < 64 && (n < -(1<<(bits-1)) || n >= 1<<(bits-1)) {
+			return fmt.Errorf("codec: %d overflows %s", n, t)
+		}
+		putUint(buf, uint64(n), t.Size)
+	case UintKind:
+		n, err := toUint64(v)
+		if err != nil {
+			return err
+		}
+		if t.Size < 8 && n >= 1<<uint(t.Size*8) {
+			return fmt.Errorf("codec: %d overflows %s", n, t)
+		}
+		putUint(buf, n, t.Size)
+	case FloatKind:
+		f, err := toFloat64(v)
+		if err != nil {
+			return err
+		}
+		if t.Size == 4 {
+			putUint(buf, uint64(math.Float32bits(float32(f))), 4)
+		} else {
+			putUint(buf, math.Float64bits(f), 8)
+		}
+	case AddressKind:
+		b, err := toBytes(v)
+		if err != nil {
+			return err
+		}
+		if len(b) != addressSize {
+			return fmt.Errorf("codec: address must be %d bytes, got %d", addressSize, len(b))
+		}
+		buf.Write(b)
+	case StringKind:
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("codec: cannot encode %T as string", v)
+		}
+		putUint(buf, uint64(len(s)), lengthSize)
+		buf.WriteString(s)
+	case BytesKind:
+		b, err := toBytes(v)
+		if err != nil {
+			return err
+		}
+		putUint(buf, uint64(len(b)), lengthSize)
+		buf.Write(b)
+	case ArrayKind:
+		elems, err := toSlice(v)
+		if err != nil {
+			return err
+		}
+		putUint(buf, uint64(len(elems)), lengthSize)
+		for _, e := range elems {
+			if err := encode(buf, t.Elem, e); err != nil {
+				return err
+			}
+		}
+	case MapKind:
+		return encodeMap(buf, t, v)
+	case StructKind:
+		return encodeStruct(buf, t, v)
+	default:
+		return fmt.Errorf("codec: unknown kind %d", t.Kind)
+	}
+	return nil
+}
+
+func encodeMap(buf *bytes.Buffer, t *Type, v interface{}) error {
+	var entries []MapEntry
+	switch m := v.(type) {
+	case []MapEntry:
+		entries = m
+	case map[string]interface{}:
+		for k, val := range m {
+			entries = append(entries, MapEntry{Key: k, Value: val})
+		}
+	default:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() != reflect.Map {
+			return fmt.Errorf("codec: cannot encode %T as %s", v, t)
+		}
+		for _, k := range rv.MapKeys() {
+			entries = append(entries, MapEntry{Key: k.Interface(), Value: rv.MapIndex(k).Interface()})
+		}
+	}
+
+	type pair struct{ key, value []byte }
+	pairs := make([]pair, 0, len(entries))
+	for _, e := range entries {
+		key, err := Encode(t.Key, e.Key)
+		if err != nil {
+			return err
+		}
+		value, err := Encode(t.Elem, e.Value)
+		if err != nil {
+			return err
+		}
+		pairs = append(pairs, pair{key, value})
+	}
+	sort.Slice(pairs, func(i, j int) bool { return bytes.Compare(pairs[i].key, pairs[j].key) < 0 })
+	for i := 1; i < len(pairs); i++ {
+		if bytes.Equal(pairs[i-1].key, pairs[i].key) {
+			return fmt.Errorf("codec: duplicate map key %x", pairs[i].key)
+		}
+	}
+
+	putUint(buf, uint64(len(pairs)), lengthSize)
+	for _, p := range pairs {
+		buf.Write(p.key)
+		buf.Write(p.value)
+	}
+	return nil
+}
+
+func encodeStruct(buf *bytes.Buffer, t *Type, v interface{}) error {
+	switch s := v.(type) {
+	case map[string]interface{}:
+		for _, f := range t.Fields {
+			fv, ok := s[f.Name]
+			if !ok {
+				return fmt.Errorf("codec: missing field %s of %s", f.Name, t.Name)
+			}
+			if err := encode(buf, f.Type, fv); err != nil {
+				return err
+			}
+		}
+	default:
+		elems, err := toSlice(v)
+		if err != nil {
+			return err
+		}
+		if len(elems) != len(t.Fields) {
+			return fmt.Errorf("codec: struct %s has %d fields, got %d values", t.Name, len(t.Fields), len(elems))
+		}
+		for i, f := range t.Fields {
+			if err := encode(buf, f.Type, elems[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func putUint(buf *bytes.Buffer, n uint64, size int) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], n)
+	buf.Write(b[:size])
+}
+
+func toBool(v interface{}) (bool, error) {
+	switch b := v.(type) {
+	case bool:
+		return b, nil
+	case string:
+		return strconv.ParseBool(b)
+	}
+	return false, fmt.Errorf("codec: cannot encode %T as bool", v)
+}
+
+func toInt64(v interface{}) (int64, error) {
+	switch n := v.(type) {
+	case int:
+		return int64(n), nil
+	case int8:
+		return int64(n), nil
+	case int16:
+		return int64(n), nil
+	case int32:
+		return int64(n), nil
+	case int64:
+		return n, nil
+	case uint8:
+		return int64(n), nil
+	case uint16:
+		return int64(n), nil
+	case uint32:
+		return int64(n), nil
+	case uint64:
+		if n > math.MaxInt64 {
+			return 0, fmt.Errorf("codec: %d overflows int64", n)
+		}
+		return int64(n), nil
+	case float64:
+		if n != math.Trunc(n) {
+			return 0, fmt.Errorf("codec: %v is not an integer", n)
+		}
+		return int64(n), nil
+	case json.Number:
+		return strconv.ParseInt(string(n), 10, 64)
+	case string:
+		return strconv.ParseInt(strings.TrimSpace(n), 10, 64)
+	}
+	return 0, fmt.Errorf("codec: cannot encode %T as integer", v)
+}
+
+func toUint64(v interface{}) (uint64, error) {
+	switch n := v.(type) {
+	case uint:
+		return uint64(n), nil
+	case uint8:
+		return uint64(n), nil
+	case uint16:
+		return uint64(n), nil
+	case uint32:
+		return uint64(n), nil
+	case uint64:
+		return n, nil
+	case json.Number:
+		return strconv.ParseUint(string(n), 10, 64)
+	case string:
+		return strconv.ParseUint(strings.TrimSpace(n), 10, 64)
+	}
+	i, err := toInt64(v)
+	if err != nil {
+		return 0, err
+	}
+	if i < 0 {
+		return 0, fmt.Errorf("codec: negative value %d for unsigned integer", i)
+	}
+	return uint64(i), nil
+}
+
+func toFloat64(v interface{}) (float64, error) {
+	switch f := v.(type) {
+	case float32:
+		return float64(f), nil
+	case float64:
+		return f, nil
+	case json.Number:
+		return f.Float64()
+	case string:
+		return strconv.ParseFloat(strings.TrimSpace(f), 64)
+	}
+	i, err := toInt64(v)
+	if err != nil {
+		return 0, err
+	}
+	return float64(i), nil
+}
+
+func toBytes(v interface{}) ([]byte, error) {
+	switch b := v.(type) {
+	case []byte:
+		return b, nil
+	case common.Address:
+		return b.Bytes(), nil
+	case *common.Address:
+		return b.Bytes(), nil
+	case string:
+		return hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(b, "0x"), "0X"))
+	}
+	return nil, fmt.Errorf("codec: cannot encode %T as bytes", v)
+}
+
+func toSlice(v interface{}) ([]interface{}, error) {
+	if s, ok := v.([]interface{}); ok {
+		return s, nil
+	}
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("codec: cannot encode %T as a sequence", v)
+	}
+	s := make([]interface{}, rv.Len())
+	for i := range s {
+		s[i] = rv.Index(i).Interface()
+	}
+	return s, nil
+}
@@ -0,0 +1,57 @@
+package codec
+
+import (
+	"bytes"
+	"errors"
+)
+
+// Version is the ABI version implemented by this package.
+const Version = 2
+
+// inputPrefix marks a call input encoded with this package. A leading zero
+// byte can never start an RLP list, so binary inputs are distinguishable from
+// the RLP inputs of the C++ ABI.
+var inputPrefix = []byte{0x00, 'a', 'b', 'i', Version}
+
+var (
+	errNotBinaryInput = errors.New("codec: input is not binary ABI encoded")
+
+	stringType = &Type{Kind: StringKind, str: "string"}
+	uint64Type = &Type{Kind: UintKind, Size: 8, str: "uint64"}
+)
+
+// IsBinaryInput reports whether input carries the binary ABI prefix.
+func IsBinaryInput(input []byte) bool {
+	return bytes.HasPrefix(input, inputPrefix)
+}
+
+// EncodeInput builds the transaction input of a call to funcName with the
+// already encoded arguments.
+//
+//	input = prefix | uint64 txType | string funcName | args
+func EncodeInput(txType uint64, funcName string, args []byte) []byte {
+	head, _ := EncodeValues([]*Type{uint64Type, stringType}, []interface{}{txType, funcName})
+
+	input := make([]byte, 0, len(inputPrefix)+len(head)+len(args))
+	input = append(input, inputPrefix...)
+	input = append(input, head...)
+	return append(input, args...)
+}
+
+// DecodeInput splits a binary call input into its transaction type, function
+// name and encoded arguments.
+func DecodeInput(input []byte) (txType uint64, funcName string, args []byte, err error) {
+	if !IsBinaryInput(input) {
+		return 0, "", nil, errNotBinaryInput
+	}
+	d := &decoder{data: input, pos: len(inputPrefix)}
+	t, err := d.decode(uint64Type)
+	if err != nil {
+		return 0, "", nil, err
+	}
+	name, err := d.decode(stringType)
+	if err != nil {
+		return 0, "", nil, err
+	}
+	return t.(uint64), name.(string), input[d.pos:], nil
+}
@@ -0,0 +1,202 @@
+// Package codec implements the length-prefixed binary encoding used by the
+// version 2 WASM contract ABI. Contracts written in Rust or AssemblyScript use
+// this encoding for call inputs, return values and event data instead of the
+// RLP/null-terminated layout expected by the C++ BCWasm library.
+//
+// Layout:
+//
+//	bool                 1 byte, 0 or 1
+//	int8..int64          fixed width, little endian, two's complement
+//	uint8..uint64        fixed width, little endian
+//	float32, float64     IEEE 754, little endian
+//	address              20 bytes
+//	string, bytes        uint32 length followed by the raw bytes
+//	T[]                  uint32 count followed by the elements
+//	map<K,V>             uint32 count followed by key/value pairs, ordered by
+//	                     the encoded key bytes
+//	struct               the fields in declaration order, without a prefix
+package codec
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Kind identifies the shape of an ABI type.
+type Kind int
+
+const (
+	BoolKind Kind = iota
+	IntKind
+	UintKind
+	FloatKind
+	AddressKind
+	StringKind
+	BytesKind
+	ArrayKind
+	MapKind
+	StructKind
+)
+
+const (
+	// lengthSize is the size of the length prefix of strings, bytes, arrays and maps.
+	lengthSize = 4
+	// addressSize is the size of an encoded address.
+	addressSize = 20
+)
+
+var (
+	errShortBuffer    = errors.New("codec: unexpected end of data")
+	errTrailingBytes  = errors.New("codec: trailing bytes after value")
+	errUnsortedMap    = errors.New("codec: map keys are not in canonical order")
+	errInvalidBool    = errors.New("codec: invalid bool value")
+	errStructRecursed = errors.New("codec: recursive struct definition")
+)
+
+// Field is a named member of a struct type.
+type Field struct {
+	Name string
+	Type *Type
+}
+
+// Type describes an ABI type.
+type Type struct {
+	Kind   Kind
+	Size   int     // byte width of integers and floats
+	Elem   *Type   // element type of arrays and value type of maps
+	Key    *Type   // key type of maps
+	Name   string  // name of struct types
+	Fields []Field // members of struct types
+
+	str string
+}
+
+// String returns the type in the notation accepted by ParseType.
+func (t *Type) String() string {
+	return t.str
+}
+
+// StructDef declares a named struct type, as listed in the "structs" section
+// of a version 2 ABI file.
+type StructDef struct {
+	Name   string
+	Fields []FieldDef
+}
+
+// FieldDef is the textual declaration of a struct field.
+type FieldDef struct {
+	Name string
+	Type string
+}
+
+// ParseType parses a type declaration such as "uint32", "string[]",
+// "map<string,uint64>" or the name of a struct from defs.
+func ParseType(s string, defs []StructDef) (*Type, error) {
+	p := &typeParser{defs: make(map[string]StructDef), parsing: make(map[string]bool)}
+	for _, d := range defs {
+		p.defs[d.Name] = d
+	}
+	return p.parse(strings.TrimSpace(s))
+}
+
+type typeParser struct {
+	defs    map[string]StructDef
+	parsing map[string]bool
+}
+
+func (p *typeParser) parse(s string) (*Type, error) {
+	if s == "" {
+		return nil, errors.New("codec: empty type")
+	}
+	if strings.HasSuffix(s, "[]") {
+		elem, err := p.parse(strings.TrimSpace(s[:len(s)-2]))
+		if err != nil {
+			return nil, err
+		}
+		return &Type{Kind: ArrayKind, Elem: elem, str: elem.str + "[]"}, nil
+	}
+	if strings.HasPrefix(s, "map<") && strings.HasSuffix(s, ">") {
+		return p.parseMap(s[len("map<") : len(s)-1])
+	}
+	switch s {
+	case "bool":
+		return &Type{Kind: BoolKind, Size: 1, str: s}, nil
+	case "int8", "int16", "int32", "int64":
+		return &Type{Kind: IntKind, Size: bitSize(s[3:]) / 8, str: s}, nil
+	case "uint8", "uint16", "uint32", "uint64":
+		return &Type{Kind: UintKind, Size: bitSize(s[4:]) / 8, str: s}, nil
+	case "float32":
+		return &Type{Kind: FloatKind, Size: 4, str: s}, nil
+	case "float64":
+		return &Type{Kind: FloatKind, Size: 8, str: s}, nil
+	case "address":
+		return &Type{Kind: AddressKind, Size: addressSize, str: s}, nil
+	case "string":
+		return &Type{Kind: StringKind, str: s}, nil
+	case "bytes":
+		return &Type{Kind: BytesKind, str: s}, nil
+	}
+	def, ok := p.defs[s]
+	if !ok {
+		return nil, fmt.Errorf("codec: unknown type %q", s)
+	}
+	if p.parsing[s] {
+		return nil, errStructRecursed
+	}
+	p.parsing[s] = true
+	defer delete(p.parsing, s)
+
+	t := &Type{Kind: StructKind, Name: def.Name, str: def.Name}
+	for _, f := range def.Fields {
+		ft, err := p.parse(strings.TrimSpace(f.Type))
+		if err != nil {
+			return nil, fmt.Errorf("codec: struct %s field %s: %v", def.Name, f.Name, err)
+		}
+		t.Fields = append(t.Fields, Field{Name: f.Name, Type: ft})
+	}
+	return t, nil
+}
+
+func (p *typeParser) parseMap(s string) (*Type, error) {
+	// split at the first top level comma, value types may be maps themselves
+	depth := 0
+	for i, c := range s {
+		switch c {
+		case '<':
+			depth++
+		case '>':
+			depth--
+		case ',':
+			if depth != 0 {
+				continue
+			}
+			key, err := p.parse(strings.TrimSpace(s[:i]))
+			if err != nil {
+				return nil, err
+			}
+			switch key.Kind {
+			case ArrayKind, MapKind, StructKind:
+				return nil, fmt.Errorf("codec: unsupported map key type %s", key)
+			}
+			elem, err := p.parse(strings.TrimSpace(s[i+1:]))
+			if err != nil {
+				return nil, err
+			}
+			return &Type{Kind: MapKind, Key: key, Elem: elem, str: "map<" + key.str + "," + elem.str + ">"}, nil
+		}
+	}
+	return nil, fmt.Errorf("codec: invalid map type map<%s>", s)
+}
+
+func bitSize(s string) int {
+	switch s {
+	case "8":
+		return 8
+	case "16":
+		return 16
+	case "32":
+		return 32
+	}
+	return 64
+}
@@ -1,8 +1,10 @@
 package exec
 
 import (
-	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"fmt"
 	"math/big"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
 )
 
 type StateDB interface {
@@ -27,4 +29,22 @@ type StateDB interface {
 	Transfer(addr common.Address, value *big.Int) (ret []byte, leftOverGas uint64, err error)
 	DelegateCall(addr, params []byte) ([]byte, error)
 	Call(addr, params []byte) ([]byte, error)
+
+	// CallContract calls addr with at most gas and transfers value to it. The
+	// gas used by the callee is returned to be charged to the caller. A callee
+	// that reverts returns its reason as a *RevertError.
+	CallContract(addr common.Address, params []byte, gas uint64, value *big.Int) (ret []byte, gasUsed uint64, err error)
+	// DelegateCallContract runs the code of addr in the context of the caller.
+	DelegateCallContract(addr common.Address, params []byte, gas uint64) (ret []byte, gasUsed uint64, err error)
+}
+
+// RevertError aborts the execution of a contract. Unlike other failures the
+// state changes are reverted without consuming the gas left, and the reason
+// is returned to the caller.
+type RevertError struct {
+	Reason []byte
+}
+
+func (e *RevertError) Error() string {
+	return fmt.Sprintf("execution reverted: %s", e.Reason)
 }
@@ -106,6 +106,14 @@ type VMContext struct {
 
 	StateDB StateDB
 	Log     log.Logger
+
+	// Input, ReturnData and CallOutput carry the call data, the result and
+	// the output of the last contract call of contracts using the binary
+	// ABI, which exchange them through host imports instead of function
+	// parameters.
+	Input      []byte
+	ReturnData []byte
+	CallOutput []byte
 }
 
 type VMMemory struct {
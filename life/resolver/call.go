@@ -0,0 +1,110 @@
+package resolver
+
+import (
+	"math/big"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/life/exec"
+)
+
+// Status codes returned by the contract call host imports.
+const (
+	callSuccess  = 0
+	callReverted = 1 // the callee reverted, the call output is its reason
+	callFailed   = 2 // any other failure, the call output is the error message
+)
+
+// callGas returns the gas given to a callee: the requested amount capped to
+// all but one 64th of the gas left, so the caller can still handle a failure.
+// Zero requests as much as possible.
+func callGas(vm *exec.VirtualMachine, requested uint64) uint64 {
+	left := vm.Context.GasLimit - vm.Context.GasUsed
+	left -= left / 64
+	if requested == 0 || requested > left {
+		return left
+	}
+	return requested
+}
+
+// callContract performs a call on behalf of the running contract and charges
+// the gas used by the callee to it.
+func callContract(vm *exec.VirtualMachine, addr common.Address, input []byte, gas uint64, value *big.Int, delegate bool) int64 {
+	var (
+		ret  []byte
+		used uint64
+		err  error
+	)
+	input = common.CopyBytes(input)
+	gas = callGas(vm, gas)
+	if delegate {
+		ret, used, err = vm.Context.StateDB.DelegateCallContract(addr, input, gas)
+	} else {
+		ret, used, err = vm.Context.StateDB.CallContract(addr, input, gas, value)
+	}
+	vm.Context.GasUsed += used
+	return setCallOutput(vm, ret, err)
+}
+
+func setCallOutput(vm *exec.VirtualMachine, ret []byte, err error) int64 {
+	switch e := err.(type) {
+	case nil:
+		vm.Context.CallOutput = ret
+		return callSuccess
+	case *exec.RevertError:
+		vm.Context.CallOutput = e.Reason
+		return callReverted
+	default:
+		vm.Context.CallOutput = []byte(err.Error())
+		return callFailed
+	}
+}
+
+// define: int32_t bcwasmCallContract(const uint8_t addr[20], const uint8_t *input, size_t len, uint64_t gas, const uint8_t value[32]);
+// define: int32_t platone_call_contract(const uint8_t addr[20], const uint8_t *input, uint32_t len, uint64_t gas, const uint8_t value[32]);
+// returns one of the call status codes, the return data or revert reason of
+// the callee is read with the call output imports.
+func envCallContract(vm *exec.VirtualMachine) int64 {
+	addr := int(int32(vm.GetCurrentFrame().Locals[0]))
+	input := int(int32(vm.GetCurrentFrame().Locals[1]))
+	inputLen := int(int32(vm.GetCurrentFrame().Locals[2]))
+	gas := uint64(vm.GetCurrentFrame().Locals[3])
+	value := int(int32(vm.GetCurrentFrame().Locals[4]))
+
+	return callContract(vm,
+		common.BytesToAddress(vm.Memory.Memory[addr:addr+common.AddressLength]),
+		vm.Memory.Memory[input:input+inputLen],
+		gas,
+		new(big.Int).SetBytes(vm.Memory.Memory[value:value+32]),
+		false)
+}
+
+// define: int32_t bcwasmDelegateCallContract(const uint8_t addr[20], const uint8_t *input, size_t len, uint64_t gas);
+// define: int32_t platone_delegate_call_contract(const uint8_t addr[20], const uint8_t *input, uint32_t len, uint64_t gas);
+func envDelegateCallContract(vm *exec.VirtualMachine) int64 {
+	addr := int(int32(vm.GetCurrentFrame().Locals[0]))
+	input := int(int32(vm.GetCurrentFrame().Locals[1]))
+	inputLen := int(int32(vm.GetCurrentFrame().Locals[2]))
+	gas := uint64(vm.GetCurrentFrame().Locals[3])
+
+	return callContract(vm,
+		common.BytesToAddress(vm.Memory.Memory[addr:addr+common.AddressLength]),
+		vm.Memory.Memory[input:input+inputLen],
+		gas,
+		nil,
+		true)
+}
+
+// define: void bcwasmRevert(const uint8_t *reason, size_t len);
+// define: void platone_revert(const uint8_t *reason, uint32_t len);
+// stops the execution and reverts the state changes of the contract, the
+// reason is returned to the caller.
+func envRevert(vm *exec.VirtualMachine) int64 {
+	src := int(int32(vm.GetCurrentFrame().Locals[0]))
+	size := int(int32(vm.GetCurrentFrame().Locals[1]))
+	panic(&exec.RevertError{Reason: common.CopyBytes(vm.Memory.Memory[src : src+size])})
+}
+
+func envRevertGasCost(vm *exec.VirtualMachine) (uint64, error) {
+	size := uint64(uint32(vm.GetCurrentFrame().Locals[1]))
+	return 9 + size/32, nil
+}
@@ -0,0 +1,46 @@
+package resolver
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/life/exec"
+)
+
+func TestCallGas(t *testing.T) {
+	vm := &exec.VirtualMachine{Context: &exec.VMContext{GasLimit: 6500, GasUsed: 100}}
+	tests := []struct {
+		requested, want uint64
+	}{
+		{0, 6300},    // all but one 64th of the 6400 left
+		{1000, 1000}, // within the limit
+		{9000, 6300}, // capped
+	}
+	for _, tt := range tests {
+		if got := callGas(vm, tt.requested); got != tt.want {
+			t.Errorf("callGas(%d) = %d, want %d", tt.requested, got, tt.want)
+		}
+	}
+}
+
+func TestSetCallOutput(t *testing.T) {
+	vm := &exec.VirtualMachine{Context: &exec.VMContext{}}
+	tests := []struct {
+		ret    []byte
+		err    error
+		status int64
+		output string
+	}{
+		{[]byte("ok"), nil, callSuccess, "ok"},
+		{nil, &exec.RevertError{Reason: []byte("not owner")}, callReverted, "not owner"},
+		{nil, errors.New("out of gas"), callFailed, "out of gas"},
+	}
+	for _, tt := range tests {
+		if status := setCallOutput(vm, tt.ret, tt.err); status != tt.status {
+			t.Errorf("status = %d, want %d", status, tt.status)
+		}
+		if string(vm.Context.CallOutput) != tt.output {
+			t.Errorf("call output = %q, want %q", vm.Context.CallOutput, tt.output)
+		}
+	}
+}
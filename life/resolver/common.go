@@ -5,6 +5,7 @@ import (
 	"github.com/PlatONEnetwork/PlatONE-Go/accounts/abi"
 	"github.com/PlatONEnetwork/PlatONE-Go/common"
 	"github.com/PlatONEnetwork/PlatONE-Go/life/exec"
+	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
 	"strings"
 )
 
@@ -18,5 +19,9 @@ func parseWasmCallSolInput(vm *exec.VirtualMachine, address, input []byte) ([]by
 	if ok, _, _, _ := common.IsWasmContractCode(code); ok {
 		return input, nil
 	}
+	// solidity abi encoded input is passed on as it is
+	if kind, _, _, err := rlp.Split(input); err != nil || kind != rlp.List {
+		return input, nil
+	}
 	return abi.ParseWasmCallSolInput(input)
 }
@@ -31,6 +31,10 @@ import (
 	"github.com/PlatONEnetwork/PlatONE-Go/life/exec"
 )
 
+// maxIndexedTopics is the number of topics emitEventIndexed may add after
+// the event name, see utils.MaxIndexedParams.
+const maxIndexedTopics = 3
+
 var (
 	cfc  = newCfcSet()
 	cgbl = newGlobalSet()
@@ -184,6 +188,8 @@ func newCfcSet() map[string]map[string]*exec.FunctionImport {
 			"getStateSize": &exec.FunctionImport{Execute: envGetStateSize, GasCost: envGetStateSizeGasCost},
 			"ecrecover":    &exec.FunctionImport{Execute: envEcrecover, GasCost: envEcrecoverGasCost},
 
+			"emitEventIndexed": &exec.FunctionImport{Execute: envEmitEventIndexed, GasCost: envEmitEventGasCost},
+
 			// support for vc
 			//Temporarily comment the following code to prepare for cross platform
 			//"vc_InitGadgetEnv":          &exec.FunctionImport{Execute: envInitGadgetEnv, GasCost: envInitGadgetEnvGasCost},
@@ -207,6 +213,14 @@ func newCfcSet() map[string]map[string]*exec.FunctionImport {
 			"bcwasmDelegateCallInt64":  &exec.FunctionImport{Execute: envBCWasmDelegateCallInt64, GasCost: envBCWasmDelegateCallInt64GasCost},
 			"bcwasmDelegateCallString": &exec.FunctionImport{Execute: envBCWasmDelegateCallString, GasCost: envBCWasmDelegateCallStringGasCost},
 
+			// calls with explicit gas and value, the callee output is read back
+			"bcwasmCallContract":         &exec.FunctionImport{Execute: envCallContract, GasCost: envBCWasmCallGasCost},
+			"bcwasmDelegateCallContract": &exec.FunctionImport{Execute: envDelegateCallContract, GasCost: envBCWasmDelegateCallGasCost},
+			"bcwasmCallOutputLength":     &exec.FunctionImport{Execute: platoneCallOutputLength, GasCost: constGasFunc(9)},
+			"bcwasmGetCallOutput":        &exec.FunctionImport{Execute: platoneGetCallOutput, GasCost: platoneGetInputGasCost},
+			"bcwasmReturn":               &exec.FunctionImport{Execute: platoneReturn, GasCost: platoneReturnGasCost},
+			"bcwasmRevert":               &exec.FunctionImport{Execute: envRevert, GasCost: envRevertGasCost},
+
 			//nizkpail
 			//"pailEncrypt":     &exec.FunctionImport{Execute: envPailEncrypt, GasCost: envPailEncryptGasCost},
 
@@ -1088,6 +1102,34 @@ func envEmitEventGasCost(vm *exec.VirtualMachine) (uint64, error) {
 	return 300000, nil
 }
 
+//void emitEventIndexed(const char *name, size_t nameLen, const uint8_t *topics, size_t topicsCount, const uint8_t *data, size_t dataLen);
+// topics holds topicsCount packed 32 bytes words, they follow keccak256(name) in the log.
+func envEmitEventIndexed(vm *exec.VirtualMachine) int64 {
+	name := int(int32(vm.GetCurrentFrame().Locals[0]))
+	nameLen := int(int32(vm.GetCurrentFrame().Locals[1]))
+	topicsSrc := int(int32(vm.GetCurrentFrame().Locals[2]))
+	topicsCount := int(int32(vm.GetCurrentFrame().Locals[3]))
+	dataSrc := int(int32(vm.GetCurrentFrame().Locals[4]))
+	dataLen := int(int32(vm.GetCurrentFrame().Locals[5]))
+
+	if topicsCount < 0 || topicsCount > maxIndexedTopics {
+		panic(fmt.Sprintf("too many indexed event topics(%d>%d)", topicsCount, maxIndexedTopics))
+	}
+	topics := make([]common.Hash, 0, topicsCount+1)
+	topics = append(topics, common.BytesToHash(crypto.Keccak256(vm.Memory.Memory[name:name+nameLen])))
+	for i := 0; i < topicsCount; i++ {
+		off := topicsSrc + i*common.HashLength
+		topics = append(topics, common.BytesToHash(vm.Memory.Memory[off:off+common.HashLength]))
+	}
+	d := make([]byte, dataLen)
+	copy(d, vm.Memory.Memory[dataSrc:dataSrc+dataLen])
+	address := vm.Context.StateDB.Address()
+	bn := vm.Context.StateDB.BlockNumber().Uint64()
+
+	vm.Context.StateDB.AddLog(address, topics, d, bn)
+	return 0
+}
+
 func envSetState(vm *exec.VirtualMachine) int64 {
 	key := int(int32(vm.GetCurrentFrame().Locals[0]))
 	keyLen := int(int32(vm.GetCurrentFrame().Locals[1]))
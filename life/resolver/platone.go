@@ -0,0 +1,224 @@
+package resolver
+
+import (
+	"fmt"
+	"unicode/utf16"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/crypto"
+	"github.com/PlatONEnetwork/PlatONE-Go/life/exec"
+)
+
+// platoneModule is the import module of contracts using the binary ABI.
+const platoneModule = "platone"
+
+var pfc = newPlatoneFuncSet()
+
+// BinaryResolver resolves the host imports of contracts using the binary
+// ABI (Rust, AssemblyScript). Such contracts manage their own linear memory,
+// so the host only ever writes into buffers provided by the contract.
+type BinaryResolver struct{}
+
+func (r *BinaryResolver) ResolveFunc(module, field string) *exec.FunctionImport {
+	if m, exist := pfc[module]; exist {
+		if f, exist := m[field]; exist {
+			return f
+		}
+	}
+	return &exec.FunctionImport{
+		Execute: func(vm *exec.VirtualMachine) int64 {
+			panic(fmt.Sprintf("unsupport func module:%s field:%s", module, field))
+		},
+		GasCost: func(vm *exec.VirtualMachine) (uint64, error) {
+			panic(fmt.Sprintf("unsupport gas cost module:%s field:%s", module, field))
+		},
+	}
+}
+
+func (r *BinaryResolver) ResolveGlobal(module, field string) int64 {
+	return 0
+}
+
+func newPlatoneFuncSet() map[string]map[string]*exec.FunctionImport {
+	return map[string]map[string]*exec.FunctionImport{
+		platoneModule: {
+			"platone_input_length": &exec.FunctionImport{Execute: platoneInputLength, GasCost: constGasFunc(9)},
+			"platone_get_input":    &exec.FunctionImport{Execute: platoneGetInput, GasCost: platoneGetInputGasCost},
+			"platone_return":       &exec.FunctionImport{Execute: platoneReturn, GasCost: platoneReturnGasCost},
+			"platone_panic":        &exec.FunctionImport{Execute: platonePanic, GasCost: constGasFunc(1)},
+			"platone_debug":        &exec.FunctionImport{Execute: platoneDebug, GasCost: envPrintsGasCost},
+
+			"platone_caller":       &exec.FunctionImport{Execute: envCaller, GasCost: envCallerGasCost},
+			"platone_origin":       &exec.FunctionImport{Execute: envOrigin, GasCost: envOriginGasCost},
+			"platone_address":      &exec.FunctionImport{Execute: envAddress, GasCost: envAddressGasCost},
+			"platone_call_value":   &exec.FunctionImport{Execute: envCallValue, GasCost: envCallValueGasCost},
+			"platone_balance":      &exec.FunctionImport{Execute: platoneBalance, GasCost: envBalanceGasCost},
+			"platone_block_number": &exec.FunctionImport{Execute: envNumber, GasCost: envNumberGasCost},
+			"platone_block_hash":   &exec.FunctionImport{Execute: envBlockHash, GasCost: envBlockHashGasCost},
+			"platone_timestamp":    &exec.FunctionImport{Execute: envTimestamp, GasCost: envTimestampGasCost},
+			"platone_gas_limit":    &exec.FunctionImport{Execute: envGasLimit, GasCost: envGasLimitGasCost},
+			"platone_sha3":         &exec.FunctionImport{Execute: platoneSha3, GasCost: envSha3GasCost},
+
+			"platone_set_state":          &exec.FunctionImport{Execute: envSetState, GasCost: envSetStateGasCost},
+			"platone_get_state_length":   &exec.FunctionImport{Execute: envGetStateSize, GasCost: envGetStateSizeGasCost},
+			"platone_get_state":          &exec.FunctionImport{Execute: platoneGetState, GasCost: envGetStateGasCost},
+			"platone_emit_event":         &exec.FunctionImport{Execute: envEmitEvent, GasCost: envEmitEventGasCost},
+			"platone_emit_event_indexed": &exec.FunctionImport{Execute: envEmitEventIndexed, GasCost: envEmitEventGasCost},
+
+			"platone_call":               &exec.FunctionImport{Execute: platoneCall, GasCost: envBCWasmCallGasCost},
+			"platone_delegate_call":      &exec.FunctionImport{Execute: platoneDelegateCall, GasCost: envBCWasmDelegateCallGasCost},
+			"platone_call_output_length": &exec.FunctionImport{Execute: platoneCallOutputLength, GasCost: constGasFunc(9)},
+			"platone_get_call_output":    &exec.FunctionImport{Execute: platoneGetCallOutput, GasCost: platoneGetInputGasCost},
+			"platone_transfer":           &exec.FunctionImport{Execute: envCallTransfer, GasCost: envCallTransferGasCost},
+
+			"platone_call_contract":          &exec.FunctionImport{Execute: envCallContract, GasCost: envBCWasmCallGasCost},
+			"platone_delegate_call_contract": &exec.FunctionImport{Execute: envDelegateCallContract, GasCost: envBCWasmDelegateCallGasCost},
+			"platone_revert":                 &exec.FunctionImport{Execute: envRevert, GasCost: envRevertGasCost},
+		},
+		// AssemblyScript reports failed assertions through env.abort.
+		"env": {
+			"abort": &exec.FunctionImport{Execute: platoneAbort, GasCost: envAbortGasCost},
+		},
+	}
+}
+
+// define: uint32_t platone_input_length();
+func platoneInputLength(vm *exec.VirtualMachine) int64 {
+	return int64(len(vm.Context.Input))
+}
+
+// define: void platone_get_input(uint8_t *dst);
+func platoneGetInput(vm *exec.VirtualMachine) int64 {
+	dst := int(int32(vm.GetCurrentFrame().Locals[0]))
+	copy(vm.Memory.Memory[dst:dst+len(vm.Context.Input)], vm.Context.Input)
+	return 0
+}
+
+func platoneGetInputGasCost(vm *exec.VirtualMachine) (uint64, error) {
+	return 9 + uint64(len(vm.Context.Input))/32, nil
+}
+
+// define: void platone_return(const uint8_t *src, uint32_t len);
+func platoneReturn(vm *exec.VirtualMachine) int64 {
+	src := int(int32(vm.GetCurrentFrame().Locals[0]))
+	size := int(int32(vm.GetCurrentFrame().Locals[1]))
+	vm.Context.ReturnData = common.CopyBytes(vm.Memory.Memory[src : src+size])
+	return 0
+}
+
+func platoneReturnGasCost(vm *exec.VirtualMachine) (uint64, error) {
+	size := uint64(uint32(vm.GetCurrentFrame().Locals[1]))
+	return 9 + size/32, nil
+}
+
+// define: void platone_panic(const uint8_t *msg, uint32_t len);
+func platonePanic(vm *exec.VirtualMachine) int64 {
+	src := int(int32(vm.GetCurrentFrame().Locals[0]))
+	size := int(int32(vm.GetCurrentFrame().Locals[1]))
+	panic(fmt.Sprintf("contract panic: %s", vm.Memory.Memory[src:src+size]))
+}
+
+// define: void platone_debug(const uint8_t *msg, uint32_t len);
+func platoneDebug(vm *exec.VirtualMachine) int64 {
+	src := int(int32(vm.GetCurrentFrame().Locals[0]))
+	size := int(int32(vm.GetCurrentFrame().Locals[1]))
+	vm.Context.Log.Debug(string(vm.Memory.Memory[src : src+size]))
+	return 0
+}
+
+// define: void platone_balance(const uint8_t addr[20], uint8_t balance[32]);
+func platoneBalance(vm *exec.VirtualMachine) int64 {
+	addr := int(int32(vm.GetCurrentFrame().Locals[0]))
+	dst := int(int32(vm.GetCurrentFrame().Locals[1]))
+	balance := vm.Context.StateDB.GetBalance(common.BytesToAddress(vm.Memory.Memory[addr : addr+common.AddressLength]))
+	if len(balance.Bytes()) > 32 {
+		panic(fmt.Sprintf("balance overflow(%d>32)", len(balance.Bytes())))
+	}
+	copy(vm.Memory.Memory[dst:dst+32], common.LeftPadBytes(balance.Bytes(), 32))
+	return 0
+}
+
+// define: void platone_sha3(const uint8_t *src, uint32_t len, uint8_t hash[32]);
+func platoneSha3(vm *exec.VirtualMachine) int64 {
+	src := int(int32(vm.GetCurrentFrame().Locals[0]))
+	size := int(int32(vm.GetCurrentFrame().Locals[1]))
+	dst := int(int32(vm.GetCurrentFrame().Locals[2]))
+	copy(vm.Memory.Memory[dst:dst+32], crypto.Keccak256(vm.Memory.Memory[src:src+size]))
+	return 0
+}
+
+// define: int32_t platone_get_state(const uint8_t *key, uint32_t keyLen, uint8_t *val, uint32_t valLen);
+// returns the length of the value, which is only copied if it fits into val.
+func platoneGetState(vm *exec.VirtualMachine) int64 {
+	key := int(int32(vm.GetCurrentFrame().Locals[0]))
+	keyLen := int(int32(vm.GetCurrentFrame().Locals[1]))
+	dst := int(int32(vm.GetCurrentFrame().Locals[2]))
+	dstLen := int(int32(vm.GetCurrentFrame().Locals[3]))
+
+	val := vm.Context.StateDB.GetState(vm.Memory.Memory[key : key+keyLen])
+	if len(val) <= dstLen {
+		copy(vm.Memory.Memory[dst:dst+len(val)], val)
+	}
+	return int64(len(val))
+}
+
+// define: int32_t platone_call(const uint8_t addr[20], const uint8_t *input, uint32_t len);
+// returns a call status code, the output is read with platone_get_call_output.
+func platoneCall(vm *exec.VirtualMachine) int64 {
+	addr := int(int32(vm.GetCurrentFrame().Locals[0]))
+	input := int(int32(vm.GetCurrentFrame().Locals[1]))
+	inputLen := int(int32(vm.GetCurrentFrame().Locals[2]))
+
+	ret, err := vm.Context.StateDB.Call(vm.Memory.Memory[addr:addr+common.AddressLength], vm.Memory.Memory[input:input+inputLen])
+	return setCallOutput(vm, ret, err)
+}
+
+// define: int32_t platone_delegate_call(const uint8_t addr[20], const uint8_t *input, uint32_t len);
+func platoneDelegateCall(vm *exec.VirtualMachine) int64 {
+	addr := int(int32(vm.GetCurrentFrame().Locals[0]))
+	input := int(int32(vm.GetCurrentFrame().Locals[1]))
+	inputLen := int(int32(vm.GetCurrentFrame().Locals[2]))
+
+	ret, err := vm.Context.StateDB.DelegateCall(vm.Memory.Memory[addr:addr+common.AddressLength], vm.Memory.Memory[input:input+inputLen])
+	return setCallOutput(vm, ret, err)
+}
+
+// define: uint32_t platone_call_output_length();
+func platoneCallOutputLength(vm *exec.VirtualMachine) int64 {
+	return int64(len(vm.Context.CallOutput))
+}
+
+// define: void platone_get_call_output(uint8_t *dst);
+func platoneGetCallOutput(vm *exec.VirtualMachine) int64 {
+	dst := int(int32(vm.GetCurrentFrame().Locals[0]))
+	copy(vm.Memory.Memory[dst:dst+len(vm.Context.CallOutput)], vm.Context.CallOutput)
+	return 0
+}
+
+// define: void abort(usize message, usize fileName, u32 line, u32 column);
+// message and fileName are AssemblyScript strings.
+func platoneAbort(vm *exec.VirtualMachine) int64 {
+	msg := readAsString(vm, int(int32(vm.GetCurrentFrame().Locals[0])))
+	file := readAsString(vm, int(int32(vm.GetCurrentFrame().Locals[1])))
+	line := uint32(vm.GetCurrentFrame().Locals[2])
+	column := uint32(vm.GetCurrentFrame().Locals[3])
+	panic(fmt.Sprintf("abort: %s at %s:%d:%d", msg, file, line, column))
+}
+
+// readAsString reads an AssemblyScript string: UTF-16LE code units preceded
+// by their byte length at ptr-4.
+func readAsString(vm *exec.VirtualMachine, ptr int) string {
+	if ptr < 4 || ptr > len(vm.Memory.Memory) {
+		return ""
+	}
+	mem := vm.Memory.Memory
+	size := int(uint32(mem[ptr-4]) | uint32(mem[ptr-3])<<8 | uint32(mem[ptr-2])<<16 | uint32(mem[ptr-1])<<24)
+	if size < 0 || ptr+size > len(mem) {
+		return ""
+	}
+	units := make([]uint16, size/2)
+	for i := range units {
+		units[i] = uint16(mem[ptr+2*i]) | uint16(mem[ptr+2*i+1])<<8
+	}
+	return string(utf16.Decode(units))
+}
@@ -0,0 +1,34 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/life/exec"
+)
+
+func TestPlatoneFuncSet(t *testing.T) {
+	r := NewResolver(binaryAbi)
+	for _, field := range []string{"platone_input_length", "platone_get_input", "platone_return", "platone_set_state", "platone_call"} {
+		if f := r.ResolveFunc(platoneModule, field); f.Execute == nil || f.GasCost == nil {
+			t.Errorf("import %s not resolved", field)
+		}
+	}
+	// C++ only imports must not leak into the binary abi import set
+	if _, ok := pfc["env"]["malloc"]; ok {
+		t.Error("env.malloc resolved for binary abi contracts")
+	}
+}
+
+func TestReadAsString(t *testing.T) {
+	mem := make([]byte, 64)
+	// "ok" as UTF-16LE with its byte length in front
+	copy(mem[16:], []byte{4, 0, 0, 0, 'o', 0, 'k', 0})
+	vm := &exec.VirtualMachine{Memory: &exec.Memory{Memory: mem}}
+
+	if s := readAsString(vm, 20); s != "ok" {
+		t.Fatalf("readAsString = %q, want %q", s, "ok")
+	}
+	if s := readAsString(vm, 2); s != "" {
+		t.Fatalf("readAsString out of bounds = %q", s)
+	}
+}
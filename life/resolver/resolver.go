@@ -7,6 +7,8 @@ import (
 var (
 	clang  int = 0x01
 	golang int = 0x02
+	// contracts using the binary abi, whatever their source language
+	binaryAbi int = 0x03
 )
 
 // new import resolver
@@ -15,6 +17,8 @@ func NewResolver(lang int) exec.ImportResolver {
 	case clang:
 		return &CResolver{}
 	case golang:
+	case binaryAbi:
+		return &BinaryResolver{}
 	default:
 	}
 	return nil
@@ -1,36 +1,127 @@
 package utils
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/life/codec"
+)
+
+const (
+	// AbiVersionRlp is the ABI of contracts built with the C++ BCWasm library.
+	AbiVersionRlp = 1
+	// AbiVersionBinary is the length-prefixed binary ABI, see package codec.
+	AbiVersionBinary = codec.Version
 )
 
+// WasmAbi is the abi file of a wasm contract. Version 1 files are a bare
+// json array of functions, later versions are an object:
+//
+//	{"version": 2, "structs": [...], "abiArr": [...]}
 type WasmAbi struct {
-	AbiArr []AbiStruct	`json:"abiArr"`
+	Version int         `json:"version"`
+	Structs []StructDef `json:"structs,omitempty"`
+	AbiArr  []AbiStruct `json:"abiArr"`
 }
 
 type AbiStruct struct {
-	Name string				`json:"name"`
-	Inputs []InputParam 	`json:"inputs"`
-	Outputs []OutputsParam 	`json:"outputs"`
-	Constant string 		`json:"constant"`
-	Type 	string 			`json:"type"`
+	Name     string         `json:"name"`
+	Inputs   []InputParam   `json:"inputs"`
+	Outputs  []OutputsParam `json:"outputs"`
+	Constant string         `json:"constant"`
+	Type     string         `json:"type"`
 }
 
 type InputParam struct {
-	Name string		`json:"name"`
-	Type string		`json:"type"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Indexed bool   `json:"indexed,omitempty"` // event parameters only
 }
 
 type OutputsParam struct {
-	Name string 	`json:"name"`
-	Type string 	`json:"type"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// StructDef declares a struct type usable as a parameter type in version 2 abi files.
+type StructDef struct {
+	Name   string       `json:"name"`
+	Fields []InputParam `json:"fields"`
 }
 
 func (abi *WasmAbi) FromJson(body []byte) error {
 	if body == nil {
 		return fmt.Errorf("invalid param. %v", body)
 	}
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '{' {
+		type plain WasmAbi
+		if err := json.Unmarshal(trimmed, (*plain)(abi)); err != nil {
+			return err
+		}
+		if abi.Version < AbiVersionRlp || abi.Version > AbiVersionBinary {
+			return fmt.Errorf("unsupported abi version %d", abi.Version)
+		}
+		return nil
+	}
+	abi.Version = AbiVersionRlp
 	err := json.Unmarshal(body, &abi.AbiArr)
 	return err
-}
\ No newline at end of file
+}
+
+// IsBinary reports whether the contract uses the binary ABI.
+func (abi *WasmAbi) IsBinary() bool {
+	return abi.Version == AbiVersionBinary
+}
+
+// Function returns the declaration of the named function.
+func (abi *WasmAbi) Function(name string) (*AbiStruct, bool) {
+	for i := range abi.AbiArr {
+		if abi.AbiArr[i].Name == name && abi.AbiArr[i].Type == "function" {
+			return &abi.AbiArr[i], true
+		}
+	}
+	return nil, false
+}
+
+// StructDefs converts the struct declarations for use with package codec.
+func (abi *WasmAbi) StructDefs() []codec.StructDef {
+	defs := make([]codec.StructDef, 0, len(abi.Structs))
+	for _, s := range abi.Structs {
+		def := codec.StructDef{Name: s.Name}
+		for _, f := range s.Fields {
+			def.Fields = append(def.Fields, codec.FieldDef{Name: f.Name, Type: f.Type})
+		}
+		defs = append(defs, def)
+	}
+	return defs
+}
+
+// InputTypes parses the parameter types of fn with package codec.
+func (abi *WasmAbi) InputTypes(fn *AbiStruct) ([]*codec.Type, error) {
+	types := make([]*codec.Type, 0, len(fn.Inputs))
+	for _, in := range fn.Inputs {
+		t, err := codec.ParseType(in.Type, abi.StructDefs())
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+	}
+	return types, nil
+}
+
+// OutputTypes parses the return types of fn with package codec.
+func (abi *WasmAbi) OutputTypes(fn *AbiStruct) ([]*codec.Type, error) {
+	types := make([]*codec.Type, 0, len(fn.Outputs))
+	for _, out := range fn.Outputs {
+		if out.Type == "void" {
+			continue
+		}
+		t, err := codec.ParseType(out.Type, abi.StructDefs())
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+	}
+	return types, nil
+}
@@ -0,0 +1,218 @@
+package utils
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/crypto"
+	"github.com/PlatONEnetwork/PlatONE-Go/life/codec"
+	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
+)
+
+// MaxIndexedParams is the number of event parameters that can be indexed,
+// the first topic is always taken by the event name.
+const MaxIndexedParams = 3
+
+// EventTopic returns the first topic of the logs emitted by the named event.
+func EventTopic(name string) common.Hash {
+	return common.BytesToHash(crypto.Keccak256([]byte(name)))
+}
+
+// DecodedEvent is a log decoded against the event declarations of an abi.
+type DecodedEvent struct {
+	Name string       `json:"name"`
+	Args []DecodedArg `json:"args"`
+}
+
+// DecodedArg is a single event parameter. Indexed parameters of dynamic
+// types only carry the hash of their value.
+type DecodedArg struct {
+	Name    string      `json:"name"`
+	Type    string      `json:"type"`
+	Indexed bool        `json:"indexed,omitempty"`
+	Value   interface{} `json:"value"`
+}
+
+// EventByTopic returns the event declaration matching the first topic of a log.
+func (abi *WasmAbi) EventByTopic(topic common.Hash) (*AbiStruct, bool) {
+	for i := range abi.AbiArr {
+		if abi.AbiArr[i].Type == "event" && EventTopic(abi.AbiArr[i].Name) == topic {
+			return &abi.AbiArr[i], true
+		}
+	}
+	return nil, false
+}
+
+// IndexedTopic encodes the value of an indexed event parameter of type typ
+// as a log topic. Fixed size values are stored as a 32 bytes big endian word,
+// signed integers sign extended; strings and bytes are stored as the keccak256
+// hash of their content and other dynamic types as the hash of their encoding.
+func (abi *WasmAbi) IndexedTopic(typ string, value interface{}) (common.Hash, error) {
+	t, err := abi.paramType(typ)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	enc, err := codec.Encode(t, value)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	var word common.Hash
+	switch t.Kind {
+	case codec.BoolKind, codec.UintKind, codec.FloatKind, codec.IntKind:
+		for i := 0; i < t.Size; i++ {
+			word[common.HashLength-1-i] = enc[i]
+		}
+		if t.Kind == codec.IntKind && enc[t.Size-1]&0x80 != 0 {
+			for i := 0; i < common.HashLength-t.Size; i++ {
+				word[i] = 0xff
+			}
+		}
+	case codec.AddressKind:
+		copy(word[common.HashLength-len(enc):], enc)
+	case codec.StringKind, codec.BytesKind:
+		word = common.BytesToHash(crypto.Keccak256(enc[4:]))
+	default:
+		word = common.BytesToHash(crypto.Keccak256(enc))
+	}
+	return word, nil
+}
+
+// DecodeEvent decodes a log emitted by a contract with this abi. Indexed
+// parameters are read from topics[1:], the others from data, which is an rlp
+// list for the C++ abi and a binary tuple for the binary abi.
+func (abi *WasmAbi) DecodeEvent(topics []common.Hash, data []byte) (*DecodedEvent, error) {
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("log has no topics")
+	}
+	ev, ok := abi.EventByTopic(topics[0])
+	if !ok {
+		return nil, fmt.Errorf("no event matches topic %s", topics[0].Hex())
+	}
+
+	var (
+		types   []*codec.Type
+		nonIdx  []*codec.Type
+		indexed = topics[1:]
+	)
+	for _, in := range ev.Inputs {
+		t, err := abi.paramType(in.Type)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+		if !in.Indexed {
+			nonIdx = append(nonIdx, t)
+		}
+	}
+
+	values, err := abi.decodeEventData(nonIdx, data)
+	if err != nil {
+		return nil, fmt.Errorf("event %s: %v", ev.Name, err)
+	}
+
+	decoded := &DecodedEvent{Name: ev.Name}
+	for i, in := range ev.Inputs {
+		arg := DecodedArg{Name: in.Name, Type: in.Type, Indexed: in.Indexed}
+		if in.Indexed {
+			if len(indexed) == 0 {
+				return nil, fmt.Errorf("event %s: missing topic for %s", ev.Name, in.Name)
+			}
+			arg.Value = topicValue(types[i], indexed[0])
+			indexed = indexed[1:]
+		} else {
+			arg.Value, values = values[0], values[1:]
+		}
+		decoded.Args = append(decoded.Args, arg)
+	}
+	return decoded, nil
+}
+
+func (abi *WasmAbi) decodeEventData(types []*codec.Type, data []byte) ([]interface{}, error) {
+	if abi.IsBinary() {
+		return codec.DecodeValues(types, data)
+	}
+	var items [][]byte
+	if len(types) == 0 && len(data) == 0 {
+		return nil, nil
+	}
+	if err := rlp.DecodeBytes(data, &items); err != nil {
+		return nil, err
+	}
+	if len(items) != len(types) {
+		return nil, fmt.Errorf("want %d values, got %d", len(types), len(items))
+	}
+	values := make([]interface{}, len(items))
+	for i, item := range items {
+		values[i] = rlpValue(types[i], item)
+	}
+	return values, nil
+}
+
+// paramType resolves a parameter type of either abi version with package codec.
+func (abi *WasmAbi) paramType(typ string) (*codec.Type, error) {
+	if !abi.IsBinary() {
+		// names only known to the C++ abi
+		switch typ {
+		case "int":
+			typ = "int32"
+		case "uint":
+			typ = "uint32"
+		case "int128_s", "uint128_s", "int256_s", "uint256_s":
+			typ = "string"
+		case "int128", "uint128", "float128":
+			typ = "bytes"
+		}
+	}
+	return codec.ParseType(typ, abi.StructDefs())
+}
+
+// rlpValue converts an rlp item written by the C++ library (big endian,
+// without leading zeros) to the value of type t.
+func rlpValue(t *codec.Type, b []byte) interface{} {
+	var word [8]byte
+	if len(b) <= 8 {
+		copy(word[8-len(b):], b)
+	}
+	n := binary.BigEndian.Uint64(word[:])
+	switch t.Kind {
+	case codec.BoolKind:
+		return n == 1
+	case codec.UintKind:
+		return n
+	case codec.IntKind:
+		shift := uint(64 - t.Size*8)
+		return int64(n<<shift) >> shift
+	case codec.FloatKind:
+		if t.Size == 4 {
+			return math.Float32frombits(uint32(n))
+		}
+		return math.Float64frombits(n)
+	case codec.StringKind:
+		return string(b)
+	}
+	return common.CopyBytes(b)
+}
+
+// topicValue is the inverse of IndexedTopic, dynamic types yield the hash.
+func topicValue(t *codec.Type, topic common.Hash) interface{} {
+	n := binary.BigEndian.Uint64(topic[common.HashLength-8:])
+	switch t.Kind {
+	case codec.BoolKind:
+		return n == 1
+	case codec.UintKind:
+		return n
+	case codec.IntKind:
+		shift := uint(64 - t.Size*8)
+		return int64(n<<shift) >> shift
+	case codec.FloatKind:
+		if t.Size == 4 {
+			return math.Float32frombits(uint32(n))
+		}
+		return math.Float64frombits(n)
+	case codec.AddressKind:
+		return common.BytesToAddress(topic[common.HashLength-common.AddressLength:])
+	}
+	return topic
+}
@@ -0,0 +1,94 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/crypto"
+	"github.com/PlatONEnetwork/PlatONE-Go/life/codec"
+	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
+)
+
+const rlpEventAbi = `[{"name":"Transfer","type":"event","inputs":[
+	{"name":"to","type":"string","indexed":true},
+	{"name":"amount","type":"uint64"},
+	{"name":"delta","type":"int32"},
+	{"name":"memo","type":"string"}]}]`
+
+const binaryEventAbi = `{"version":2,"abiArr":[{"name":"Moved","type":"event","inputs":[
+	{"name":"who","type":"address","indexed":true},
+	{"name":"offset","type":"int16","indexed":true},
+	{"name":"tags","type":"string[]"}]}]}`
+
+func parseAbi(t *testing.T, s string) *WasmAbi {
+	abi := new(WasmAbi)
+	if err := abi.FromJson([]byte(s)); err != nil {
+		t.Fatal(err)
+	}
+	return abi
+}
+
+func TestDecodeRlpEvent(t *testing.T) {
+	abi := parseAbi(t, rlpEventAbi)
+	to, err := abi.IndexedTopic("string", "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if to != common.BytesToHash(crypto.Keccak256([]byte("bob"))) {
+		t.Fatalf("string topic = %x", to)
+	}
+
+	// the C++ library writes signed integers as uint64
+	minusTwo := int64(-2)
+	data, _ := rlp.EncodeToBytes([]interface{}{uint64(7), uint64(minusTwo), "hi"})
+	ev, err := abi.DecodeEvent([]common.Hash{EventTopic("Transfer"), to}, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []interface{}{to, uint64(7), int64(-2), "hi"}
+	for i, arg := range ev.Args {
+		if !reflect.DeepEqual(arg.Value, want[i]) {
+			t.Errorf("arg %s = %#v, want %#v", arg.Name, arg.Value, want[i])
+		}
+	}
+
+	if _, err := abi.DecodeEvent([]common.Hash{EventTopic("Transfer")}, data); err == nil {
+		t.Error("decoded event with a missing indexed topic")
+	}
+	if _, err := abi.DecodeEvent([]common.Hash{EventTopic("Other")}, data); err == nil {
+		t.Error("decoded undeclared event")
+	}
+}
+
+func TestDecodeBinaryEvent(t *testing.T) {
+	abi := parseAbi(t, binaryEventAbi)
+	addr := common.HexToAddress("0x1000000000000000000000000000000000000002")
+	who, err := abi.IndexedTopic("address", addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	offset, err := abi.IndexedTopic("int16", -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if offset != common.HexToHash("0xffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff") {
+		t.Fatalf("int16 topic not sign extended: %x", offset)
+	}
+
+	tags, _ := codec.ParseType("string[]", nil)
+	data, err := codec.EncodeValues([]*codec.Type{tags}, []interface{}{[]string{"a", "b"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev, err := abi.DecodeEvent([]common.Hash{EventTopic("Moved"), who, offset}, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []interface{}{addr, int64(-1), []interface{}{"a", "b"}}
+	for i, arg := range ev.Args {
+		if !reflect.DeepEqual(arg.Value, want[i]) {
+			t.Errorf("arg %s = %#v, want %#v", arg.Name, arg.Value, want[i])
+		}
+	}
+}
@@ -0,0 +1,310 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"reflect"
+
+	solabi "github.com/PlatONEnetwork/PlatONE-Go/accounts/abi"
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/life/codec"
+	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
+)
+
+// SolidityCall is a call to a wasm contract function encoded with the
+// solidity abi: the keccak256 selector of the function signature followed by
+// the abi encoded arguments. It lets solidity contracts and tools call wasm
+// contracts without knowing their calling convention.
+type SolidityCall struct {
+	abi    *WasmAbi
+	fn     *AbiStruct
+	method solabi.Method
+}
+
+// SolidityMethod describes fn as a solidity method. Functions using a type
+// without a solidity equivalent, such as floats, maps or structs, are
+// rejected.
+func (abi *WasmAbi) SolidityMethod(fn *AbiStruct) (solabi.Method, error) {
+	m := solabi.Method{Name: fn.Name, Const: fn.Constant == "true"}
+	for _, in := range fn.Inputs {
+		arg, err := abi.solidityArgument(in.Name, in.Type)
+		if err != nil {
+			return m, err
+		}
+		m.Inputs = append(m.Inputs, arg)
+	}
+	for _, out := range fn.Outputs {
+		if out.Type == "void" {
+			continue
+		}
+		// the C++ library returns nothing for bool results
+		if !abi.IsBinary() && out.Type == "bool" {
+			return m, fmt.Errorf("unsupported return type %s", out.Type)
+		}
+		arg, err := abi.solidityArgument(out.Name, out.Type)
+		if err != nil {
+			return m, err
+		}
+		m.Outputs = append(m.Outputs, arg)
+	}
+	return m, nil
+}
+
+// SolidityCall returns the function called by a solidity encoded input.
+// Inputs in the calling convention of the contract are never mistaken for
+// one.
+func (abi *WasmAbi) SolidityCall(input []byte) (*SolidityCall, bool) {
+	if len(input) < 4 || codec.IsBinaryInput(input) || abi.isRlpCall(input) {
+		return nil, false
+	}
+	for i := range abi.AbiArr {
+		fn := &abi.AbiArr[i]
+		if fn.Type != "function" {
+			continue
+		}
+		m, err := abi.SolidityMethod(fn)
+		if err != nil {
+			continue
+		}
+		if bytes.Equal(m.Id(), input[:4]) {
+			return &SolidityCall{abi: abi, fn: fn, method: m}, true
+		}
+	}
+	return nil, false
+}
+
+// Function returns the name of the called function.
+func (c *SolidityCall) Function() string {
+	return c.fn.Name
+}
+
+// WasmInput translates the solidity input to the calling convention of the
+// contract, asking for its result in the raw format understood by
+// SolidityOutput.
+func (c *SolidityCall) WasmInput(input []byte) ([]byte, error) {
+	args, err := c.method.Inputs.UnpackValues(input[4:])
+	if err != nil {
+		return nil, err
+	}
+	if c.abi.IsBinary() {
+		types, err := c.abi.InputTypes(c.fn)
+		if err != nil {
+			return nil, err
+		}
+		data, err := codec.EncodeValues(types, args)
+		if err != nil {
+			return nil, err
+		}
+		return codec.EncodeInput(common.CallContractFlag, c.fn.Name, data), nil
+	}
+
+	items := [][]byte{common.Int64ToBytes(common.CallContractFlag), []byte(c.fn.Name)}
+	for i, in := range c.fn.Inputs {
+		b, err := rlpArgument(in.Type, args[i])
+		if err != nil {
+			return nil, fmt.Errorf("argument %s: %v", in.Name, err)
+		}
+		items = append(items, b)
+	}
+	return rlp.EncodeToBytes(items)
+}
+
+// SolidityOutput translates the result of the contract to the solidity
+// encoding.
+func (c *SolidityCall) SolidityOutput(ret []byte) ([]byte, error) {
+	var vals []interface{}
+	if c.abi.IsBinary() {
+		types, err := c.abi.OutputTypes(c.fn)
+		if err != nil {
+			return nil, err
+		}
+		if vals, err = codec.DecodeValues(types, ret); err != nil {
+			return nil, err
+		}
+	} else if len(c.method.Outputs) > 0 {
+		v, err := rlpResult(c.fn.Outputs[0].Type, ret)
+		if err != nil {
+			return nil, err
+		}
+		vals = []interface{}{v}
+	}
+
+	args := make([]interface{}, len(vals))
+	for i, v := range vals {
+		rv, err := solidityValue(c.method.Outputs[i].Type, v)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = rv.Interface()
+	}
+	return c.method.Outputs.Pack(args...)
+}
+
+// isRlpCall reports whether input is an rlp encoded call of a function of
+// the contract.
+func (abi *WasmAbi) isRlpCall(input []byte) bool {
+	var items [][]byte
+	if err := rlp.DecodeBytes(input, &items); err != nil || len(items) < 2 {
+		return false
+	}
+	_, ok := abi.Function(string(items[1]))
+	return ok
+}
+
+func (abi *WasmAbi) solidityArgument(name, typ string) (solabi.Argument, error) {
+	var (
+		sol string
+		err error
+	)
+	if abi.IsBinary() {
+		var t *codec.Type
+		if t, err = codec.ParseType(typ, abi.StructDefs()); err == nil {
+			sol, err = solidityType(t)
+		}
+	} else {
+		sol, err = rlpSolidityType(typ)
+	}
+	if err != nil {
+		return solabi.Argument{}, err
+	}
+	t, err := solabi.NewType(sol)
+	if err != nil {
+		return solabi.Argument{}, err
+	}
+	return solabi.Argument{Name: name, Type: t}, nil
+}
+
+// rlpSolidityType maps the types of the C++ library.
+func rlpSolidityType(typ string) (string, error) {
+	switch typ {
+	case "int8", "int16", "int32", "int64", "int128",
+		"uint8", "uint16", "uint32", "uint64", "uint128", "bool", "string":
+		return typ, nil
+	case "int":
+		return "int32", nil
+	case "uint":
+		return "uint32", nil
+	}
+	return "", fmt.Errorf("unsupported type %s", typ)
+}
+
+// solidityType maps the types of package codec.
+func solidityType(t *codec.Type) (string, error) {
+	switch t.Kind {
+	case codec.BoolKind:
+		return "bool", nil
+	case codec.IntKind:
+		return fmt.Sprintf("int%d", t.Size*8), nil
+	case codec.UintKind:
+		return fmt.Sprintf("uint%d", t.Size*8), nil
+	case codec.AddressKind:
+		return "address", nil
+	case codec.StringKind:
+		return "string", nil
+	case codec.BytesKind:
+		return "bytes", nil
+	case codec.ArrayKind:
+		elem, err := solidityType(t.Elem)
+		return elem + "[]", err
+	}
+	return "", fmt.Errorf("unsupported type %s", t)
+}
+
+// rlpArgument encodes an argument the way the C++ library expects it: big
+// endian integers of the width of the type, raw strings.
+func rlpArgument(typ string, v interface{}) ([]byte, error) {
+	switch n := v.(type) {
+	case string:
+		return []byte(n), nil
+	case bool:
+		if n {
+			return []byte{1}, nil
+		}
+		return []byte{0}, nil
+	case *big.Int:
+		// int128 and uint128, two's complement
+		return common.LeftPadBytes(new(big.Int).And(n, maxUint128).Bytes(), 16), nil
+	}
+	rv := reflect.ValueOf(v)
+	var n uint64
+	switch rv.Kind() {
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n = uint64(rv.Int())
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n = rv.Uint()
+	default:
+		return nil, fmt.Errorf("cannot encode %T as %s", v, typ)
+	}
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, n)
+	return b[8-int(rv.Type().Size()):], nil
+}
+
+var maxUint128 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+
+// rlpResult decodes a result returned by the C++ library to a call made with
+// common.CallContractFlag.
+func rlpResult(typ string, ret []byte) (interface{}, error) {
+	switch typ {
+	case "string":
+		return string(ret), nil
+	case "int128", "uint128":
+		if len(ret) != 16 {
+			return nil, fmt.Errorf("invalid %s result of %d bytes", typ, len(ret))
+		}
+		n := new(big.Int).SetBytes(ret)
+		if typ == "int128" && ret[0]&0x80 != 0 {
+			n.Sub(n, new(big.Int).Lsh(big.NewInt(1), 128))
+		}
+		return n, nil
+	}
+	if len(ret) != 8 {
+		return nil, fmt.Errorf("invalid %s result of %d bytes", typ, len(ret))
+	}
+	n := binary.BigEndian.Uint64(ret)
+	if typ[0] == 'u' {
+		return n, nil
+	}
+	return int64(n), nil
+}
+
+// solidityValue converts a decoded value to the go type packed by t.
+func solidityValue(t solabi.Type, v interface{}) (reflect.Value, error) {
+	switch t.T {
+	case solabi.SliceTy:
+		elems, ok := v.([]interface{})
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("cannot use %T as %s", v, t)
+		}
+		s := reflect.MakeSlice(t.Type, len(elems), len(elems))
+		for i, e := range elems {
+			ev, err := solidityValue(*t.Elem, e)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			s.Index(i).Set(ev)
+		}
+		return s, nil
+	case solabi.IntTy, solabi.UintTy:
+		if n, ok := v.(*big.Int); ok {
+			return reflect.ValueOf(n), nil
+		}
+		rv := reflect.ValueOf(v)
+		if t.Size > 64 {
+			// 128 bit values of the binary abi do not exist, this keeps the
+			// conversion total
+			if rv.Kind() == reflect.Int64 {
+				return reflect.ValueOf(big.NewInt(rv.Int())), nil
+			}
+			return reflect.ValueOf(new(big.Int).SetUint64(rv.Uint())), nil
+		}
+		return rv.Convert(t.Type), nil
+	}
+	rv := reflect.ValueOf(v)
+	if !rv.IsValid() || !rv.Type().ConvertibleTo(t.Type) {
+		return reflect.Value{}, fmt.Errorf("cannot use %T as %s", v, t)
+	}
+	return rv.Convert(t.Type), nil
+}
@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/crypto"
+	"github.com/PlatONEnetwork/PlatONE-Go/life/codec"
+	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
+)
+
+const rlpFuncAbi = `[
+	{"name":"add","type":"function","inputs":[{"name":"a","type":"int"},{"name":"memo","type":"string"}],"outputs":[{"name":"","type":"int64"}]},
+	{"name":"rate","type":"function","inputs":[{"name":"f","type":"float64"}],"outputs":[{"name":"","type":"void"}]}]`
+
+const binaryFuncAbi = `{"version":2,"abiArr":[
+	{"name":"tag","type":"function","inputs":[{"name":"who","type":"address"},{"name":"ids","type":"uint16[]"}],"outputs":[{"name":"","type":"string"}]}]}`
+
+func selector(sig string) []byte {
+	return crypto.Keccak256([]byte(sig))[:4]
+}
+
+func TestSolidityCallRlp(t *testing.T) {
+	abi := parseAbi(t, rlpFuncAbi)
+	fn, _ := abi.Function("add")
+	m, err := abi.SolidityMethod(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(m.Id(), selector("add(int32,string)")) {
+		t.Fatalf("selector of %s = %x", m.Sig(), m.Id())
+	}
+	args, _ := m.Inputs.Pack(int32(-3), "hi")
+	input := append(m.Id(), args...)
+
+	call, ok := abi.SolidityCall(input)
+	if !ok {
+		t.Fatal("solidity call not recognised")
+	}
+	wasmInput, err := call.WasmInput(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var items [][]byte
+	if err := rlp.DecodeBytes(wasmInput, &items); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]byte{common.Int64ToBytes(common.CallContractFlag), []byte("add"), {0xff, 0xff, 0xff, 0xfd}, []byte("hi")}
+	if !reflect.DeepEqual(items, want) {
+		t.Fatalf("wasm input = %x, want %x", items, want)
+	}
+	// the translated input is a call of the contract, not a solidity call
+	if _, ok := abi.SolidityCall(wasmInput); ok {
+		t.Fatal("rlp input taken for a solidity call")
+	}
+
+	out, err := call.SolidityOutput(common.Int64ToBytes(-5))
+	if err != nil {
+		t.Fatal(err)
+	}
+	vals, err := m.Outputs.UnpackValues(out)
+	if err != nil || !reflect.DeepEqual(vals, []interface{}{int64(-5)}) {
+		t.Fatalf("output = %v, %v", vals, err)
+	}
+
+	// floats have no solidity equivalent
+	if _, ok := abi.SolidityCall(selector("rate(float64)")); ok {
+		t.Fatal("float function callable from solidity")
+	}
+}
+
+func TestSolidityCallBinary(t *testing.T) {
+	abi := parseAbi(t, binaryFuncAbi)
+	fn, _ := abi.Function("tag")
+	m, err := abi.SolidityMethod(fn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := common.HexToAddress("0x1000000000000000000000000000000000000002")
+	args, _ := m.Inputs.Pack(addr, []uint16{1, 2})
+	input := append(selector("tag(address,uint16[])"), args...)
+
+	call, ok := abi.SolidityCall(input)
+	if !ok {
+		t.Fatal("solidity call not recognised")
+	}
+	wasmInput, err := call.WasmInput(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txType, name, data, err := codec.DecodeInput(wasmInput)
+	if err != nil || txType != common.CallContractFlag || name != "tag" {
+		t.Fatalf("wasm input = %d %s, %v", txType, name, err)
+	}
+	types, _ := abi.InputTypes(fn)
+	vals, err := codec.DecodeValues(types, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []interface{}{addr, []interface{}{uint64(1), uint64(2)}}; !reflect.DeepEqual(vals, want) {
+		t.Fatalf("arguments = %#v, want %#v", vals, want)
+	}
+
+	str, _ := codec.ParseType("string", nil)
+	ret, _ := codec.Encode(str, "ok")
+	out, err := call.SolidityOutput(ret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := m.Outputs.UnpackValues(out)
+	if err != nil || !reflect.DeepEqual(res, []interface{}{"ok"}) {
+		t.Fatalf("output = %v, %v", res, err)
+	}
+}
@@ -75,6 +75,10 @@ const (
 	staleThreshold = 7
 
 	defaultCommitRatio = 0.95
+
+	// packingBudgetPercent is the share of the block period the transactions of a
+	// block may take to execute.
+	packingBudgetPercent = 50
 )
 
 // environment is the worker's current environment and holds all of the current state information.
@@ -85,9 +89,13 @@ type environment struct {
 	tcount  int            // tx count in cycle
 	gasPool *core.GasPool  // available gas used to pack transactions
 
+	executor *core.ParallelExecutor // executes transactions ahead in parallel, if enabled
+
 	header   *types.Header
 	txs      []*types.Transaction
 	receipts []*types.Receipt
+
+	deadline time.Time // when packing stops to keep the block within its execution budget, zero for no budget
 }
 
 // task contains all information for consensus engine sealing and result submitting.
@@ -632,16 +640,26 @@ func (w *worker) makeCurrent(parent *types.Block, header *types.Header) error {
 	return nil
 }
 
+// systemConfigAt returns the system configuration in force for the block of
+// header, the one after the head if the chain cannot resolve it.
+func (w *worker) systemConfigAt(header *types.Header) *common.SystemConfig {
+	if config := w.chain.SystemConfigAt(header); config != nil {
+		return config
+	}
+	return common.SysCfg
+}
+
 // updateSnapshot updates pending snapshot block and state.
 // Note this function assumes the current variable is thread safe.
 func (w *worker) updateSnapshot(block *types.Block) {
 	w.snapshotMu.Lock()
 	defer w.snapshotMu.Unlock()
 	if block == nil {
-		w.snapshotBlock = types.NewBlock(
+		w.snapshotBlock = types.NewBlockWith(
 			w.current.header,
 			w.current.txs,
 			w.current.receipts,
+			w.systemConfigAt(w.current.header).IsBlockUseTrieHash(),
 		)
 	} else {
 		w.snapshotBlock = block
@@ -652,7 +670,15 @@ func (w *worker) updateSnapshot(block *types.Block) {
 func (w *worker) commitTransaction(tx *types.Transaction, coinbase common.Address) ([]*types.Log, error) {
 	snap := w.current.state.Snapshot()
 
-	receipt, _, err := core.ApplyTransaction(w.config, w.chain, &coinbase, w.current.gasPool, w.current.state, w.current.header, tx, &w.current.header.GasUsed, vm.Config{})
+	var (
+		receipt *types.Receipt
+		err     error
+	)
+	if w.current.executor != nil {
+		receipt, err = w.current.executor.Apply(tx, w.current.tcount, &w.current.header.GasUsed)
+	} else {
+		receipt, _, err = core.ApplyTransaction(w.config, w.chain, &coinbase, w.current.gasPool, w.current.state, w.current.header, tx, &w.current.header.GasUsed, vm.Config{})
+	}
 	if err != nil {
 		w.current.state.RevertToSnapshot(snap)
 		return nil, err
@@ -674,6 +700,9 @@ func (w *worker) commitTransactionsWithHeader(header *types.Header, txs *types.T
 	if w.current.gasPool == nil {
 		w.current.gasPool = new(core.GasPool).AddGas(w.current.header.GasLimit)
 	}
+	if w.config.ParallelExecution && w.current.executor == nil {
+		w.current.executor = core.NewParallelExecutor(w.config, w.chain, &coinbase, w.current.gasPool, w.current.state, w.current.header, common.Hash{}, vm.Config{})
+	}
 
 	var coalescedLogs []*types.Log
 
@@ -703,17 +732,45 @@ func (w *worker) commitTransactionsWithHeader(header *types.Header, txs *types.T
 			log.Trace("Not enough gas for further transactions", "have", w.current.gasPool, "want", params.TxGas)
 			break
 		}
+		// The validators refuse the transactions starting past the gas budget
+		// of a round
+		if w.config.Istanbul != nil {
+			if budget := w.config.Istanbul.GasBudget(); budget > 0 && w.current.header.GasUsed >= budget {
+				log.Debug("Gas budget of the block exhausted", "blockNumber", header.Number, "used", w.current.header.GasUsed, "budget", budget)
+				break
+			}
+		}
 		// Retrieve the next transaction and abort if all done
 		tx := txs.Peek()
 		if tx == nil {
 			break
 		}
+		// Stop once the execution budget of the block is spent, and leave the
+		// transactions expected to overrun it to the next blocks
+		if !w.current.deadline.IsZero() {
+			remaining := time.Until(w.current.deadline)
+			if remaining <= 0 {
+				log.Debug("Execution budget of the block exhausted", "blockNumber", header.Number, "txs", w.current.tcount)
+				break
+			}
+			if estimate := core.ExecStats.Estimate(tx); estimate > remaining {
+				log.Trace("Skipping account with transaction over the execution budget", "hash", tx.Hash(), "estimate", estimate, "remaining", remaining)
+				txs.Pop()
+				continue
+			}
+		}
 		// Error may be ignored here. The error has already been checked
 		// during transaction acceptance is the transaction pool.
 		//
 		// We use the eip155 signer regardless of the current hf.
 		from, _ := types.Sender(w.current.signer, tx)
 
+		// Execute the next transactions of the accounts ahead in parallel,
+		// leaving out those declaring accesses overlapping others
+		if w.current.executor != nil && !w.current.executor.Speculated(tx) {
+			heads := txs.Heads(2 * core.ParallelBatch)
+			w.current.executor.Speculate(core.ScheduleParallel(w.current.signer, heads, core.ParallelBatch))
+		}
 		// Start executing the transaction
 		rpc.MonitorWriteData(rpc.TransactionExecuteStartTime, tx.Hash().String(), "", w.extdb)
 		w.current.state.Prepare(tx.Hash(), common.Hash{}, w.current.tcount)
@@ -737,6 +794,11 @@ func (w *worker) commitTransactionsWithHeader(header *types.Header, txs *types.T
 			log.Warn("Skipping account with hight nonce", "blockNumber", header.Number, "blockParentHash", header.ParentHash, "tx.hash", tx.Hash(), "sender", from, "senderCurNonce", w.current.state.GetNonce(from), "tx.nonce", tx.Nonce())
 			txs.Pop()
 			rpc.MonitorWriteData(rpc.TransactionExecuteStatus, tx.Hash().String(), "false", w.extdb)
+		case core.ErrTxNotYetValid, core.ErrTxExpired:
+			// Outside its validity window, the pool drops it once it expired
+			log.Debug("Skipping transaction outside its validity window", "blockNumber", header.Number, "tx.hash", tx.Hash(), "err", err)
+			txs.Shift()
+			rpc.MonitorWriteData(rpc.TransactionExecuteStatus, tx.Hash().String(), "false", w.extdb)
 		case nil:
 			// Everything ok, collect the logs and shift in the next transaction from the same account
 			coalescedLogs = append(coalescedLogs, logs...)
@@ -748,6 +810,7 @@ func (w *worker) commitTransactionsWithHeader(header *types.Header, txs *types.T
 			// nonce-too-high clause will prevent us from executing in vain).
 			log.Warn("Transaction failed, account skipped", "blockNumber", header.Number, "blockParentHash", header.ParentHash, "hash", tx.Hash(), "hash", tx.Hash(), "err", err)
 			txs.Shift()
+			w.eth.TxPool().TrackFailed(tx.Hash(), err)
 			rpc.MonitorWriteData(rpc.TransactionExecuteStatus, tx.Hash().String(), "false", w.extdb)
 		}
 	}
@@ -803,10 +866,10 @@ func (w *worker) commitNewWork(interrupt *int32, timestamp int64, commitBlock *t
 	header := &types.Header{
 		ParentHash: parent.Hash(),
 		Number:     num.Add(num, common.Big1),
-		GasLimit:   core.CalcGasLimit(parent, w.gasFloor, w.gasCeil),
 		Extra:      w.extra,
 		Time:       big.NewInt(timestamp),
 	}
+	header.GasLimit = core.CalcGasLimit(parent, w.systemConfigAt(header), w.gasFloor, w.gasCeil)
 	// Only set the coinbase if our consensus engine is running (avoid spurious block rewards)
 	if w.isRunning() {
 		/*
@@ -832,10 +895,13 @@ func (w *worker) commitNewWork(interrupt *int32, timestamp int64, commitBlock *t
 		log.Error("Failed to create mining context", "err", err)
 		return
 	}
+	if budget := packingBudget(w.config.Istanbul); budget > 0 {
+		w.current.deadline = time.Now().Add(budget)
+	}
 
 	// Fill the block with all available pending transactions.
 	startTime := time.Now()
-	pending, err := w.eth.TxPool().PendingLimited()
+	lanes, err := w.eth.TxPool().PendingLanes()
 
 	if err != nil {
 		log.Error("Failed to fetch pending transactions", "time", common.PrettyDuration(time.Since(startTime)), "err", err)
@@ -844,8 +910,14 @@ func (w *worker) commitNewWork(interrupt *int32, timestamp int64, commitBlock *t
 
 	//log.Info("Fetch pending transactions success", "pendingLength", len(pending), "time", common.PrettyDuration(time.Since(startTime)))
 
+	txsCount := 0
+	for _, lane := range lanes {
+		for _, accTxs := range lane {
+			txsCount = txsCount + len(accTxs)
+		}
+	}
 	// Short circuit if there is no available pending transactions
-	if len(pending) == 0 {
+	if txsCount == 0 {
 		if _, ok := w.engine.(consensus.Istanbul); ok {
 			w.commit(nil, true, tstart)
 		} else {
@@ -853,32 +925,31 @@ func (w *worker) commitNewWork(interrupt *int32, timestamp int64, commitBlock *t
 		}
 		return
 	}
+	log.Debug("execute pending transactions", "lanes", len(lanes), "txsCount", txsCount)
 
-	txsCount := 0
-	for _, accTxs := range pending {
-		txsCount = txsCount + len(accTxs)
-	}
-	// Split the pending transactions into locals and remotes
-	localTxs, remoteTxs := make(map[common.Address]types.Transactions), pending
-	for _, account := range w.eth.TxPool().Locals() {
-		if txs := remoteTxs[account]; len(txs) > 0 {
-			delete(remoteTxs, account)
-			localTxs[account] = txs
-		}
-	}
-	log.Debug("execute pending transactions", "localTxCount", len(localTxs), "remoteTxCount", len(remoteTxs), "txsCount", txsCount)
-
+	// Commit the lanes by priority, the system and administrator transactions
+	// first, and within a lane the locals before the remotes
 	startTime = time.Now()
-	if len(localTxs) > 0 {
-		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, localTxs)
-		if ok := w.commitTransactionsWithHeader(header, txs, w.coinbase, interrupt); ok {
-			return
+	locals := w.eth.TxPool().Locals()
+	for _, remoteTxs := range lanes {
+		localTxs := make(map[common.Address]types.Transactions)
+		for _, account := range locals {
+			if txs := remoteTxs[account]; len(txs) > 0 {
+				delete(remoteTxs, account)
+				localTxs[account] = txs
+			}
 		}
-	}
-	if len(remoteTxs) > 0 {
-		txs := types.NewTransactionsByPriceAndNonce(w.current.signer, remoteTxs)
-		if ok := w.commitTransactionsWithHeader(header, txs, w.coinbase, interrupt); ok {
-			return
+		if len(localTxs) > 0 {
+			txs := types.NewTransactionsByPriceAndNonce(w.current.signer, localTxs)
+			if ok := w.commitTransactionsWithHeader(header, txs, w.coinbase, interrupt); ok {
+				return
+			}
+		}
+		if len(remoteTxs) > 0 {
+			txs := types.NewTransactionsByPriceAndNonce(w.current.signer, remoteTxs)
+			if ok := w.commitTransactionsWithHeader(header, txs, w.coinbase, interrupt); ok {
+				return
+			}
 		}
 	}
 	log.Info("commit transaction -------------------", "duration", time.Since(startTime))
@@ -886,6 +957,22 @@ func (w *worker) commitNewWork(interrupt *int32, timestamp int64, commitBlock *t
 	w.commit(w.fullTaskHook, true, tstart)
 }
 
+// packingBudget returns how long the transactions of a block may take to
+// execute: part of the block period, and little enough for the validators to
+// re-execute the block within a consensus round.
+func packingBudget(config *params.IstanbulConfig) time.Duration {
+	if config == nil || config.BlockPeriod == 0 {
+		return 0
+	}
+	budget := time.Duration(config.BlockPeriod) * time.Second * packingBudgetPercent / 100
+	if config.RequestTimeout > 0 {
+		if limit := time.Duration(config.RequestTimeout) * time.Millisecond / 2; budget > limit {
+			budget = limit
+		}
+	}
+	return budget
+}
+
 // commit runs any post-transaction state modifications, assembles the final block
 // and commits new work if consensus engine is running.
 func (w *worker) commit(interval func(), update bool, start time.Time) error {
@@ -941,10 +1028,11 @@ func (w *worker) makePending() (*types.Block, *state.StateDB) {
 	if parent != nil {
 		state, err := w.blockChainCache.MakeStateDB(parent)
 		if err == nil {
-			block := types.NewBlock(
+			block := types.NewBlockWith(
 				parent.Header(),
 				parent.Transactions(),
 				nil,
+				w.systemConfigAt(parent.Header()).IsBlockUseTrieHash(),
 			)
 
 			return block, state
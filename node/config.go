@@ -67,6 +67,11 @@ type Config struct {
 	// in memory.
 	DataDir string
 
+	// DatabaseEngine is the storage engine of the databases created in DataDir.
+	// Existing databases are always opened with the engine they were created
+	// with; an empty engine selects ethdb.DefaultEngine for new ones.
+	DatabaseEngine string `toml:",omitempty"`
+
 	// Configuration of peer-to-peer networking.
 	P2P p2p.Config
 
@@ -62,8 +62,15 @@ type ChainConfig struct {
 	// Various consensus engines
 	Istanbul *IstanbulConfig `json:"istanbul,omitempty"`
 
-	// Various vm interpreter
+	// Various vm interpreter: "wasm", "evm" or "all". With "all" both are
+	// active and every contract runs on the interpreter matching its code,
+	// solidity and wasm contracts call each other with the solidity abi.
 	VMInterpreter string `json:"interpreter,omitempty"`
+
+	// ParallelExecution runs the transactions of a block optimistically in
+	// parallel, re-executing in order the ones that conflict. The resulting
+	// state is the same as with sequential execution.
+	ParallelExecution bool `json:"parallelExecution,omitempty"`
 }
 
 // EthashConfig is the consensus engine configs for proof-of-work based sealing.
@@ -83,6 +90,13 @@ type IstanbulConfig struct {
 	FirstValidatorNode discover.Node  `json:"firstValidatorNode,omitempty"`
 }
 
+// GasBudget returns the gas the transactions of a block may use before its
+// last one starts, 0 for no bound. Half a round converted to gas, it lets every
+// validator re-execute a block within a round, and is the same for all of them.
+func (c *IstanbulConfig) GasBudget() uint64 {
+	return c.RequestTimeout / 2 * GasPerMillisecond
+}
+
 // String implements the fmt.Stringer interface.
 func (c *ChainConfig) String() string {
 	var engine interface{}
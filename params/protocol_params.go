@@ -95,6 +95,9 @@ const (
 	ParamManagerGas   uint64 = 80000 //
 	FireWall          uint64 = 10000
 	CnsInvokeGas      uint64 = 80000 //
+	FeeSponsorGas     uint64 = 80000 //
+
+	GasPerMillisecond uint64 = 1e6 // Gas executed in about a millisecond, the ratio of the bounds of the gas limits
 
 )
 
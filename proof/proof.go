@@ -0,0 +1,121 @@
+// Package proof builds and verifies the merkle proofs of accounts, contract
+// storage, transactions and receipts, so the data served by a single node can
+// be checked against a trusted block hash.
+package proof
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/common/hexutil"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
+	"github.com/PlatONEnetwork/PlatONE-Go/trie"
+)
+
+// AccountResult is the proof of an account and of entries of its storage, as
+// returned by eth_getProof.
+type AccountResult struct {
+	Address      common.Address  `json:"address"`
+	AccountProof []hexutil.Bytes `json:"accountProof"` // State trie nodes, from the root node on
+	Balance      *hexutil.Big    `json:"balance"`
+	CodeHash     common.Hash     `json:"codeHash"`
+	Nonce        hexutil.Uint64  `json:"nonce"`
+	StorageHash  common.Hash     `json:"storageHash"` // Root of the storage trie
+	StorageProof []StorageResult `json:"storageProof"`
+}
+
+// StorageResult is the proof of the value a contract set for a key.
+type StorageResult struct {
+	Key   hexutil.Bytes   `json:"key"` // Key set by the contract
+	Value hexutil.Bytes   `json:"value"`
+	Proof []hexutil.Bytes `json:"proof"` // Storage trie nodes, from the root node on
+}
+
+// InclusionResult is the proof of a transaction or receipt of a block, as
+// returned by eth_getTransactionProof and eth_getReceiptProof.
+//
+// The header of the block holds either the root of a trie of the items, or
+// the flat hash of the encoded list of them when the system configuration of
+// the block disabled trie hashes. In the latter case, the proof is that
+// encoded list.
+type InclusionResult struct {
+	BlockHash common.Hash     `json:"blockHash"`
+	Header    hexutil.Bytes   `json:"header"` // RLP encoding of the header of the block
+	Index     hexutil.Uint    `json:"index"`
+	Item      hexutil.Bytes   `json:"item"` // Consensus RLP encoding of the transaction or receipt
+	TrieHash  bool            `json:"trieHash"`
+	Proof     []hexutil.Bytes `json:"proof"`
+}
+
+// ProveTransaction returns the proof of the transaction at index in the block
+// of header, whose transactions are txs.
+func ProveTransaction(header *types.Header, txs types.Transactions, index int) (*InclusionResult, error) {
+	flat, err := rlp.EncodeToBytes(&types.Body{Transactions: txs})
+	if err != nil {
+		return nil, err
+	}
+	return prove(header, header.TxHash, txs, index, flat)
+}
+
+// ProveReceipt returns the proof of the receipt at index in the block of
+// header, whose receipts are receipts.
+func ProveReceipt(header *types.Header, receipts types.Receipts, index int) (*InclusionResult, error) {
+	flat, err := rlp.EncodeToBytes(receipts)
+	if err != nil {
+		return nil, err
+	}
+	return prove(header, header.ReceiptHash, receipts, index, flat)
+}
+
+// prove proves the item at index of list against root, checking which of the
+// trie root and the flat hash of list it is.
+func prove(header *types.Header, root common.Hash, list types.DerivableList, index int, flat []byte) (*InclusionResult, error) {
+	if index < 0 || index >= list.Len() {
+		return nil, fmt.Errorf("index %d out of range [0, %d)", index, list.Len())
+	}
+	enc, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, err
+	}
+	result := &InclusionResult{
+		BlockHash: header.Hash(),
+		Header:    enc,
+		Index:     hexutil.Uint(index),
+		Item:      list.GetRlp(index),
+	}
+	var (
+		tr  = new(trie.Trie)
+		key = new(bytes.Buffer)
+	)
+	for i := 0; i < list.Len(); i++ {
+		key.Reset()
+		rlp.Encode(key, uint(i))
+		tr.Update(key.Bytes(), list.GetRlp(i))
+	}
+	switch root {
+	case tr.Hash():
+		key.Reset()
+		rlp.Encode(key, uint(index))
+
+		var nodes nodeList
+		if err := tr.Prove(key.Bytes(), 0, &nodes); err != nil {
+			return nil, err
+		}
+		result.TrieHash, result.Proof = true, nodes
+	case list.GetHash():
+		result.Proof = []hexutil.Bytes{flat}
+	default:
+		return nil, fmt.Errorf("root %x of block %d matches neither the trie nor the flat hash of its items", root, header.Number)
+	}
+	return result, nil
+}
+
+// nodeList collects the trie nodes of a proof, from the root node on.
+type nodeList []hexutil.Bytes
+
+func (n *nodeList) Put(key []byte, value []byte) error {
+	*n = append(*n, common.CopyBytes(value))
+	return nil
+}
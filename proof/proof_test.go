@@ -0,0 +1,140 @@
+package proof
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/common/hexutil"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/state"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
+)
+
+// Tests that the proofs of an account and of its storage are verified, and
+// that tampered values are not.
+func TestAccountProof(t *testing.T) {
+	statedb, _ := state.New(common.Hash{}, state.NewDatabase(ethdb.NewMemDatabase()))
+	addr := common.BytesToAddress([]byte("contract"))
+	statedb.SetBalance(addr, big.NewInt(42))
+	statedb.SetNonce(addr, 3)
+	statedb.SetState(addr, []byte("key"), []byte("value"))
+	root, err := statedb.Commit(false)
+	if err != nil {
+		t.Fatalf("commit failed: %v", err)
+	}
+	statedb, _ = state.New(root, statedb.Database())
+
+	prove := func(addr common.Address, keys ...string) *AccountResult {
+		accountProof, err := statedb.GetProof(addr)
+		if err != nil {
+			t.Fatalf("account proof failed: %v", err)
+		}
+		res := &AccountResult{
+			Address:      addr,
+			AccountProof: toHex(accountProof),
+			Balance:      (*hexutil.Big)(statedb.GetBalance(addr)),
+			CodeHash:     statedb.GetCodeHash(addr),
+			Nonce:        hexutil.Uint64(statedb.GetNonce(addr)),
+			StorageHash:  types.EmptyRootHash,
+		}
+		if st := statedb.StorageTrie(addr); st != nil {
+			res.StorageHash = st.Hash()
+		}
+		for _, key := range keys {
+			storageProof, err := statedb.GetStorageProof(addr, []byte(key))
+			if err != nil {
+				t.Fatalf("storage proof failed: %v", err)
+			}
+			res.StorageProof = append(res.StorageProof, StorageResult{
+				Key:   []byte(key),
+				Value: statedb.GetState(addr, []byte(key)),
+				Proof: toHex(storageProof),
+			})
+		}
+		return res
+	}
+	res := prove(addr, "key", "missing")
+	if err := VerifyAccount(root, res); err != nil {
+		t.Fatalf("valid proof rejected: %v", err)
+	}
+	if string(res.StorageProof[0].Value) != "value" || len(res.StorageProof[1].Value) != 0 {
+		t.Fatalf("storage values mismatch: %v", res.StorageProof)
+	}
+	// An absent account is proven too
+	if err := VerifyAccount(root, prove(common.BytesToAddress([]byte("absent")))); err != nil {
+		t.Fatalf("proof of absence rejected: %v", err)
+	}
+	// Tampered fields are rejected
+	res.Balance = (*hexutil.Big)(big.NewInt(43))
+	if err := VerifyAccount(root, res); err == nil {
+		t.Fatal("tampered balance accepted")
+	}
+	res = prove(addr, "key")
+	res.StorageProof[0].Value = []byte("other")
+	if err := VerifyAccount(root, res); err == nil {
+		t.Fatal("tampered storage value accepted")
+	}
+}
+
+// Tests that the transactions and receipts of blocks are proven both against
+// trie roots and against flat hashes of the lists.
+func TestInclusionProof(t *testing.T) {
+	var (
+		txs      types.Transactions
+		receipts types.Receipts
+	)
+	for i := 0; i < 20; i++ {
+		txs = append(txs, types.NewTransaction(uint64(i), common.Address{byte(i)}, big.NewInt(int64(i)), 21000, big.NewInt(1), nil, 0))
+		receipts = append(receipts, &types.Receipt{Status: types.ReceiptStatusSuccessful, CumulativeGasUsed: uint64(i), Logs: []*types.Log{}})
+	}
+	for _, useTrieHash := range []bool{true, false} {
+		header := &types.Header{
+			Number:      big.NewInt(1),
+			TxHash:      types.DeriveShaWith(txs, useTrieHash),
+			ReceiptHash: types.DeriveShaWith(receipts, useTrieHash),
+		}
+		for _, index := range []int{0, 7, 19} {
+			res, err := ProveTransaction(header, txs, index)
+			if err != nil {
+				t.Fatalf("trie %v, tx %d: proof failed: %v", useTrieHash, index, err)
+			}
+			if res.TrieHash != useTrieHash {
+				t.Fatalf("trie %v, tx %d: root kind mismatch", useTrieHash, index)
+			}
+			tx, err := VerifyTransaction(header.Hash(), res)
+			if err != nil {
+				t.Fatalf("trie %v, tx %d: valid proof rejected: %v", useTrieHash, index, err)
+			}
+			if tx.Hash() != txs[index].Hash() {
+				t.Fatalf("trie %v, tx %d: transaction mismatch", useTrieHash, index)
+			}
+			if res, err = ProveReceipt(header, receipts, index); err != nil {
+				t.Fatalf("trie %v, receipt %d: proof failed: %v", useTrieHash, index, err)
+			}
+			receipt, err := VerifyReceipt(header.Hash(), res)
+			if err != nil {
+				t.Fatalf("trie %v, receipt %d: valid proof rejected: %v", useTrieHash, index, err)
+			}
+			if receipt.CumulativeGasUsed != uint64(index) {
+				t.Fatalf("trie %v, receipt %d: receipt mismatch", useTrieHash, index)
+			}
+			// Another item at the index or another block are rejected
+			res.Item = receipts.GetRlp((index + 1) % len(receipts))
+			if _, err := VerifyReceipt(header.Hash(), res); err == nil {
+				t.Fatalf("trie %v, receipt %d: tampered item accepted", useTrieHash, index)
+			}
+			if _, err := VerifyReceipt(common.Hash{1}, res); err != errHeaderMismatch {
+				t.Fatalf("trie %v, receipt %d: header error mismatch: have %v", useTrieHash, index, err)
+			}
+		}
+	}
+}
+
+func toHex(nodes [][]byte) []hexutil.Bytes {
+	result := make([]hexutil.Bytes, len(nodes))
+	for i, node := range nodes {
+		result[i] = node
+	}
+	return result
+}
@@ -0,0 +1,200 @@
+package proof
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/PlatONEnetwork/PlatONE-Go/common"
+	"github.com/PlatONEnetwork/PlatONE-Go/common/hexutil"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/state"
+	"github.com/PlatONEnetwork/PlatONE-Go/core/types"
+	"github.com/PlatONEnetwork/PlatONE-Go/crypto"
+	"github.com/PlatONEnetwork/PlatONE-Go/ethdb"
+	"github.com/PlatONEnetwork/PlatONE-Go/rlp"
+	"github.com/PlatONEnetwork/PlatONE-Go/trie"
+)
+
+var (
+	errHeaderMismatch = errors.New("header doesn't match the block hash")
+	errItemMismatch   = errors.New("proven item doesn't match the item")
+	errFlatMismatch   = errors.New("encoded list doesn't match the root")
+)
+
+// VerifyHeader decodes the RLP encoding of a header and checks that it is the
+// header of the block with the given hash.
+func VerifyHeader(blockHash common.Hash, enc []byte) (*types.Header, error) {
+	header := new(types.Header)
+	if err := rlp.DecodeBytes(enc, header); err != nil {
+		return nil, fmt.Errorf("invalid header: %v", err)
+	}
+	if header.Hash() != blockHash {
+		return nil, errHeaderMismatch
+	}
+	return header, nil
+}
+
+// VerifyAccount checks the proof of an account and of the entries of its
+// storage against the state root of a trusted header.
+func VerifyAccount(stateRoot common.Hash, res *AccountResult) error {
+	enc, _, err := trie.VerifyProof(stateRoot, crypto.Keccak256(res.Address.Bytes()), nodeSet(res.AccountProof))
+	if err != nil {
+		return fmt.Errorf("invalid account proof: %v", err)
+	}
+	account := state.Account{Root: types.EmptyRootHash}
+	if enc != nil {
+		if err := rlp.DecodeBytes(enc, &account); err != nil {
+			return fmt.Errorf("invalid account: %v", err)
+		}
+	}
+	var balance hexutil.Big
+	if res.Balance != nil {
+		balance = *res.Balance
+	}
+	switch {
+	case account.Nonce != uint64(res.Nonce):
+		return fmt.Errorf("nonce mismatch: proven %d, have %d", account.Nonce, res.Nonce)
+	case (account.Balance == nil && balance.ToInt().Sign() != 0) || (account.Balance != nil && account.Balance.Cmp(balance.ToInt()) != 0):
+		return fmt.Errorf("balance mismatch: proven %v, have %v", account.Balance, balance.ToInt())
+	case common.BytesToHash(account.CodeHash) != res.CodeHash:
+		return fmt.Errorf("code hash mismatch: proven %x, have %x", account.CodeHash, res.CodeHash)
+	case account.Root != res.StorageHash:
+		return fmt.Errorf("storage hash mismatch: proven %x, have %x", account.Root, res.StorageHash)
+	}
+	for i := range res.StorageProof {
+		if err := VerifyStorage(res.Address, res.StorageHash, &res.StorageProof[i]); err != nil {
+			return fmt.Errorf("key %x: %v", []byte(res.StorageProof[i].Key), err)
+		}
+	}
+	return nil
+}
+
+// VerifyStorage checks the proof of the value the contract at addr set for a
+// key against the root of its storage trie. An empty value is proven by the
+// absence of the key.
+func VerifyStorage(addr common.Address, storageRoot common.Hash, res *StorageResult) error {
+	enc, _, err := trie.VerifyProof(storageRoot, state.StorageKeyHash(addr, res.Key).Bytes(), nodeSet(res.Proof))
+	if err != nil {
+		return fmt.Errorf("invalid storage proof: %v", err)
+	}
+	if enc == nil {
+		if len(res.Value) != 0 {
+			return fmt.Errorf("value %x of an absent key", []byte(res.Value))
+		}
+		return nil
+	}
+	// The storage trie holds the hash of the value in place of it
+	_, content, _, err := rlp.Split(enc)
+	if err != nil {
+		return fmt.Errorf("invalid storage entry: %v", err)
+	}
+	if have := state.StorageValueHash(res.Value); common.BytesToHash(content) != have {
+		return fmt.Errorf("value hash mismatch: proven %x, have %x", content, have)
+	}
+	return nil
+}
+
+// VerifyTransaction checks the proof of a transaction of the block with the
+// given trusted hash and returns the transaction.
+func VerifyTransaction(blockHash common.Hash, res *InclusionResult) (*types.Transaction, error) {
+	header, err := VerifyHeader(blockHash, res.Header)
+	if err != nil {
+		return nil, err
+	}
+	// The flat hash is of the encoded body, whose first field is the list
+	if err := verifyInclusion(header.TxHash, res, func(flat []byte) ([]byte, error) {
+		content, _, err := rlp.SplitList(flat)
+		if err != nil {
+			return nil, err
+		}
+		return nthItem(content, 0)
+	}); err != nil {
+		return nil, err
+	}
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(res.Item, tx); err != nil {
+		return nil, fmt.Errorf("invalid transaction: %v", err)
+	}
+	return tx, nil
+}
+
+// VerifyReceipt checks the proof of a receipt of the block with the given
+// trusted hash and returns the consensus fields of the receipt.
+func VerifyReceipt(blockHash common.Hash, res *InclusionResult) (*types.Receipt, error) {
+	header, err := VerifyHeader(blockHash, res.Header)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyInclusion(header.ReceiptHash, res, func(flat []byte) ([]byte, error) {
+		return flat, nil
+	}); err != nil {
+		return nil, err
+	}
+	receipt := new(types.Receipt)
+	if err := rlp.DecodeBytes(res.Item, receipt); err != nil {
+		return nil, fmt.Errorf("invalid receipt: %v", err)
+	}
+	return receipt, nil
+}
+
+// verifyInclusion checks that the item of res is at its index of the list
+// whose root is root. list extracts the encoded list from the flat encoding
+// the flat hash is of.
+func verifyInclusion(root common.Hash, res *InclusionResult, list func(flat []byte) ([]byte, error)) error {
+	var item []byte
+	if res.TrieHash {
+		key, _ := rlp.EncodeToBytes(uint(res.Index))
+		value, _, err := trie.VerifyProof(root, key, nodeSet(res.Proof))
+		if err != nil {
+			return fmt.Errorf("invalid inclusion proof: %v", err)
+		}
+		item = value
+	} else {
+		if len(res.Proof) != 1 {
+			return fmt.Errorf("invalid flat proof of %d parts", len(res.Proof))
+		}
+		if crypto.Keccak256Hash(res.Proof[0]) != root {
+			return errFlatMismatch
+		}
+		enc, err := list(res.Proof[0])
+		if err != nil {
+			return fmt.Errorf("invalid encoded list: %v", err)
+		}
+		content, _, err := rlp.SplitList(enc)
+		if err != nil {
+			return fmt.Errorf("invalid encoded list: %v", err)
+		}
+		if item, err = nthItem(content, int(res.Index)); err != nil {
+			return err
+		}
+	}
+	if item == nil || !bytes.Equal(item, res.Item) {
+		return errItemMismatch
+	}
+	return nil
+}
+
+// nthItem returns the encoding of the item at index of the content of an RLP
+// list.
+func nthItem(content []byte, index int) ([]byte, error) {
+	for i := 0; len(content) > 0; i++ {
+		_, _, rest, err := rlp.Split(content)
+		if err != nil {
+			return nil, err
+		}
+		if i == index {
+			return content[:len(content)-len(rest)], nil
+		}
+		content = rest
+	}
+	return nil, fmt.Errorf("index %d out of range", index)
+}
+
+// nodeSet returns a database of the trie nodes of a proof, keyed by hash.
+func nodeSet(nodes []hexutil.Bytes) *ethdb.MemDatabase {
+	db := ethdb.NewMemDatabase()
+	for _, node := range nodes {
+		db.Put(crypto.Keccak256(node), node)
+	}
+	return db
+}